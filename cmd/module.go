@@ -12,6 +12,9 @@ var addModuleCmdForce bool
 var addModuleCmdHashcode int
 var getModulesCmdPath string
 var getModulesCmdExtensions []string
+var getModulesCmdEffective bool
+var getModulesCmdExpandIncludes bool
+var getModulesCmdDir string
 var updateModuleMtaCmdPath string
 var updateModuleCmdData string
 var updateModuleCmdHashcode int
@@ -31,6 +34,12 @@ func init() {
 		"the path to the yaml file")
 	getModulesCmd.Flags().StringSliceVarP(&getModulesCmdExtensions, "extensions", "x", nil,
 		"the paths to the MTA extension descriptors")
+	getModulesCmd.Flags().BoolVar(&getModulesCmdEffective, "effective", false,
+		"return the modules with the properties and parameters which they inherit from their types")
+	getModulesCmd.Flags().BoolVar(&getModulesCmdExpandIncludes, "expand-includes", false,
+		"return the modules with the content of the files in their includes in their parameters")
+	getModulesCmd.Flags().StringVarP(&getModulesCmdDir, "workspace", "w", "",
+		"the path to the project folder, to which the paths of the includes are relative; the default path is the folder of the mta.yaml file")
 
 	updateModuleCmd.Flags().StringVarP(&updateModuleMtaCmdPath, "path", "p", "",
 		"the path to the yaml file")
@@ -64,7 +73,11 @@ var getModulesCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return mta.RunAndWriteResultAndHash("get modules", getModulesCmdPath, getModulesCmdExtensions, func() (interface{}, []string, error) {
-			return mta.GetModules(getModulesCmdPath, getModulesCmdExtensions)
+			return mta.GetModulesWithOptions(getModulesCmdPath, getModulesCmdExtensions, mta.GetOptions{
+				Effective:      getModulesCmdEffective,
+				ExpandIncludes: getModulesCmdExpandIncludes,
+				WorkspaceDir:   getModulesCmdDir,
+			})
 		})
 	},
 	Hidden:        true,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/SAP/cloud-mta/internal/logs"
@@ -11,12 +12,21 @@ import (
 	"github.com/SAP/cloud-mta/validations"
 )
 
+const (
+	wrongFormatMsg      = `the "%s" format is incorrect; expected one of the following: sarif, junit, checkstyle, text`
+	wrongFailOnMsg      = `the "%s" fail-on severity is incorrect; expected one of the following: warning, error`
+	failedValidationMsg = `the validation found %d issues with the "%s" severity or a higher severity`
+)
+
 var createMtaCmdPath string
 var createMtaCmdData string
 var deleteMtaCmdPath string
+var deleteMtaCmdWorkspaceRoot string
 var copyCmdSourcePath string
 var copyCmdTargetPath string
+var copyCmdWorkspaceRoot string
 var deleteFileCmdPath string
+var deleteFileCmdWorkspaceRoot string
 var existCmdName string
 var existCmdPath string
 var getBuildParametersCmdPath string
@@ -33,6 +43,9 @@ var updateParametersCmdHashcode int
 var getMtaIDCmdPath string
 var validateMtaCmdPath string
 var validateMtaCmdExtensions []string
+var validateMtaCmdFormat string
+var validateMtaCmdFailOn string
+var validateMtaCmdFix bool
 
 func init() {
 
@@ -44,14 +57,20 @@ func init() {
 
 	deleteMtaCmd.Flags().StringVarP(&deleteMtaCmdPath, "path", "p", "",
 		"the path to the MTA project")
+	deleteMtaCmd.Flags().StringVar(&deleteMtaCmdWorkspaceRoot, "workspace-root", "",
+		"the workspace root folder; the MTA project is not deleted if it is outside of it")
 
 	copyCmd.Flags().StringVarP(&copyCmdSourcePath, "source", "s", "",
 		"the path to the source file")
 	copyCmd.Flags().StringVarP(&copyCmdTargetPath, "target", "t", "",
 		"the path to the target file")
+	copyCmd.Flags().StringVar(&copyCmdWorkspaceRoot, "workspace-root", "",
+		"the workspace root folder; the file is not copied if the source or the target path is outside of it")
 
 	deleteFileCmd.Flags().StringVarP(&deleteFileCmdPath, "path", "p", "",
 		"the path to the file")
+	deleteFileCmd.Flags().StringVar(&deleteFileCmdWorkspaceRoot, "workspace-root", "",
+		"the workspace root folder; the file is not deleted if it is outside of it")
 
 	existCmd.Flags().StringVarP(&existCmdPath, "path", "p", "",
 		"the path to the file")
@@ -91,6 +110,12 @@ func init() {
 		"the path to the yaml file")
 	validateMtaCmd.Flags().StringSliceVarP(&validateMtaCmdExtensions, "extensions", "x", nil,
 		"the paths to the MTA extension descriptors")
+	validateMtaCmd.Flags().StringVarP(&validateMtaCmdFormat, "format", "f", "",
+		"the report format: sarif, junit, checkstyle or text; by default, the result is written in JSON format")
+	validateMtaCmd.Flags().StringVar(&validateMtaCmdFailOn, "fail-on", "",
+		"the minimal severity of the issues which fail the command: warning or error; by default, the command doesn't fail on issues")
+	validateMtaCmd.Flags().BoolVar(&validateMtaCmdFix, "fix", false,
+		"apply the safe fixes of the issues to the files before the validation; the result contains the remaining issues")
 
 }
 
@@ -118,7 +143,7 @@ var deleteMtaCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logs.Logger.Info("delete MTA in path: " + deleteMtaCmdPath)
-		err := mta.DeleteMta(deleteMtaCmdPath)
+		err := mta.DeleteMtaInWorkspace(deleteMtaCmdWorkspaceRoot, deleteMtaCmdPath)
 		writeErr := mta.WriteResult(nil, nil, 0, err)
 		if err != nil {
 			// The original error is more important
@@ -143,7 +168,7 @@ var copyCmd = &cobra.Command{
 			copyCmdTargetPath,
 			nil, // Extensions are not relevant in copy
 			func() (interface{}, []string, error) {
-				return nil, nil, mta.CopyFile(copyCmdSourcePath, copyCmdTargetPath, os.Create)
+				return nil, nil, mta.CopyFileInWorkspace(copyCmdWorkspaceRoot, copyCmdSourcePath, copyCmdTargetPath, os.Create)
 			},
 		)
 	},
@@ -160,7 +185,7 @@ var deleteFileCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logs.Logger.Info("delete file in path: " + deleteFileCmdPath)
-		err := mta.DeleteFile(deleteFileCmdPath)
+		err := mta.DeleteFileInWorkspace(deleteFileCmdWorkspaceRoot, deleteFileCmdPath)
 		writeErr := mta.WriteResult(nil, nil, 0, err)
 		if err != nil {
 			// The original error is more important
@@ -282,12 +307,63 @@ var validateMtaCmd = &cobra.Command{
 	Long:  "Validate mta.yaml file and MTA extension files",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Extensions don't change the mta ID
-		return mta.RunAndWriteResultAndHash("validate MTA", validateMtaCmdPath, validateMtaCmdExtensions, func() (interface{}, []string, error) {
-			return validate.Validate(validateMtaCmdPath, validateMtaCmdExtensions), nil, nil
-		})
+		if err := checkValidateFlags(); err != nil {
+			return err
+		}
+		if validateMtaCmdFix {
+			if _, err := validate.ApplyFixes(validate.Validate(validateMtaCmdPath, validateMtaCmdExtensions)); err != nil {
+				return err
+			}
+		}
+		var result validate.ValidationResult
+		if validateMtaCmdFormat == "" {
+			// Extensions don't change the mta ID
+			err := mta.RunAndWriteResultAndHash("validate MTA", validateMtaCmdPath, validateMtaCmdExtensions, func() (interface{}, []string, error) {
+				result = validate.Validate(validateMtaCmdPath, validateMtaCmdExtensions)
+				return result, nil, nil
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			// The report is not preceded by a log message, so it can be parsed
+			result = validate.Validate(validateMtaCmdPath, validateMtaCmdExtensions)
+			if err := validate.WriteReport(os.Stdout, result, validateMtaCmdFormat); err != nil {
+				return err
+			}
+		}
+		return getFailOnError(result)
 	},
 	Hidden:        true,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
+
+// checkValidateFlags checks the values of the validate command flags before the validation runs
+func checkValidateFlags() error {
+	switch validateMtaCmdFormat {
+	case "", validate.ReportFormatSarif, validate.ReportFormatJUnit, validate.ReportFormatCheckstyle, validate.ReportFormatText:
+	default:
+		return errors.Errorf(wrongFormatMsg, validateMtaCmdFormat)
+	}
+	switch validateMtaCmdFailOn {
+	case "", validate.SeverityWarning, validate.SeverityError:
+		return nil
+	}
+	return errors.Errorf(wrongFailOnMsg, validateMtaCmdFailOn)
+}
+
+// getFailOnError returns an error if there are issues with the severity of the fail-on flag or a higher severity
+func getFailOnError(result validate.ValidationResult) error {
+	if validateMtaCmdFailOn == "" {
+		return nil
+	}
+	count, err := result.CountIssues(validateMtaCmdFailOn)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.Errorf(failedValidationMsg, count, validateMtaCmdFailOn)
+	}
+	return nil
+}
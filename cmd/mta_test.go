@@ -2,12 +2,15 @@ package commands
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/SAP/cloud-mta/mta"
+	"github.com/SAP/cloud-mta/validations"
 )
 
 var _ = Describe("Resource", func() {
@@ -42,4 +45,105 @@ var _ = Describe("Resource", func() {
 		deleteMtaCmdPath = getTestPath("result")
 		Ω(deleteMtaCmd.RunE(nil, []string{})).Should(Succeed())
 	})
+
+	It("doesn't copy or delete files outside of the workspace root", func() {
+		workspaceRoot := getTestPath("result", "workspace")
+		Ω(os.MkdirAll(workspaceRoot, os.ModePerm)).Should(Succeed())
+		outsideFilePath := getTestPath("result", "outside.txt")
+		Ω(ioutil.WriteFile(outsideFilePath, []byte("outside"), 0644)).Should(Succeed())
+		defer func() {
+			copyCmdWorkspaceRoot = ""
+			deleteFileCmdWorkspaceRoot = ""
+			deleteMtaCmdWorkspaceRoot = ""
+		}()
+
+		copyCmdWorkspaceRoot = workspaceRoot
+		copyCmdSourcePath = outsideFilePath
+		copyCmdTargetPath = getTestPath("result", "workspace", "copy.txt")
+		Ω(copyCmd.RunE(nil, []string{})).Should(HaveOccurred())
+		Ω(copyCmdTargetPath).ShouldNot(BeAnExistingFile())
+
+		deleteFileCmdWorkspaceRoot = workspaceRoot
+		deleteFileCmdPath = outsideFilePath
+		Ω(deleteFileCmd.RunE(nil, []string{})).Should(HaveOccurred())
+		Ω(outsideFilePath).Should(BeAnExistingFile())
+
+		deleteMtaCmdWorkspaceRoot = workspaceRoot
+		deleteMtaCmdPath = getTestPath("result")
+		Ω(deleteMtaCmd.RunE(nil, []string{})).Should(HaveOccurred())
+		Ω(outsideFilePath).Should(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("Validate", func() {
+
+	AfterEach(func() {
+		validateMtaCmdPath = ""
+		validateMtaCmdFormat = ""
+		validateMtaCmdFailOn = ""
+		validateMtaCmdFix = false
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("fails on an unknown format", func() {
+		validateMtaCmdPath = getTestPath("mta.yaml")
+		validateMtaCmdFormat = "html"
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(MatchError(fmt.Sprintf(wrongFormatMsg, "html")))
+	})
+
+	It("fails on an unknown fail-on severity", func() {
+		validateMtaCmdPath = getTestPath("mta.yaml")
+		validateMtaCmdFailOn = "info"
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(MatchError(fmt.Sprintf(wrongFailOnMsg, "info")))
+	})
+
+	It("doesn't fail on issues by default", func() {
+		validateMtaCmdPath = getTestPath("mta.yaml")
+		validateMtaCmdFormat = validate.ReportFormatText
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("fails on the issues with the fail-on severity", func() {
+		validateMtaCmdPath = getTestPath("mta.yaml")
+		result := validate.Validate(validateMtaCmdPath, nil)
+		count, err := result.CountIssues(validate.SeverityError)
+		Ω(err).Should(Succeed())
+		Ω(count).Should(BeNumerically(">", 0))
+
+		validateMtaCmdFailOn = validate.SeverityError
+		validateMtaCmdFormat = validate.ReportFormatCheckstyle
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(MatchError(fmt.Sprintf(failedValidationMsg, count, validate.SeverityError)))
+		validateMtaCmdFormat = validate.ReportFormatSarif
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(MatchError(fmt.Sprintf(failedValidationMsg, count, validate.SeverityError)))
+	})
+
+	It("doesn't fail when there are no issues with the fail-on severity", func() {
+		validateMtaCmdPath = getTestPath("valid", "mta.yaml")
+		validateMtaCmdFailOn = validate.SeverityWarning
+		validateMtaCmdFormat = validate.ReportFormatJUnit
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("applies the safe fixes", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		validateMtaCmdPath = getTestPath("result", "mta.yaml")
+		Ω(ioutil.WriteFile(validateMtaCmdPath, []byte(`ID: fix
+_schema-version: '3.1'
+version: 0.0.1
+
+modules:
+- name: app
+  type: nodejs
+  path: app
+  build-parameters:
+    builder: npm
+    npm-opts:
+      no-optional: null
+`), os.ModePerm)).Should(Succeed())
+		validateMtaCmdFix = true
+		validateMtaCmdFailOn = validate.SeverityWarning
+		validateMtaCmdFormat = validate.ReportFormatText
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(Succeed())
+		Ω(ioutil.ReadFile(validateMtaCmdPath)).Should(HaveSuffix("  build-parameters:\n    builder: npm\n"))
+	})
 })
@@ -1,19 +1,37 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+
 	"github.com/SAP/cloud-mta/internal/logs"
 	"github.com/SAP/cloud-mta/internal/resolver"
 	"github.com/SAP/cloud-mta/mta"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+const (
+	wrongHookFormatMsg  = `could not resolve the "%s" hook; the hook must be in the "<module>/<hook>" format`
+	unresolvedValuesMsg = `could not resolve %d values`
+
+	// parametersOutputPrefix is the prefix of the parameters in the text output, which distinguishes them from the properties
+	parametersOutputPrefix = "parameters."
+)
+
 var resolveCmdPath string
 var resolveCmdExtensions []string
 var resolveCmdWorkspaceDir string
 var resolveCmdModule string
+var resolveCmdResource string
+var resolveCmdHook string
 var resolveCmdEnvFileName string
 var resolveCmdOutputFormat string
+var resolveCmdDeployerFormat bool
+var resolveCmdExplain bool
+var resolveCmdStrict bool
+var resolveCmdExpandIncludes bool
 
 func init() {
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdPath, "path", "p", "",
@@ -24,8 +42,20 @@ func init() {
 		"the path to the project folder; the default path is the folder of the mta.yaml file")
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdModule, "module", "m", "",
 		"the module name")
+	resolveMtaCmd.Flags().StringVarP(&resolveCmdResource, "resource", "r", "",
+		"the resource name; resolves the resource properties and parameters instead of the module properties")
+	resolveMtaCmd.Flags().StringVar(&resolveCmdHook, "hook", "",
+		"the hook, in the \"<module>/<hook>\" format; resolves the hook parameters and the properties available to the hook")
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdEnvFileName, "envFile", "e", "",
-		"the environment file path, relative to the module folder; the default file path is \".env\"")
+		"the environment file path, relative to the module folder (or to the project folder for a resource); the default file path is \".env\"")
+	resolveMtaCmd.Flags().BoolVar(&resolveCmdDeployerFormat, "deployer-format", false,
+		"render structured and non-string values inside strings the way the deployer renders them, instead of as JSON, and resolve the non-string parameters")
+	resolveMtaCmd.Flags().BoolVar(&resolveCmdExplain, "explain", false,
+		"print the resolution trace of each value: the lookups tried for each variable and placeholder, the scope which supplied the value and the environment variable or file it came from")
+	resolveMtaCmd.Flags().BoolVar(&resolveCmdStrict, "strict", false,
+		"fail if a variable or placeholder cannot be resolved or has a cyclic reference, and return the unresolved values as errors")
+	resolveMtaCmd.Flags().BoolVar(&resolveCmdExpandIncludes, "expand-includes", false,
+		"load the files in the includes into the parameters before the resolution, the same as a builder does; the paths are relative to the project folder")
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdOutputFormat, "output", "o", "",
 		"the output format; use \"json\" for json-formatted output")
 	_ = resolveMtaCmd.Flags().MarkHidden("output")
@@ -37,28 +67,43 @@ var resolveMtaCmd = &cobra.Command{
 	Use:   "resolve",
 	Short: "Resolve variables and placeholders in an MTA file",
 	Long: `The MTA file typically contains variables in the form ~{var-name} and placeholders in the form ${placeholder}.
-The resolve command prints the module's properties from the MTA file to stdout, with variables and placeholders replaced with concrete values, based on environment variables and an environment file.`,
+The resolve command prints the module's properties from the MTA file to stdout, with variables and placeholders replaced with concrete values, based on environment variables and an environment file.
+When a resource or a hook is sent instead of a module, its properties and parameters are printed; the names of the parameters are prefixed with "parameters.".
+The configurations matched by a configuration resource required with a list are taken from the "<resource>/configurations" variable, as a JSON array of property sets.
+Use the --explain flag to get the resolution trace of each value as JSON.
+Use the --strict flag to fail when a value cannot be resolved; the unresolved values are returned as errors.
+Use the --expand-includes flag to resolve the values with the parameters loaded from the files in the includes.
+Structured and non-string values inside strings are rendered as JSON; use the --deployer-format flag to render them the same as the deployer renders them (e.g. "{field=abc}").
+The non-string parameters are resolved only with the --deployer-format flag.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if resolveCmdOutputFormat == "json" {
-			return mta.RunAndWriteResultAndHash(
+			var result resolver.ResolveResult
+			err := mta.RunAndWriteResultAndHash(
 				"Resolve MTA",
 				resolveCmdPath,
 				resolveCmdExtensions,
 				func() (interface{}, []string, error) {
-					return resolver.Resolve(resolveCmdWorkspaceDir, resolveCmdModule, resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName)
+					var messages []string
+					var err error
+					result, messages, err = resolveEntity()
+					return result, messages, err
 				},
 			)
+			if err != nil {
+				return err
+			}
+			return getStrictError(result)
 		}
 
 		// Just write to the output (this option is here for backwards compatibility)
 		logs.Logger.Info("Resolve MTA")
-		result, messages, err := resolver.Resolve(resolveCmdWorkspaceDir, resolveCmdModule, resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName)
+		result, messages, err := resolveEntity()
 		if err != nil {
 			logs.Logger.Error(err)
 		} else {
-			for key, val := range result.Properties {
-				fmt.Println(key + "=" + val)
+			for _, line := range getResultLines(result) {
+				fmt.Println(line)
 			}
 			for _, message := range messages {
 				logs.Logger.Warn(message)
@@ -66,6 +111,15 @@ The resolve command prints the module's properties from the MTA file to stdout,
 			for _, message := range result.Messages {
 				logs.Logger.Warn(message)
 			}
+			for _, resolveError := range result.Errors {
+				logs.Logger.Error(resolveError.Message)
+			}
+			if resolveCmdExplain {
+				err = printTrace(result.Trace)
+			}
+			if err == nil {
+				err = getStrictError(result)
+			}
 		}
 		return err
 	},
@@ -73,3 +127,53 @@ The resolve command prints the module's properties from the MTA file to stdout,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
+
+func getResolveOptions() resolver.Options {
+	return resolver.Options{DeployerFormat: resolveCmdDeployerFormat, Explain: resolveCmdExplain, Strict: resolveCmdStrict,
+		ExpandIncludes: resolveCmdExpandIncludes}
+}
+
+// getResultLines returns the "key=value" lines of the properties and then of the parameters, with the parameters prefix
+func getResultLines(result resolver.ResolveResult) []string {
+	var lines []string
+	for key, val := range result.Properties {
+		lines = append(lines, key+"="+val)
+	}
+	for key, val := range result.Parameters {
+		lines = append(lines, parametersOutputPrefix+key+"="+val)
+	}
+	return lines
+}
+
+// getStrictError returns an error if there are unresolved values (they are returned only in strict mode)
+func getStrictError(result resolver.ResolveResult) error {
+	if len(result.Errors) > 0 {
+		return errors.Errorf(unresolvedValuesMsg, len(result.Errors))
+	}
+	return nil
+}
+
+// printTrace prints the resolution trace as JSON
+func printTrace(trace []*resolver.ValueTrace) error {
+	traceBytes, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(traceBytes))
+	return nil
+}
+
+// resolveEntity resolves the resource or hook if sent, and the module otherwise
+func resolveEntity() (resolver.ResolveResult, []string, error) {
+	if len(resolveCmdResource) > 0 {
+		return resolver.ResolveResource(resolveCmdWorkspaceDir, resolveCmdResource, resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName, getResolveOptions())
+	}
+	if len(resolveCmdHook) > 0 {
+		parts := strings.SplitN(resolveCmdHook, "/", 2)
+		if len(parts) != 2 {
+			return resolver.ResolveResult{}, nil, errors.Errorf(wrongHookFormatMsg, resolveCmdHook)
+		}
+		return resolver.ResolveHook(resolveCmdWorkspaceDir, parts[0], parts[1], resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName, getResolveOptions())
+	}
+	return resolver.Resolve(resolveCmdWorkspaceDir, resolveCmdModule, resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName, getResolveOptions())
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/resolver"
+)
+
+var _ = Describe("Resolve", func() {
+
+	AfterEach(func() {
+		resolveCmdPath = ""
+		resolveCmdModule = ""
+		resolveCmdResource = ""
+		resolveCmdHook = ""
+		resolveCmdStrict = false
+	})
+
+	It("resolves the resource when a resource is sent", func() {
+		resolveCmdPath = getTestPath("mta.yaml")
+		resolveCmdResource = "doesNotExist"
+		_, _, err := resolveEntity()
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(`"doesNotExist" resource`))
+	})
+
+	It("resolves the hook when a hook is sent", func() {
+		resolveCmdPath = getTestPath("mta.yaml")
+		resolveCmdHook = "backend/doesNotExist"
+		_, _, err := resolveEntity()
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(`"doesNotExist" hook in the "backend" module`))
+	})
+
+	It("fails when the hook is not in the module/hook format", func() {
+		resolveCmdPath = getTestPath("mta.yaml")
+		resolveCmdHook = "backend"
+		_, _, err := resolveEntity()
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(fmt.Sprintf(wrongHookFormatMsg, "backend")))
+	})
+
+	It("returns the unresolved values in strict mode", func() {
+		resolveCmdPath = getTestPath("mta.yaml")
+		resolveCmdResource = "plugins"
+		resolveCmdStrict = true
+		result, _, err := resolveEntity()
+		Ω(err).Should(Succeed())
+		Ω(result.Errors).ShouldNot(BeEmpty())
+		Ω(getStrictError(result)).Should(MatchError(fmt.Sprintf(unresolvedValuesMsg, len(result.Errors))))
+	})
+
+	It("doesn't return the unresolved values when not in strict mode", func() {
+		resolveCmdPath = getTestPath("mta.yaml")
+		resolveCmdResource = "plugins"
+		result, _, err := resolveEntity()
+		Ω(err).Should(Succeed())
+		Ω(result.Errors).Should(BeEmpty())
+		Ω(getStrictError(result)).Should(Succeed())
+	})
+
+	It("prints the parameters with a prefix", func() {
+		lines := getResultLines(resolver.ResolveResult{
+			Properties: map[string]string{"name": "abc"},
+			Parameters: map[string]string{"name": "def", "memory": "256M"},
+		})
+		Ω(lines).Should(HaveLen(3))
+		Ω(lines[0]).Should(Equal("name=abc"))
+		Ω(lines[1:]).Should(ConsistOf("parameters.name=def", "parameters.memory=256M"))
+	})
+})
@@ -1,17 +1,27 @@
 package commands
 
 import (
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/SAP/cloud-mta/internal/resolver"
 	"github.com/SAP/cloud-mta/mta"
 )
 
+const (
+	resourceConfigTargetMsg = "provide either the name of a resource or the --all flag"
+)
+
 var addResourceCmdPath string
 var addResourceCmdData string
 var addResourceCmdForce bool
 var addResourceCmdHashcode int
 var getResourcesCmdPath string
 var getResourcesCmdExtensions []string
+var getResourcesCmdEffective bool
+var getResourcesCmdExpandIncludes bool
+var getResourcesCmdDir string
+var getResourcesCmdActiveState bool
 var updateResourceMtaCmdPath string
 var updateResourceCmdData string
 var updateResourceCmdHashcode int
@@ -19,6 +29,10 @@ var getResourceConfigCmdPath string
 var getResourceConfigCmdExtensions []string
 var getResourceConfigCmdName string
 var getResourceConfigCmdDir string
+var getResourceConfigCmdMerge string
+var getResourceConfigCmdAll bool
+var getResourceConfigCmdResolve bool
+var getResourceConfigCmdEnvFile string
 
 func init() {
 	// set flags of commands
@@ -35,6 +49,14 @@ func init() {
 		"the path to the yaml file")
 	getResourcesCmd.Flags().StringSliceVarP(&getResourcesCmdExtensions, "extensions", "x", nil,
 		"the paths to the MTA extension descriptors")
+	getResourcesCmd.Flags().BoolVar(&getResourcesCmdEffective, "effective", false,
+		"return the resources with the properties and parameters which they inherit from their types")
+	getResourcesCmd.Flags().BoolVar(&getResourcesCmdExpandIncludes, "expand-includes", false,
+		"return the resources with the content of the files in their includes in their parameters")
+	getResourcesCmd.Flags().StringVarP(&getResourcesCmdDir, "workspace", "w", "",
+		"the path to the project folder, to which the paths of the includes are relative; the default path is the folder of the mta.yaml file")
+	getResourcesCmd.Flags().BoolVar(&getResourcesCmdActiveState, "active-state", false,
+		"return the resources with their active state after the extensions are merged, also when it is not set")
 
 	updateResourceCmd.Flags().StringVarP(&updateResourceMtaCmdPath, "path", "p", "",
 		"the path to the yaml file")
@@ -51,6 +73,14 @@ func init() {
 		"the path to the project folder; the default path is the folder of the mta.yaml file")
 	getResourceConfigCmd.Flags().StringVarP(&getResourceConfigCmdName, "resource", "r", "",
 		"the resource name")
+	getResourceConfigCmd.Flags().StringVar(&getResourceConfigCmdMerge, "merge", string(mta.ShallowMerge),
+		"the way the config parameter is merged over the file in the path parameter: \"shallow\" (only the top-level keys, the same as the deployer) or \"deep\"")
+	getResourceConfigCmd.Flags().BoolVar(&getResourceConfigCmdAll, "all", false,
+		"return the configurations of all the active resources by their names")
+	getResourceConfigCmd.Flags().BoolVar(&getResourceConfigCmdResolve, "resolve", false,
+		"resolve the variables and placeholders in the configuration, based on environment variables and an environment file")
+	getResourceConfigCmd.Flags().StringVarP(&getResourceConfigCmdEnvFile, "envFile", "e", "",
+		"the environment file path for the resolution, relative to the project folder; the default file path is \".env\"")
 }
 
 // addResourceCmd - adds a new resource.
@@ -77,7 +107,12 @@ var getResourcesCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return mta.RunAndWriteResultAndHash("get resources", getResourcesCmdPath, getResourcesCmdExtensions, func() (interface{}, []string, error) {
-			return mta.GetResources(getResourcesCmdPath, getResourcesCmdExtensions)
+			return mta.GetResourcesWithOptions(getResourcesCmdPath, getResourcesCmdExtensions, mta.GetOptions{
+				Effective:      getResourcesCmdEffective,
+				ExpandIncludes: getResourcesCmdExpandIncludes,
+				WorkspaceDir:   getResourcesCmdDir,
+				ActiveState:    getResourcesCmdActiveState,
+			})
 		})
 	},
 	Hidden:        true,
@@ -104,14 +139,46 @@ var updateResourceCmd = &cobra.Command{
 var getResourceConfigCmd = &cobra.Command{
 	Use:   "resource-config",
 	Short: "Get resource configuration",
-	Long:  "Get resource configuration, which is used when creating the service",
-	Args:  cobra.NoArgs,
+	Long: `Get resource configuration, which is used when creating the service.
+The config parameter is merged over the JSON file in the path parameter; use the --merge flag to choose a shallow merge (the default, the same as the deployer) or a deep merge.
+Use the --all flag to get the configurations of all the active resources, and the --resolve flag to resolve the variables and placeholders in them.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return mta.RunAndWriteResultAndHash("get resource config", getResourceConfigCmdPath, getResourceConfigCmdExtensions, func() (interface{}, []string, error) {
-			return mta.GetResourceConfig(getResourceConfigCmdPath, getResourceConfigCmdExtensions, getResourceConfigCmdName, getResourceConfigCmdDir)
+			return getResourceConfig(getResourceConfigCmdPath, getResourceConfigCmdExtensions, getResourceConfigCmdName, getResourceConfigCmdDir,
+				mta.MergeStrategy(getResourceConfigCmdMerge), getResourceConfigCmdAll, getResourceConfigCmdResolve, getResourceConfigCmdEnvFile)
 		})
 	},
 	Hidden:        true,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
+
+// getResourceConfig returns the configuration of the resource, or the configurations of all the active resources by
+// their names, optionally with the variables and placeholders resolved
+func getResourceConfig(path string, extensions []string, resourceName string, workspaceDir string,
+	strategy mta.MergeStrategy, all bool, resolve bool, envFile string) (interface{}, []string, error) {
+	if all == (resourceName != "") {
+		return nil, nil, errors.New(resourceConfigTargetMsg)
+	}
+	if !resolve {
+		if all {
+			return mta.GetResourceConfigs(path, extensions, workspaceDir, strategy)
+		}
+		return mta.GetResourceConfigWithStrategy(path, extensions, resourceName, workspaceDir, strategy)
+	}
+
+	var resourceNames []string
+	if !all {
+		resourceNames = []string{resourceName}
+	}
+	result, messages, err := resolver.ResolveResourceConfigs(workspaceDir, resourceNames, path, extensions, envFile, strategy, resolver.Options{})
+	if err != nil {
+		return nil, messages, err
+	}
+	messages = append(messages, result.Messages...)
+	if all {
+		return result.Configs, messages, nil
+	}
+	return result.Configs[resourceName], messages, nil
+}
@@ -42,4 +42,28 @@ var _ = Describe("Resource", func() {
 		// hashcode of the mta.yaml is wrong now
 		Ω(addResourceCmd.RunE(nil, []string{})).Should(HaveOccurred())
 	})
+
+	Describe("getResourceConfig", func() {
+		It("returns the configurations of all the active resources", func() {
+			configs, _, err := getResourceConfig(getTestPath("mta.yaml"), nil, "", "", mta.ShallowMerge, true, false, "")
+			Ω(err).Should(Succeed())
+			Ω(configs).Should(Equal(map[string]map[string]interface{}{"database": {}}))
+		})
+
+		It("returns the resolved configuration of the resource", func() {
+			config, _, err := getResourceConfig(getTestPath("mta.yaml"), nil, "database", "", mta.DeepMerge, false, true, "")
+			Ω(err).Should(Succeed())
+			Ω(config).Should(Equal(map[string]interface{}{}))
+		})
+
+		It("fails when both a resource and the all flag are sent", func() {
+			_, _, err := getResourceConfig(getTestPath("mta.yaml"), nil, "database", "", mta.ShallowMerge, true, false, "")
+			Ω(err).Should(MatchError(resourceConfigTargetMsg))
+		})
+
+		It("fails when neither a resource nor the all flag are sent", func() {
+			_, _, err := getResourceConfig(getTestPath("mta.yaml"), nil, "", "", mta.ShallowMerge, false, true, "")
+			Ω(err).Should(MatchError(resourceConfigTargetMsg))
+		})
+	})
 })
@@ -0,0 +1,240 @@
+// Package expression parses the MTA expressions in string values: variables in the form ~{name}
+// and placeholders in the form ${name}.
+//
+// An expression can be escaped with a backslash: \~{name} and \${name} are kept as the literal text ~{name} and ${name}.
+// An expression in double braces (e.g. ~{{name}}) is parsed with the inner braces as part of its name.
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a node in a parsed value
+type Kind int
+
+const (
+	// Text is literal text
+	Text Kind = iota
+	// Variable is an expression in the form ~{name}
+	Variable
+	// Placeholder is an expression in the form ${name}
+	Placeholder
+)
+
+const (
+	// VariablePrefix is the prefix of a variable expression
+	VariablePrefix = "~"
+	// PlaceholderPrefix is the prefix of a placeholder expression
+	PlaceholderPrefix = "$"
+
+	escapeChar = '\\'
+)
+
+const (
+	unterminatedExprMsg = `the "%s" expression is not terminated; add "%s" at the end of the expression`
+	nestedExprMsg       = `the "%s" expression is nested in another expression; nested expressions are not supported`
+	emptyExprMsg        = `the "%s" expression is empty`
+)
+
+// Node is a part of a parsed value: literal text or an expression.
+// Start and End are the byte offsets of the node in the value, including the expression prefix and braces
+// and the escape characters.
+type Node struct {
+	Kind Kind
+	// Value is the text without the escape characters for a text node, and the expression name for an expression
+	Value string
+	Start int
+	End   int
+	// DoubleBraced is true for an expression in double braces, e.g. ~{{name}}
+	DoubleBraced bool
+}
+
+// Error is a malformed expression in a value
+type Error struct {
+	Message string
+	// Offset is the byte offset of the malformed expression in the value
+	Offset int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Value is a parsed value
+type Value struct {
+	Source string
+	Nodes  []Node
+	Errors []*Error
+}
+
+// Parse parses the expressions in the value. A malformed expression is kept as text and reported in the errors.
+func Parse(value string) *Value {
+	p := &parser{value: value, result: &Value{Source: value}, textStart: -1}
+	p.parse()
+	return p.result
+}
+
+// Raw returns the source text of the node, including the expression prefix and braces and the escape characters
+func (v *Value) Raw(node Node) string {
+	return v.Source[node.Start:node.End]
+}
+
+// WholeExpression returns the expression of the kind if it is the whole value
+func (v *Value) WholeExpression(kind Kind) (Node, bool) {
+	if len(v.Nodes) == 1 && v.Nodes[0].Kind == kind && v.Nodes[0].Start == 0 && v.Nodes[0].End == len(v.Source) {
+		return v.Nodes[0], true
+	}
+	return Node{}, false
+}
+
+// Expressions returns the expressions of the kind
+func (v *Value) Expressions(kind Kind) []Node {
+	var result []Node
+	for _, node := range v.Nodes {
+		if node.Kind == kind {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// HasExpressions returns true if the value has an expression of the kind
+func (v *Value) HasExpressions(kind Kind) bool {
+	for _, node := range v.Nodes {
+		if node.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// LineColumn returns the zero-based line and column (in characters) of the byte offset in the value
+func LineColumn(value string, offset int) (int, int) {
+	line := strings.Count(value[:offset], "\n")
+	lineStart := strings.LastIndex(value[:offset], "\n") + 1
+	return line, utf8.RuneCountInString(value[lineStart:offset])
+}
+
+type parser struct {
+	value     string
+	result    *Value
+	text      strings.Builder
+	textStart int
+}
+
+func (p *parser) parse() {
+	pos := 0
+	for pos < len(p.value) {
+		if p.value[pos] == escapeChar && getPrefix(p.value, pos+1) != "" {
+			// escaped expression start: keep the prefix and the brace as text
+			p.addText(pos, p.value[pos+1:pos+3])
+			pos += 3
+			continue
+		}
+		if getPrefix(p.value, pos) != "" {
+			node, ok := p.parseExpression(pos)
+			if ok {
+				p.flushText(pos)
+				p.result.Nodes = append(p.result.Nodes, node)
+				pos = node.End
+				continue
+			}
+			// malformed expression start: keep it as text and parse the rest of the value
+			p.addText(pos, p.value[pos:pos+2])
+			pos += 2
+			continue
+		}
+		p.addText(pos, p.value[pos:pos+1])
+		pos++
+	}
+	p.flushText(len(p.value))
+}
+
+func (p *parser) addText(pos int, text string) {
+	if p.textStart < 0 {
+		p.textStart = pos
+	}
+	p.text.WriteString(text)
+}
+
+func (p *parser) flushText(end int) {
+	if p.textStart < 0 {
+		return
+	}
+	p.result.Nodes = append(p.result.Nodes, Node{Kind: Text, Value: p.text.String(), Start: p.textStart, End: end})
+	p.text.Reset()
+	p.textStart = -1
+}
+
+func (p *parser) addError(offset int, format string, args ...interface{}) {
+	p.result.Errors = append(p.result.Errors, &Error{Message: fmt.Sprintf(format, args...), Offset: offset})
+}
+
+// parseExpression parses the expression which starts at the position, and returns false if it is malformed
+func (p *parser) parseExpression(start int) (Node, bool) {
+	prefix := getPrefix(p.value, start)
+	kind := Variable
+	if prefix == PlaceholderPrefix {
+		kind = Placeholder
+	}
+
+	if start+2 < len(p.value) && p.value[start+2] == '{' {
+		end := strings.Index(p.value[start+3:], "}}")
+		if end < 0 {
+			p.addError(start, unterminatedExprMsg, firstLine(p.value[start:]), "}}")
+			return Node{}, false
+		}
+		end += start + 3 + 2
+		return Node{Kind: kind, Value: p.value[start+2 : end-1], Start: start, End: end, DoubleBraced: true}, true
+	}
+
+	for pos := start + 2; pos < len(p.value); pos++ {
+		if p.value[pos] == '}' {
+			if pos == start+2 {
+				p.addError(start, emptyExprMsg, p.value[start:pos+1])
+				return Node{}, false
+			}
+			return Node{Kind: kind, Value: p.value[start+2 : pos], Start: start, End: pos + 1}, true
+		}
+		if getPrefix(p.value, pos) != "" {
+			p.addError(pos, nestedExprMsg, p.value[pos:getExpressionEnd(p.value, pos)])
+			return Node{}, false
+		}
+	}
+	p.addError(start, unterminatedExprMsg, firstLine(p.value[start:]), "}")
+	return Node{}, false
+}
+
+// getPrefix returns the prefix of the expression which starts at the position, or an empty string if no expression starts there
+func getPrefix(value string, pos int) string {
+	if pos+1 >= len(value) || value[pos+1] != '{' {
+		return ""
+	}
+	switch value[pos : pos+1] {
+	case VariablePrefix:
+		return VariablePrefix
+	case PlaceholderPrefix:
+		return PlaceholderPrefix
+	}
+	return ""
+}
+
+// getExpressionEnd returns the end of the expression which starts at the position, for the error messages
+func getExpressionEnd(value string, pos int) int {
+	end := strings.Index(value[pos:], "}")
+	if end < 0 {
+		return len(value)
+	}
+	return pos + end + 1
+}
+
+// firstLine returns the text until the end of the line, for the error messages
+func firstLine(text string) string {
+	end := strings.Index(text, "\n")
+	if end < 0 {
+		return text
+	}
+	return text[:end]
+}
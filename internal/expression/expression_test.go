@@ -0,0 +1,110 @@
+package expression
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parse", func() {
+	It("parses text without expressions", func() {
+		value := Parse("abc")
+		Ω(value.Nodes).Should(Equal([]Node{{Kind: Text, Value: "abc", Start: 0, End: 3}}))
+		Ω(value.Errors).Should(BeEmpty())
+	})
+
+	It("parses an empty value", func() {
+		value := Parse("")
+		Ω(value.Nodes).Should(BeEmpty())
+		Ω(value.Errors).Should(BeEmpty())
+	})
+
+	It("parses variables and placeholders with their offsets", func() {
+		value := Parse("~{db/url}:${port}/path")
+		Ω(value.Nodes).Should(Equal([]Node{
+			{Kind: Variable, Value: "db/url", Start: 0, End: 9},
+			{Kind: Text, Value: ":", Start: 9, End: 10},
+			{Kind: Placeholder, Value: "port", Start: 10, End: 17},
+			{Kind: Text, Value: "/path", Start: 17, End: 22},
+		}))
+		Ω(value.Errors).Should(BeEmpty())
+		Ω(value.Raw(value.Nodes[2])).Should(Equal("${port}"))
+		Ω(value.Expressions(Variable)).Should(Equal([]Node{{Kind: Variable, Value: "db/url", Start: 0, End: 9}}))
+		Ω(value.HasExpressions(Placeholder)).Should(BeTrue())
+		_, whole := value.WholeExpression(Variable)
+		Ω(whole).Should(BeFalse())
+	})
+
+	It("parses a whole expression", func() {
+		value := Parse("${port}")
+		node, whole := value.WholeExpression(Placeholder)
+		Ω(whole).Should(BeTrue())
+		Ω(node.Value).Should(Equal("port"))
+		_, whole = value.WholeExpression(Variable)
+		Ω(whole).Should(BeFalse())
+	})
+
+	It("parses an expression in double braces", func() {
+		value := Parse("a ~{{var1}}")
+		Ω(value.Nodes).Should(Equal([]Node{
+			{Kind: Text, Value: "a ", Start: 0, End: 2},
+			{Kind: Variable, Value: "{var1}", Start: 2, End: 11, DoubleBraced: true},
+		}))
+		Ω(value.Errors).Should(BeEmpty())
+	})
+
+	It("keeps escaped expressions as text", func() {
+		value := Parse(`echo \${HOME} \~{a/b} ${c}`)
+		Ω(value.Nodes).Should(Equal([]Node{
+			{Kind: Text, Value: "echo ${HOME} ~{a/b} ", Start: 0, End: 22},
+			{Kind: Placeholder, Value: "c", Start: 22, End: 26},
+		}))
+		Ω(value.Raw(value.Nodes[0])).Should(Equal(`echo \${HOME} \~{a/b} `))
+		Ω(value.Errors).Should(BeEmpty())
+	})
+
+	It("keeps a backslash which does not escape an expression", func() {
+		value := Parse(`a\b\$c`)
+		Ω(value.Nodes).Should(Equal([]Node{{Kind: Text, Value: `a\b\$c`, Start: 0, End: 6}}))
+	})
+
+	DescribeTable("reports malformed expressions", func(value string, offset int, message string, nodes []Node) {
+		parsed := Parse(value)
+		Ω(parsed.Errors).Should(Equal([]*Error{{Message: message, Offset: offset}}))
+		Ω(parsed.Nodes).Should(Equal(nodes))
+	},
+		Entry("expression start at the end of the value", "abc ~{", 4, fmt.Sprintf(unterminatedExprMsg, "~{", "}"),
+			[]Node{{Kind: Text, Value: "abc ~{", Start: 0, End: 6}}),
+		Entry("unterminated expression", "a ${b c", 2, fmt.Sprintf(unterminatedExprMsg, "${b c", "}"),
+			[]Node{{Kind: Text, Value: "a ${b c", Start: 0, End: 7}}),
+		Entry("unterminated expression in double braces", "~{{b}", 0, fmt.Sprintf(unterminatedExprMsg, "~{{b}", "}}"),
+			[]Node{{Kind: Text, Value: "~{{b}", Start: 0, End: 5}}),
+		Entry("empty expression", "a${}", 1, fmt.Sprintf(emptyExprMsg, "${}"),
+			[]Node{{Kind: Text, Value: "a${}", Start: 0, End: 4}}),
+		Entry("nested expression", "~{a${b}}", 3, fmt.Sprintf(nestedExprMsg, "${b}"), []Node{
+			{Kind: Text, Value: "~{a", Start: 0, End: 3},
+			{Kind: Placeholder, Value: "b", Start: 3, End: 7},
+			{Kind: Text, Value: "}", Start: 7, End: 8},
+		}),
+	)
+
+	It("returns the error message", func() {
+		parsed := Parse("${a")
+		Ω(parsed.Errors[0].Error()).Should(Equal(fmt.Sprintf(unterminatedExprMsg, "${a", "}")))
+	})
+})
+
+var _ = Describe("LineColumn", func() {
+	It("returns the line and column of the offset", func() {
+		line, column := LineColumn("ab\ncäd ${x}", 8)
+		Ω(line).Should(Equal(1))
+		Ω(column).Should(Equal(4))
+	})
+	It("returns the position in the first line", func() {
+		line, column := LineColumn("ab ${x}", 3)
+		Ω(line).Should(Equal(0))
+		Ω(column).Should(Equal(3))
+	})
+})
@@ -59,3 +59,47 @@ func GetJSONContent(path string) (map[string]interface{}, error) {
 	}
 	return fileConfig, nil
 }
+
+// ResolvePath returns the absolute path with its symbolic links resolved. If the path doesn't exist, the symbolic links
+// in its longest existing parent folder are resolved, so the path of a file which is not created yet can be resolved too.
+func ResolvePath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	current := absPath
+	for {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return absPath, nil
+		}
+		missing = append([]string{filepath.Base(current)}, missing...)
+		current = parent
+	}
+}
+
+// IsInFolder returns true if the path is the folder or is in it, after the symbolic links in both paths are resolved
+func IsInFolder(folder string, path string) (bool, error) {
+	resolvedFolder, err := ResolvePath(folder)
+	if err != nil {
+		return false, err
+	}
+	resolvedPath, err := ResolvePath(path)
+	if err != nil {
+		return false, err
+	}
+	relPath, err := filepath.Rel(resolvedFolder, resolvedPath)
+	if err != nil {
+		// The paths are on different volumes
+		return false, nil
+	}
+	return relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)), nil
+}
@@ -10,17 +10,35 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 
+	"github.com/SAP/cloud-mta/internal/expression"
 	"github.com/SAP/cloud-mta/internal/logs"
 	"github.com/SAP/cloud-mta/mta"
 )
 
 const (
-	emptyModuleNameMsg = "provide a name for the module"
-	moduleNotFoundMsg  = `could not find the "%s" module`
-	marshalFailsMag    = `could not marshal the "%s" environment variable`
-	missingPrefixMsg   = `could not resolve the value for the "~{%s}" variable; missing required prefix`
+	emptyModuleNameMsg     = "provide a name for the module"
+	emptyResourceNameMsg   = "provide a name for the resource"
+	emptyHookNameMsg       = "provide a name for the hook"
+	moduleNotFoundMsg      = `could not find the "%s" module`
+	resourceNotFoundMsg    = `could not find the "%s" resource`
+	hookNotFoundMsg        = `could not find the "%s" hook in the "%s" module`
+	marshalFailsMag        = `could not marshal the "%s" environment variable`
+	missingPrefixMsg       = `could not resolve the value for the "~{%s}" variable; missing required prefix`
+	cyclicReferenceMsg     = `could not resolve the value for the "%s" expression; cyclic reference: %s`
+	unresolvedVariableMsg  = `could not resolve the value for the "%s" variable`
+	missingParameterMsg    = `could not resolve the value for the "%s" placeholder`
+	wrongConfigurationsMsg = `could not parse the configurations of the "%s" resource; the "%s" variable must be a JSON array of objects`
+	ignoredRequiresMsg     = `the "%s" resource required by "%s" is not active; the requires section is ignored`
+	inactiveResourceMsg    = `the "%s" resource is not active; the deployer does not create it`
 
 	defaultEnvFileName = ".env"
+
+	propertiesSection = "properties"
+	parametersSection = "parameters"
+	configSection     = "config"
+
+	configurationType        = "configuration"
+	configurationsContextKey = "configurations"
 )
 
 var envGetter = os.Environ
@@ -28,33 +46,70 @@ var envGetter = os.Environ
 // ResolveResult is the result of the Resolve function. This is serialized to json when requested.
 type ResolveResult struct {
 	Properties map[string]string `json:"properties"`
+	Parameters map[string]string `json:"parameters,omitempty"`
 	Messages   []string          `json:"messages"`
+	// Trace is the resolution trace of each resolved value; it is returned only in explain mode
+	Trace []*ValueTrace `json:"trace,omitempty"`
+	// Errors are the unresolved variables and placeholders and the cyclic references; they are returned only in strict mode
+	Errors []*ResolveError `json:"errors,omitempty"`
+}
+
+// ResolveConfigsResult is the result of the ResolveResourceConfigs function. This is serialized to json when requested.
+type ResolveConfigsResult struct {
+	// Configs are the resolved configurations (service creation parameters) by the names of the resources
+	Configs  map[string]map[string]interface{} `json:"configs"`
+	Messages []string                          `json:"messages"`
+	// Trace is the resolution trace of each resolved value; it is returned only in explain mode
+	Trace []*ValueTrace `json:"trace,omitempty"`
+	// Errors are the unresolved variables and placeholders and the cyclic references; they are returned only in strict mode
+	Errors []*ResolveError `json:"errors,omitempty"`
+}
+
+// Kinds of resolve errors
+const (
+	UnresolvedVariableError    = "unresolvedVariable"
+	UnresolvedPlaceholderError = "unresolvedPlaceholder"
+	MissingConfigurationError  = "missingConfiguration"
+	CyclicReferenceError       = "cyclicReference"
+)
+
+// ResolveError is a variable or placeholder which could not be resolved in a value
+type ResolveError struct {
+	ValueLocation
+	Kind string `json:"kind"`
+	// Expression is the unresolved variable or placeholder
+	Expression string `json:"expression"`
+	// Cycle is the chain of references which leads back to the first one, for a cyclic reference
+	Cycle   []string `json:"cycle,omitempty"`
+	Message string   `json:"message"`
+}
+
+// Options are the options of the resolution
+type Options struct {
+	// DeployerFormat renders structured and non-string values inside strings the way the deployer renders them,
+	// instead of as JSON, and resolves the non-string parameters, which are ignored otherwise
+	DeployerFormat bool
+	// Explain returns the resolution trace of each resolved value
+	Explain bool
+	// Strict returns the unresolved variables and placeholders as errors
+	Strict bool
+	// ExpandIncludes loads the files in the includes into the parameters before the resolution, the same as a builder does
+	ExpandIncludes bool
 }
 
 // Resolve - resolve module's parameters
-func Resolve(workspaceDir, moduleName, path string, extensions []string, envFile string) (result ResolveResult, messages []string, err error) {
+func Resolve(workspaceDir, moduleName, path string, extensions []string, envFile string, options Options) (result ResolveResult, messages []string, err error) {
 	if len(moduleName) == 0 {
 		return result, nil, errors.New(emptyModuleNameMsg)
 	}
-	mtaRaw, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	m, messages, err := newMTAResolverFromFile(workspaceDir, path, extensions, options)
 	if err != nil {
 		return result, messages, err
 	}
-	if len(workspaceDir) == 0 {
-		workspaceDir = filepath.Dir(path)
-	}
-
-	// If environment file name is not provided - set the default file name to .env
-	envFilePath := defaultEnvFileName
-	if len(envFile) > 0 {
-		envFilePath = envFile
-	}
-
-	m := NewMTAResolver(mtaRaw, workspaceDir)
 
 	for _, module := range m.GetModules() {
 		if module.Name == moduleName {
-			m.ResolveProperties(module, envFilePath)
+			m.ResolveProperties(module, getEnvFilePath(envFile))
 
 			propVarMap, err := getPropertiesAsEnvVar(module)
 			if err != nil {
@@ -62,6 +117,8 @@ func Resolve(workspaceDir, moduleName, path string, extensions []string, envFile
 			}
 			result.Properties = propVarMap
 			result.Messages = m.messages
+			result.Trace = m.Trace()
+			result.Errors = m.getErrors(options)
 			return result, messages, nil
 		}
 	}
@@ -69,36 +126,232 @@ func Resolve(workspaceDir, moduleName, path string, extensions []string, envFile
 	return result, messages, errors.Errorf(moduleNotFoundMsg, moduleName)
 }
 
+// ResolveResource - resolve resource's properties and parameters
+func ResolveResource(workspaceDir, resourceName, path string, extensions []string, envFile string, options Options) (result ResolveResult, messages []string, err error) {
+	if len(resourceName) == 0 {
+		return result, nil, errors.New(emptyResourceNameMsg)
+	}
+	m, messages, err := newMTAResolverFromFile(workspaceDir, path, extensions, options)
+	if err != nil {
+		return result, messages, err
+	}
+
+	resource := m.GetResourceByName(resourceName)
+	if resource == nil {
+		return result, messages, errors.Errorf(resourceNotFoundMsg, resourceName)
+	}
+	if !resource.IsActive() {
+		m.addMessage(fmt.Sprintf(inactiveResourceMsg, resourceName))
+	}
+	m.ResolveResource(resource, getEnvFilePath(envFile))
+
+	result.Properties, err = serializePropertiesAsEnvVars(resource.Properties)
+	if err != nil {
+		return result, messages, err
+	}
+	result.Parameters, err = serializePropertiesAsEnvVars(resource.Parameters)
+	if err != nil {
+		return result, messages, err
+	}
+	result.Messages = m.messages
+	result.Trace = m.Trace()
+	result.Errors = m.getErrors(options)
+	return result, messages, nil
+}
+
+// ResolveResourceConfigs - resolve the configurations (service creation parameters) of the resources, which are
+// the config parameter merged over the file in the path parameter with the merge strategy.
+// When no resource names are sent, the configurations of all the active resources are resolved.
+func ResolveResourceConfigs(workspaceDir string, resourceNames []string, path string, extensions []string, envFile string,
+	strategy mta.MergeStrategy, options Options) (result ResolveConfigsResult, messages []string, err error) {
+	m, messages, err := newMTAResolverFromFile(workspaceDir, path, extensions, options)
+	if err != nil {
+		return result, messages, err
+	}
+
+	var resources []*mta.Resource
+	if len(resourceNames) == 0 {
+		for _, resource := range m.GetResources() {
+			if resource.IsActive() {
+				resources = append(resources, resource)
+			}
+		}
+	}
+	for _, resourceName := range resourceNames {
+		resource := m.GetResourceByName(resourceName)
+		if resource == nil {
+			return result, messages, errors.Errorf(resourceNotFoundMsg, resourceName)
+		}
+		if !resource.IsActive() {
+			m.addMessage(fmt.Sprintf(inactiveResourceMsg, resourceName))
+		}
+		resources = append(resources, resource)
+	}
+
+	m.loadContext(resolvePath(getEnvFilePath(envFile), m.WorkingDir))
+	result.Configs = make(map[string]map[string]interface{}, len(resources))
+	for _, resource := range resources {
+		config, err := m.resolveResourceConfig(resource, strategy)
+		if err != nil {
+			return result, messages, err
+		}
+		result.Configs[resource.Name] = config
+	}
+	result.Messages = m.messages
+	result.Trace = m.Trace()
+	result.Errors = m.getErrors(options)
+	return result, messages, nil
+}
+
+// ResolveHook - resolve hook's parameters, and the properties which are available to the hook in the module's environment
+func ResolveHook(workspaceDir, moduleName, hookName, path string, extensions []string, envFile string, options Options) (result ResolveResult, messages []string, err error) {
+	if len(moduleName) == 0 {
+		return result, nil, errors.New(emptyModuleNameMsg)
+	}
+	if len(hookName) == 0 {
+		return result, nil, errors.New(emptyHookNameMsg)
+	}
+	m, messages, err := newMTAResolverFromFile(workspaceDir, path, extensions, options)
+	if err != nil {
+		return result, messages, err
+	}
+
+	module, err := m.GetModuleByName(moduleName)
+	if err != nil {
+		return result, messages, errors.Errorf(moduleNotFoundMsg, moduleName)
+	}
+	hook := module.GetHookByName(hookName)
+	if hook == nil {
+		return result, messages, errors.Errorf(hookNotFoundMsg, hookName, moduleName)
+	}
+	m.ResolveHook(module, hook, getEnvFilePath(envFile))
+
+	result.Properties, err = getHookPropertiesAsEnvVar(module, hook)
+	if err != nil {
+		return result, messages, err
+	}
+	result.Parameters, err = serializePropertiesAsEnvVars(hook.Parameters)
+	if err != nil {
+		return result, messages, err
+	}
+	result.Messages = m.messages
+	result.Trace = m.Trace()
+	result.Errors = m.getErrors(options)
+	return result, messages, nil
+}
+
+func (m *MTAResolver) getErrors(options Options) []*ResolveError {
+	if !options.Strict {
+		return nil
+	}
+	return m.errors
+}
+
+func newMTAResolverFromFile(workspaceDir, path string, extensions []string, options Options) (*MTAResolver, []string, error) {
+	mtaRaw, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	// The modules and resources inherit the values of their types; if the inheritance fails they are resolved without them
+	if effective, err := mtaRaw.Effective(); err != nil {
+		messages = append(messages, err.Error())
+	} else {
+		mtaRaw = effective
+	}
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+	if options.ExpandIncludes {
+		if err = mta.ExpandIncludes(mtaRaw, workspaceDir); err != nil {
+			return nil, messages, err
+		}
+	}
+	m := NewMTAResolver(mtaRaw, workspaceDir)
+	m.DeployerFormat = options.DeployerFormat
+	if options.Explain {
+		m.EnableTrace()
+	}
+	return m, messages, nil
+}
+
+// If environment file name is not provided - set the default file name to .env
+func getEnvFilePath(envFile string) string {
+	if len(envFile) > 0 {
+		return envFile
+	}
+	return defaultEnvFileName
+}
+
 func getPropertiesAsEnvVar(module *mta.Module) (map[string]string, error) {
 	envVar := map[string]interface{}{}
 	for key, val := range module.Properties {
 		envVar[key] = val
 	}
+	addRequiresToEnvVar(envVar, module.Requires)
 
-	for _, requires := range module.Requires {
-		propMap := envVar
-		if len(requires.Group) > 0 {
-			propMap = map[string]interface{}{}
-		}
+	//serialize
+	return serializePropertiesAsEnvVars(envVar)
+}
+
+// getHookPropertiesAsEnvVar returns the module's environment, extended with the properties required by the hook
+func getHookPropertiesAsEnvVar(module *mta.Module, hook *mta.Hook) (map[string]string, error) {
+	envVar := map[string]interface{}{}
+	for key, val := range module.Properties {
+		envVar[key] = val
+	}
+	addRequiresToEnvVar(envVar, append(append([]mta.Requires{}, module.Requires...), hook.Requires...))
 
-		for key, val := range requires.Properties {
-			propMap[key] = val
+	//serialize
+	return serializePropertiesAsEnvVars(envVar)
+}
+
+// addRequiresToEnvVar adds the properties of the requires sections to the environment, the same as the deployer does:
+// the properties of a requires section with a list are assembled into a JSON array under the list name, one element
+// per matched property set, and the properties (or the list) of a requires section with a group are appended
+// to a JSON array under the group name.
+func addRequiresToEnvVar(envVar map[string]interface{}, requiresList []mta.Requires) {
+	for i := 0; i < len(requiresList); i++ {
+		requires := requiresList[i]
+		propMap := map[string]interface{}{}
+		if len(requires.List) > 0 {
+			// a requires section on a configuration resource is expanded to consecutive sections, one per matched set
+			list := []map[string]interface{}{copyProperties(requires.Properties)}
+			for i+1 < len(requiresList) && isSameListRequires(requires, requiresList[i+1]) {
+				i++
+				list = append(list, copyProperties(requiresList[i].Properties))
+			}
+			propMap[requires.List] = list
+		} else {
+			propMap = copyProperties(requires.Properties)
 		}
 
-		if len(requires.Group) > 0 {
-			//append the array element to group
-			group, ok := envVar[requires.Group]
-			if ok {
-				groupArray := group.([]map[string]interface{})
-				envVar[requires.Group] = append(groupArray, propMap)
-			} else {
-				envVar[requires.Group] = []map[string]interface{}{propMap}
+		if len(requires.Group) == 0 {
+			for key, val := range propMap {
+				envVar[key] = val
 			}
+			continue
+		}
+
+		//append the array element to group
+		group, ok := envVar[requires.Group].([]map[string]interface{})
+		if ok {
+			envVar[requires.Group] = append(group, propMap)
+		} else {
+			envVar[requires.Group] = []map[string]interface{}{propMap}
 		}
 	}
+}
 
-	//serialize
-	return serializePropertiesAsEnvVars(envVar)
+func isSameListRequires(requires mta.Requires, other mta.Requires) bool {
+	return other.Name == requires.Name && other.List == requires.List && other.Group == requires.Group
+}
+
+func copyProperties(properties map[string]interface{}) map[string]interface{} {
+	propMap := make(map[string]interface{}, len(properties))
+	for key, val := range properties {
+		propMap[key] = val
+	}
+	return propMap
 }
 
 func serializePropertiesAsEnvVars(envVar map[string]interface{}) (map[string]string, error) {
@@ -125,13 +378,20 @@ type MTAResolver struct {
 	WorkingDir string
 	context    *ResolveContext
 	messages   []string
+	// DeployerFormat renders structured and non-string values inside strings the way the deployer renders them,
+	// instead of as JSON, and resolves the non-string parameters, which are ignored otherwise
+	DeployerFormat bool
+	tracer         *tracer
+	errors         []*ResolveError
+	// location is the location of the value which is being resolved
+	location ValueLocation
+	// references are the variables and placeholders which are being resolved, for the cycle detection
+	references []string
 }
 
 const resourceType = 1
 const moduleType = 2
-
-const variablePrefix = "~"
-const placeholderPrefix = "$"
+const hookType = 3
 
 type mtaSource struct {
 	Name       string
@@ -140,6 +400,20 @@ type mtaSource struct {
 	Type       int
 	Module     *mta.Module
 	Resource   *mta.Resource
+	// Parent is the enclosing scope of the source, e.g. the module of a hook
+	Parent *mtaSource
+}
+
+func newModuleSource(module *mta.Module) *mtaSource {
+	return &mtaSource{Name: module.Name, Properties: module.Properties, Parameters: module.Parameters, Type: moduleType, Module: module}
+}
+
+func newResourceSource(resource *mta.Resource) *mtaSource {
+	return &mtaSource{Name: resource.Name, Properties: resource.Properties, Parameters: resource.Parameters, Type: resourceType, Resource: resource}
+}
+
+func newHookSource(module *mta.Module, hook *mta.Hook) *mtaSource {
+	return &mtaSource{Name: hook.Name, Parameters: hook.Parameters, Type: hookType, Module: module, Parent: newModuleSource(module)}
 }
 
 // NewMTAResolver is a factory function for MTAResolver
@@ -148,7 +422,8 @@ func NewMTAResolver(m *mta.MTA, workspaceDir string) *MTAResolver {
 		global:    map[string]string{},
 		modules:   map[string]map[string]string{},
 		resources: map[string]map[string]string{},
-	}, []string{}}
+		origins:   map[string]string{},
+	}, []string{}, false, nil, nil, ValueLocation{}, nil}
 
 	for _, module := range m.Modules {
 		resolver.context.modules[module.Name] = map[string]string{}
@@ -160,6 +435,19 @@ func NewMTAResolver(m *mta.MTA, workspaceDir string) *MTAResolver {
 	return resolver
 }
 
+// EnableTrace enables recording the resolution trace
+func (m *MTAResolver) EnableTrace() {
+	m.tracer = &tracer{}
+}
+
+// Trace returns the resolution trace of each resolved value, or nil if the trace is not enabled
+func (m *MTAResolver) Trace() []*ValueTrace {
+	if m.tracer == nil {
+		return nil
+	}
+	return m.tracer.values
+}
+
 func resolvePath(path string, parts ...string) string {
 	absolutePath := path
 	if !filepath.IsAbs(path) {
@@ -170,7 +458,57 @@ func resolvePath(path string, parts ...string) string {
 
 // ResolveProperties is the main function to trigger the resolution
 func (m *MTAResolver) ResolveProperties(module *mta.Module, envFilePath string) {
+	m.loadContext(m.getModuleEnvFile(module, envFilePath))
+	m.resolveModule(module)
+}
 
+// ResolveResource resolves the parameters and properties of the resource, and the parameters and properties
+// in its requires section. The environment file path is relative to the working directory.
+func (m *MTAResolver) ResolveResource(resource *mta.Resource, envFilePath string) {
+	m.loadContext(resolvePath(envFilePath, m.WorkingDir))
+
+	// The resource is the source of its own parameters and properties, the same as when it is required
+	owner := newResourceSource(resource)
+	// Parameters are resolved first, so placeholders in the properties get the resolved parameter values
+	m.resolveMap(nil, owner, parametersSection, resource.Parameters)
+	m.resolveMap(nil, owner, propertiesSection, resource.Properties)
+	resource.Requires = m.resolveRequires(owner, resource.Requires)
+}
+
+// resolveResourceConfig resolves the parameters of the resource, and then the configuration which is merged from
+// its config parameter and the file in its path parameter, so the values from the file are resolved too
+func (m *MTAResolver) resolveResourceConfig(resource *mta.Resource, strategy mta.MergeStrategy) (map[string]interface{}, error) {
+	owner := newResourceSource(resource)
+	m.resolveMap(nil, owner, parametersSection, resource.Parameters)
+	config, err := resource.GetConfig(m.WorkingDir, strategy)
+	if err != nil {
+		return nil, err
+	}
+	m.resolveMap(nil, owner, configSection, config)
+	return config, nil
+}
+
+// ResolveHook resolves the parameters of the hook and the parameters and properties in its requires section.
+// The hook runs in the module's environment, so the module's properties are resolved too.
+func (m *MTAResolver) ResolveHook(module *mta.Module, hook *mta.Hook, envFilePath string) {
+	m.loadContext(m.getModuleEnvFile(module, envFilePath))
+	m.resolveModule(module)
+
+	owner := newHookSource(module, hook)
+	m.resolveMap(owner, nil, parametersSection, hook.Parameters)
+	hook.Requires = m.resolveRequires(owner, hook.Requires)
+}
+
+// getModuleEnvFile returns the path of the environment file in the module's path, or an empty string if the module has no path
+func (m *MTAResolver) getModuleEnvFile(module *mta.Module, envFilePath string) string {
+	if len(module.Path) > 0 {
+		return resolvePath(envFilePath, m.WorkingDir, module.Path)
+	}
+	return ""
+}
+
+// loadContext adds the environment variables and the variables from the environment file (if sent) to the context
+func (m *MTAResolver) loadContext(envFile string) {
 	if m.Parameters == nil {
 		m.Parameters = map[string]interface{}{}
 	}
@@ -181,41 +519,165 @@ func (m *MTAResolver) ResolveProperties(module *mta.Module, envFilePath string)
 		if pos > 0 {
 			key := strings.Trim(val[:pos], " ")
 			value := strings.Trim(val[pos+1:], " ")
-			m.addValueToContext(key, value)
+			m.addValueToContext(key, value, fmt.Sprintf(envVarOrigin, key))
 		}
 	}
 
-	//add .env file in module's path to the module context
-	if len(module.Path) > 0 {
-		envFile := resolvePath(envFilePath, m.WorkingDir, module.Path)
+	//add the .env file to the context
+	if len(envFile) > 0 {
 		envMap, err := godotenv.Read(envFile)
 		if err == nil {
 			for key, value := range envMap {
-				m.addValueToContext(key, value)
+				m.addValueToContext(key, value, fmt.Sprintf(envFileOrigin, key, envFile))
 			}
 		}
 	}
-	m.addServiceNames(module)
+	m.addServiceNames()
+}
+
+func (m *MTAResolver) resolveModule(module *mta.Module) {
+	owner := newModuleSource(module)
 
 	//top level properties
-	for key, value := range module.Properties {
+	m.resolveMap(owner, nil, propertiesSection, module.Properties)
+
+	//required properties and parameters
+	module.Requires = m.resolveRequires(owner, module.Requires)
+}
+
+// resolveMap resolves the variables and placeholders of the values in the map (properties or parameters) of the owner
+// or source. Variables must have the name of the required property set as a prefix.
+func (m *MTAResolver) resolveMap(owner *mtaSource, source *mtaSource, section string, values map[string]interface{}) {
+	entity := owner
+	if entity == nil {
+		entity = source
+	}
+	for key, value := range values {
+		valueTrace := m.beginValue(entity, "", section, key)
 		//no expected variables
-		propValue := m.resolve(module, nil, value)
-		module.Properties[key] = m.resolvePlaceholders(module, nil, nil, propValue)
+		resolvedValue := m.resolve(owner, source, nil, value)
+		values[key] = m.resolvePlaceholders(owner, source, nil, resolvedValue)
+		m.tracer.endValue(valueTrace, values[key])
 	}
+}
 
-	//required properties:
-	for _, req := range module.Requires {
+// resolveRequires resolves the parameters and properties of each requires section of the owner and returns the
+// resolved requires sections. Variables reference the properties of the required property set, and placeholders are
+// searched in the required property set, then in the requires parameters and then in the owner.
+// A requires section with a list which references a configuration resource is expanded to a requires section for
+// each matched configuration, the same as the deployer does.
+// A requires section which references an inactive resource is dropped, the same as the deployer does;
+// a message is added unless the resource is optional.
+func (m *MTAResolver) resolveRequires(owner *mtaSource, requiresList []mta.Requires) []mta.Requires {
+	var result []mta.Requires
+	for _, req := range requiresList {
+		if resource := m.GetResourceByName(req.Name); resource != nil && !resource.IsActive() {
+			if !resource.Optional {
+				m.addMessage(fmt.Sprintf(ignoredRequiresMsg, req.Name, owner.Name))
+			}
+			continue
+		}
 		requiredSource := m.findProvider(req.Name)
-		for propName, PropValue := range req.Properties {
-			resolvedValue := m.resolve(module, &req, PropValue)
-			//replace value with resolved value
-			req.Properties[propName] = m.resolvePlaceholders(module, requiredSource, &req, resolvedValue)
+		for _, expanded := range m.expandListRequires(req, requiredSource) {
+			m.resolveRequiresEntry(owner, expanded.source, &expanded.requires)
+			result = append(result, expanded.requires)
+		}
+	}
+	return result
+}
+
+func (m *MTAResolver) resolveRequiresEntry(owner *mtaSource, requiredSource *mtaSource, req *mta.Requires) {
+	// Parameters are resolved first, so placeholders in the properties get the resolved parameter values
+	for paramName, paramValue := range req.Parameters {
+		valueTrace := m.beginValue(owner, req.Name, parametersSection, paramName)
+		resolvedValue := m.resolve(owner, requiredSource, req, paramValue)
+		req.Parameters[paramName] = m.resolvePlaceholders(owner, requiredSource, req, resolvedValue)
+		m.tracer.endValue(valueTrace, req.Parameters[paramName])
+	}
+	for propName, propValue := range req.Properties {
+		valueTrace := m.beginValue(owner, req.Name, propertiesSection, propName)
+		resolvedValue := m.resolve(owner, requiredSource, req, propValue)
+		//replace value with resolved value
+		req.Properties[propName] = m.resolvePlaceholders(owner, requiredSource, req, resolvedValue)
+		m.tracer.endValue(valueTrace, req.Properties[propName])
+	}
+}
+
+type requiresWithSource struct {
+	requires mta.Requires
+	source   *mtaSource
+}
+
+// expandListRequires returns a requires section for each configuration matched by a requires section with a list.
+// The deployer matches the configurations provided by other MTAs; locally they are taken from the
+// "<resource>/configurations" variable (a JSON array of provided property sets) in the environment.
+// If the requires section has no list, or the matched configurations are not defined, it is returned as is.
+func (m *MTAResolver) expandListRequires(req mta.Requires, requiredSource *mtaSource) []requiresWithSource {
+	if len(req.List) == 0 || requiredSource == nil || requiredSource.Type != resourceType || requiredSource.Resource.Type != configurationType {
+		return []requiresWithSource{{req, requiredSource}}
+	}
+	configurations, ok := m.getConfigurations(requiredSource.Name)
+	if !ok {
+		return []requiresWithSource{{req, requiredSource}}
+	}
+
+	result := make([]requiresWithSource, 0, len(configurations))
+	for _, configuration := range configurations {
+		expanded := req
+		expanded.Properties = copyMap(req.Properties)
+		expanded.Parameters = copyMap(req.Parameters)
+		source := *requiredSource
+		source.Properties = configuration
+		result = append(result, requiresWithSource{expanded, &source})
+	}
+	return result
+}
+
+func (m *MTAResolver) getConfigurations(resourceName string) ([]map[string]interface{}, bool) {
+	configurationsStr, ok := m.context.resources[resourceName][configurationsContextKey]
+	if !ok {
+		return nil, false
+	}
+	var configurations []map[string]interface{}
+	err := json.Unmarshal([]byte(configurationsStr), &configurations)
+	if err != nil {
+		m.addMessage(fmt.Sprintf(wrongConfigurationsMsg, resourceName, configurationsContextKey))
+		return nil, false
+	}
+	return configurations, true
+}
+
+// copyMap returns a deep copy of the map, so it can be resolved without changing the original values
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	return copyValue(m).(map[string]interface{})
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := convertToJSONSafe(value).(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[key] = copyValue(val)
 		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			res[i] = copyValue(val)
+		}
+		return res
+	default:
+		return v
 	}
 }
 
-func (m *MTAResolver) addValueToContext(key, value string) {
+func (m *MTAResolver) addValueToContext(key, value, origin string) {
+	if m.context.origins != nil {
+		m.context.origins[key] = origin
+	}
 	//if the key has format of "module/key", or "resource/key" writes the value to the module's context
 	slashPos := strings.Index(key, "/")
 	if slashPos > 0 {
@@ -234,23 +696,23 @@ func (m *MTAResolver) addValueToContext(key, value string) {
 
 }
 
-func (m *MTAResolver) resolve(sourceModule *mta.Module, requires *mta.Requires, valueObj interface{}) interface{} {
+func (m *MTAResolver) resolve(owner *mtaSource, source *mtaSource, requires *mta.Requires, valueObj interface{}) interface{} {
 	switch valueObj := valueObj.(type) {
 	case map[interface{}]interface{}:
 		v := convertToJSONSafe(valueObj)
-		return m.resolve(sourceModule, requires, v)
+		return m.resolve(owner, source, requires, v)
 	case map[string]interface{}:
 		for k, v := range valueObj {
-			valueObj[k] = m.resolve(sourceModule, requires, v)
+			valueObj[k] = m.resolve(owner, source, requires, v)
 		}
 		return valueObj
 	case []interface{}:
 		for i, v := range valueObj {
-			valueObj[i] = m.resolve(sourceModule, requires, v)
+			valueObj[i] = m.resolve(owner, source, requires, v)
 		}
 		return valueObj
 	case string:
-		return m.resolveString(sourceModule, requires, valueObj)
+		return m.resolveString(owner, source, requires, valueObj)
 	default:
 		//if the value is not a string but a leaf, just return it
 		return valueObj
@@ -258,37 +720,39 @@ func (m *MTAResolver) resolve(sourceModule *mta.Module, requires *mta.Requires,
 
 }
 
-func (m *MTAResolver) resolveString(sourceModule *mta.Module, requires *mta.Requires, value string) interface{} {
-	pos := 0
-
-	pos, variableName, wholeValue := parseNextVariable(pos, value, variablePrefix)
-	if pos < 0 {
-		//no variables
-		return value
+// resolveString resolves the variables in the value. The escaped expressions are kept escaped,
+// so they are not resolved as placeholders later.
+func (m *MTAResolver) resolveString(owner *mtaSource, source *mtaSource, requires *mta.Requires, value string) interface{} {
+	parsed := expression.Parse(value)
+	if node, ok := parsed.WholeExpression(expression.Variable); ok && !node.DoubleBraced {
+		return m.getTracedVariableValue(owner, source, requires, node.Value)
 	}
-	varValue := m.getVariableValue(sourceModule, requires, variableName)
-
-	if wholeValue {
-		return varValue
+	if !parsed.HasExpressions(expression.Variable) {
+		return value
 	}
-	for pos >= 0 {
-		varValueStr, _ := convertToString(varValue)
-		value = value[:pos] + varValueStr + value[pos+len(variableName)+3:]
 
-		pos, variableName, _ = parseNextVariable(pos+len(varValueStr), value, variablePrefix)
-		if pos >= 0 {
-			varValue = m.getVariableValue(sourceModule, requires, variableName)
+	var result strings.Builder
+	for _, node := range parsed.Nodes {
+		// The expressions in double braces are not resolved
+		if node.Kind == expression.Variable && !node.DoubleBraced {
+			varValueStr, _ := m.convertToString(m.getTracedVariableValue(owner, source, requires, node.Value))
+			result.WriteString(varValueStr)
+		} else {
+			result.WriteString(parsed.Raw(node))
 		}
 	}
-
-	return value
+	return result.String()
 }
 
-func convertToString(valueObj interface{}) (string, bool) {
+// convertToString returns the value as it is rendered inside a string, and whether it was converted
+func (m *MTAResolver) convertToString(valueObj interface{}) (string, bool) {
 	switch v := valueObj.(type) {
 	case string:
 		return v, false
 	}
+	if m.DeployerFormat {
+		return stringifyValue(valueObj), true
+	}
 	valueBytes, err := json.Marshal(convertToJSONSafe(valueObj))
 	if err != nil {
 		logs.Logger.Error(err)
@@ -297,185 +761,254 @@ func convertToString(valueObj interface{}) (string, bool) {
 	return string(valueBytes), true
 }
 
-// return start position, name of variable and if it is a whole value
-func parseNextVariable(pos int, value string, prefix string) (int, string, bool) {
-
-	endSign := "}"
-	posStart := strings.Index(value[pos:], prefix+"{")
-	if posStart < 0 {
-		return -1, "", false
-	}
-	posStart += pos
-
-	if string(value[posStart+2]) == "{" {
-		endSign = "}}"
-	}
-
-	posEnd := strings.Index(value[posStart+2:], endSign)
-	if posEnd < 0 {
-		//bad value
-		return -1, "", false
-	}
-	posEnd += posStart + 1 + len(endSign)
-	wholeValue := posStart == 0 && posEnd == len(value)-1
-
-	return posStart, value[posStart+2 : posEnd], wholeValue
+func (m *MTAResolver) getTracedVariableValue(owner *mtaSource, source *mtaSource, requires *mta.Requires, variableName string) interface{} {
+	state := m.tracer.beginSubstitution(expression.VariablePrefix + "{" + variableName + "}")
+	value := m.getVariableValue(owner, source, requires, variableName)
+	m.tracer.endSubstitution(state, value)
+	return value
 }
 
-func (m *MTAResolver) getVariableValue(sourceModule *mta.Module, requires *mta.Requires, variableName string) interface{} {
-	var providerName string
+// getVariableValue returns the value of the variable. If requires is sent, the variable references a property of
+// the required source; otherwise the variable has the name of the required property set as a prefix.
+func (m *MTAResolver) getVariableValue(owner *mtaSource, source *mtaSource, requires *mta.Requires, variableName string) interface{} {
+	expr := expression.VariablePrefix + "{" + variableName + "}"
+	var setName string
 	if requires == nil {
 		slashPos := strings.Index(variableName, "/")
 		if slashPos > 0 {
-			providerName = variableName[:slashPos]
+			setName = variableName[:slashPos]
+			source = m.findProvider(setName)
 			variableName = variableName[slashPos+1:]
 		} else {
 			m.addMessage(fmt.Sprintf(missingPrefixMsg, variableName))
+			m.addError(UnresolvedVariableError, expr, nil, fmt.Sprintf(missingPrefixMsg, variableName))
 			return "~{" + variableName + "}"
 		}
 
 	} else {
-		providerName = requires.Name
+		setName = requires.Name
+		if source == nil {
+			source = m.findProvider(requires.Name)
+		}
 	}
 
-	source := m.findProvider(providerName)
 	if source != nil {
-		for propName, propValue := range source.Properties {
-			if propName == variableName {
-
+		propValue, found := source.Properties[variableName]
+		m.tracer.lookup(PropertiesScope, source, found, "")
+		if found {
+			key := expression.VariablePrefix + "{" + setName + "/" + variableName + "}"
+			return m.resolveReference(key, expr, func() interface{} {
+				// The provided value is resolved in the scope of its provider, without changing it:
 				//Do not pass module and requires, because it is a wrong scope
 				//it is either global->module->requires
 				//or           global->resource
-				propValue = m.resolvePlaceholders(nil, source, nil, propValue)
-				return convertToJSONSafe(propValue)
-			}
+				resolvedValue := m.resolve(nil, nil, nil, copyValue(propValue))
+				resolvedValue = m.resolvePlaceholders(nil, source, nil, resolvedValue)
+				return convertToJSONSafe(resolvedValue)
+			})
 		}
 	}
 
-	if source != nil && source.Type == resourceType && source.Resource.Type == "configuration" {
+	if source != nil && source.Type == resourceType && source.Resource.Type == configurationType {
 		provID, ok := getStringFromMap(source.Resource.Parameters, "provider-id")
 		if ok {
-			m.addMessage(fmt.Sprint("Missing configuration ", provID, "/", variableName))
+			message := fmt.Sprint("Missing configuration ", provID, "/", variableName)
+			m.addMessage(message)
+			m.addError(MissingConfigurationError, expr, nil, message)
+			return "~{" + variableName + "}"
 		}
 	}
 
+	m.addError(UnresolvedVariableError, expr, nil, fmt.Sprintf(unresolvedVariableMsg, expr))
 	return "~{" + variableName + "}"
 }
 
-func (m *MTAResolver) resolvePlaceholders(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, valueObj interface{}) interface{} {
+// resolveReference resolves the value of a referenced property or parameter with the resolve function.
+// If the reference is already being resolved, it is a cyclic reference and the unresolved value is returned.
+func (m *MTAResolver) resolveReference(key string, unresolvedValue string, resolve func() interface{}) interface{} {
+	for i, reference := range m.references {
+		if reference == key {
+			cycle := append(append([]string{}, m.references[i:]...), key)
+			message := fmt.Sprintf(cyclicReferenceMsg, key, strings.Join(cycle, " -> "))
+			m.addMessage(message)
+			m.addError(CyclicReferenceError, key, cycle, message)
+			return unresolvedValue
+		}
+	}
+	m.references = append(m.references, key)
+	value := resolve()
+	m.references = m.references[:len(m.references)-1]
+	return value
+}
+
+// beginValue sets the location of the value which is being resolved
+func (m *MTAResolver) beginValue(entity *mtaSource, requires string, section string, name string) *ValueTrace {
+	m.location = newValueLocation(entity, requires, section, name)
+	return m.tracer.beginValue(m.location)
+}
+
+// addError adds an error for the value which is being resolved, unless it was already added
+func (m *MTAResolver) addError(kind string, expr string, cycle []string, message string) {
+	for _, err := range m.errors {
+		if err.ValueLocation == m.location && err.Kind == kind && err.Expression == expr {
+			return
+		}
+	}
+	m.errors = append(m.errors, &ResolveError{ValueLocation: m.location, Kind: kind, Expression: expr, Cycle: cycle, Message: message})
+}
+
+func (m *MTAResolver) resolvePlaceholders(owner *mtaSource, source *mtaSource, requires *mta.Requires, valueObj interface{}) interface{} {
 	switch valueObj := valueObj.(type) {
 	case map[interface{}]interface{}:
 		v := convertToJSONSafe(valueObj)
-		return m.resolvePlaceholders(sourceModule, source, requires, v)
+		return m.resolvePlaceholders(owner, source, requires, v)
 	case map[string]interface{}:
 		for k, v := range valueObj {
-			valueObj[k] = m.resolvePlaceholders(sourceModule, source, requires, v)
+			valueObj[k] = m.resolvePlaceholders(owner, source, requires, v)
 		}
 		return valueObj
 	case []interface{}:
 		for k, v := range valueObj {
-			valueObj[k] = m.resolvePlaceholders(sourceModule, source, requires, v)
+			valueObj[k] = m.resolvePlaceholders(owner, source, requires, v)
 		}
 		return valueObj
 	case string:
-		return m.resolvePlaceholdersString(sourceModule, source, requires, valueObj)
+		return m.resolvePlaceholdersString(owner, source, requires, valueObj)
 	default:
 		//if the value is not a string but a leaf, just return it
 		return valueObj
 	}
 }
 
-func (m *MTAResolver) resolvePlaceholdersString(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, value string) interface{} {
-	pos := 0
-	pos, placeholderName, wholeValue := parseNextVariable(pos, value, placeholderPrefix)
-
-	if pos < 0 {
-		return value
+// resolvePlaceholdersString resolves the placeholders in the value. This is the last resolution of the value,
+// so the escaped expressions are unescaped.
+func (m *MTAResolver) resolvePlaceholdersString(owner *mtaSource, source *mtaSource, requires *mta.Requires, value string) interface{} {
+	parsed := expression.Parse(value)
+	if node, ok := parsed.WholeExpression(expression.Placeholder); ok && !node.DoubleBraced {
+		return m.getTracedParameter(owner, source, requires, node.Value)
 	}
-	placeholderValue := m.getParameter(sourceModule, source, requires, placeholderName)
 
-	if wholeValue {
-		return placeholderValue
-	}
-	for pos >= 0 {
-		phValueStr, _ := convertToString(placeholderValue)
-		value = value[:pos] + phValueStr + value[pos+len(placeholderName)+3:]
-		pos, placeholderName, _ = parseNextVariable(pos+len(phValueStr), value, placeholderPrefix)
-		if pos >= 0 {
-			placeholderValue = m.getParameter(sourceModule, source, requires, placeholderName)
+	var result strings.Builder
+	for _, node := range parsed.Nodes {
+		switch {
+		case node.Kind == expression.Placeholder && !node.DoubleBraced:
+			phValueStr, _ := m.convertToString(m.getTracedParameter(owner, source, requires, node.Value))
+			result.WriteString(phValueStr)
+		case node.Kind == expression.Text:
+			result.WriteString(node.Value)
+		default:
+			result.WriteString(parsed.Raw(node))
 		}
 	}
-
-	return value
+	return result.String()
 }
 
-func (m *MTAResolver) getParameterFromSource(source *mtaSource, paramName string) string {
+func (m *MTAResolver) getParameterFromSource(source *mtaSource, paramName string) (interface{}, bool) {
 	if source != nil {
 		// See if the value was configured externally first (in VCAP_SERVICES, env var etc)
 		// The source can be a module or a resource
 		module, found := m.context.modules[source.Name]
 		if found {
 			paramValStr, ok := module[paramName]
+			m.traceContextLookup(source, paramName, ok)
 			if ok {
-				return paramValStr
+				return paramValStr, true
 			}
 		}
 
 		resource, found := m.context.resources[source.Name]
 		if found {
 			paramValStr, ok := resource[paramName]
+			m.traceContextLookup(source, paramName, ok)
 			if ok {
-				return paramValStr
+				return paramValStr, true
 			}
 		}
 
 		// If it was not defined externally, try to get it from the source parameters
-		paramVal, found := getStringFromMap(source.Parameters, paramName)
+		paramVal, found := m.getParameterFromMap(source.Parameters, paramName)
+		m.tracer.lookup(ParametersScope, source, found, "")
 		if found {
-			return paramVal
+			return m.resolveParameterValue(source.Name, paramName, paramVal, nil, source, nil), true
 		}
 
 	}
-	return ""
+	return nil, false
 }
 
-func (m *MTAResolver) getParameter(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, paramName string) string {
-	//first on source parameters scope
-	paramValStr := m.getParameterFromSource(source, paramName)
+func (m *MTAResolver) traceContextLookup(source *mtaSource, paramName string, found bool) {
+	m.tracer.lookup(ContextScope, source, found, m.getOrigin(source.Name+"/"+paramName, found))
+}
 
+// getOrigin returns the environment variable or environment file of the context value with the key
+func (m *MTAResolver) getOrigin(key string, found bool) string {
+	if !found || m.tracer == nil {
+		return ""
+	}
+	return m.context.origins[key]
+}
+
+func (m *MTAResolver) getParameterFromScope(scope *mtaSource, paramName string) (interface{}, bool) {
+	if scope.Type == resourceType {
+		// A resource is resolved the same way as when it is required: the values configured externally come first
+		return m.getParameterFromSource(scope, paramName)
+	}
+
+	paramVal, ok := m.getParameterFromMap(scope.Parameters, paramName)
+	m.tracer.lookup(ParametersScope, scope, ok, "")
+	if ok {
+		return m.resolveParameterValue(scope.Name, paramName, paramVal, scope, nil, nil), true
+	}
+	if scope.Type == moduleType {
+		//defaults to context's module params:
+		paramValStr, ok := m.context.modules[scope.Name][paramName]
+		m.traceContextLookup(scope, paramName, ok)
+		return paramValStr, ok
+	}
+	return nil, false
+}
+
+func (m *MTAResolver) getTracedParameter(owner *mtaSource, source *mtaSource, requires *mta.Requires, paramName string) interface{} {
+	state := m.tracer.beginSubstitution(expression.PlaceholderPrefix + "{" + paramName + "}")
+	value := m.getParameter(owner, source, requires, paramName)
+	m.tracer.endSubstitution(state, value)
+	return value
+}
+
+func (m *MTAResolver) getParameter(owner *mtaSource, source *mtaSource, requires *mta.Requires, paramName string) interface{} {
 	//first on source parameters scope
-	if paramValStr != "" {
-		return paramValStr
+	paramVal, ok := m.getParameterFromSource(source, paramName)
+	if ok {
+		return paramVal
 	}
 
 	//then try on requires level
 	if requires != nil {
-		paramVal, ok := getStringFromMap(requires.Parameters, paramName)
+		paramVal, ok := m.getParameterFromMap(requires.Parameters, paramName)
+		m.tracer.lookup(RequiresParametersScope, nil, ok, "")
 		if ok {
-			return paramVal
+			return m.resolveParameterValue(requires.Name, paramName, paramVal, owner, source, requires)
 		}
 	}
 
-	if sourceModule != nil {
-		paramVal, ok := getStringFromMap(sourceModule.Parameters, paramName)
+	//then on the owner's scope and its enclosing scopes (e.g. the module of a hook)
+	for scope := owner; scope != nil; scope = scope.Parent {
+		paramVal, ok := m.getParameterFromScope(scope, paramName)
 		if ok {
 			return paramVal
 		}
-		//defaults to context's module params:
-		paramValStr, ok = m.context.modules[sourceModule.Name][paramName]
-		if ok {
-			return paramValStr
-		}
 	}
 
 	//then on MTA root scope
-	paramVal, ok := getStringFromMap(m.Parameters, paramName)
+	paramVal, ok = m.getParameterFromMap(m.Parameters, paramName)
+	m.tracer.lookup(MtaParametersScope, nil, ok, "")
 	if ok {
-		return paramVal
+		return m.resolveParameterValue("", paramName, paramVal, nil, nil, nil)
 	}
 
 	//then global scope
-	paramValStr, ok = m.context.global[paramName]
+	paramValStr, ok := m.context.global[paramName]
+	m.tracer.lookup(GlobalContextScope, nil, ok, m.getOrigin(paramName, ok))
 	if ok {
 		return paramValStr
 	}
@@ -485,10 +1018,26 @@ func (m *MTAResolver) getParameter(sourceModule *mta.Module, source *mtaSource,
 	} else {
 		m.addMessage(fmt.Sprint("Missing ", source.Name+"/"+paramName))
 	}
+	expr := expression.PlaceholderPrefix + "{" + paramName + "}"
+	m.addError(UnresolvedPlaceholderError, expr, nil, fmt.Sprintf(missingParameterMsg, expr))
 
 	return "${" + paramName + "}"
 }
 
+// resolveParameterValue resolves the placeholders in the value of a parameter defined in the MTA, in the scope where
+// it is defined. The parameter is identified by the name of its scope (empty for the MTA parameters) for the cycle detection.
+func (m *MTAResolver) resolveParameterValue(scopeName string, paramName string, paramVal interface{},
+	owner *mtaSource, source *mtaSource, requires *mta.Requires) interface{} {
+	key := paramName
+	if scopeName != "" {
+		key = scopeName + "/" + paramName
+	}
+	key = expression.PlaceholderPrefix + "{" + key + "}"
+	return m.resolveReference(key, "${"+paramName+"}", func() interface{} {
+		return m.resolvePlaceholders(owner, source, requires, copyValue(paramVal))
+	})
+}
+
 func (m *MTAResolver) findProvider(name string) *mtaSource {
 	for _, module := range m.Modules {
 		for _, provides := range module.Provides {
@@ -499,9 +1048,9 @@ func (m *MTAResolver) findProvider(name string) *mtaSource {
 		}
 	}
 
-	//in case of resource, its name is the matching to the requires name
+	//in case of resource, its name is the matching to the requires name; the inactive resources don't provide values
 	for _, resource := range m.Resources {
-		if resource.Name == name {
+		if resource.Name == name && resource.IsActive() {
 			source := mtaSource{Name: resource.Name, Properties: resource.Properties, Parameters: resource.Parameters, Type: resourceType, Resource: resource}
 			return &source
 		}
@@ -548,17 +1097,6 @@ func convertToJSONSafe(val interface{}) interface{} {
 
 func getStringFromMap(params map[string]interface{}, key string) (string, bool) {
 	// Only return the parameter value if it's a string, to prevent a panic.
-	// Note: this is used mainly for parameter values during resolve.
-	// The deployer DOES support non-string parameters, both as the whole value
-	// (it keeps the same type) and inside a string.
-	// It stringifies the value in side a string but it's not the usual json stringify.
-	// For example, if we have this string:
-	//   prop_from_resource: "this is the prop: ~{some_prop}"
-	// And some_prop is defined like this:
-	//   stuct_field: abc
-	// We will get a resolved value like this from the Deployer:
-	// "this is the prop: {stuct_field=abc}"
-	// We do not support this use case currently.
 	value, ok := params[key]
 	if ok && value != nil {
 		str, isString := value.(string)
@@ -568,3 +1106,23 @@ func getStringFromMap(params map[string]interface{}, key string) (string, bool)
 	}
 	return "", false
 }
+
+// getValueFromMap returns the parameter value, of any type.
+// The deployer supports non-string parameters, both as the whole value (it keeps the same type)
+// and inside a string (see stringifyValue).
+func getValueFromMap(params map[string]interface{}, key string) (interface{}, bool) {
+	value, ok := params[key]
+	if ok && value != nil {
+		return convertToJSONSafe(value), true
+	}
+	return nil, false
+}
+
+// getParameterFromMap returns the parameter value. Non-string parameters are supported only in the deployer format,
+// otherwise they are ignored (see getStringFromMap).
+func (m *MTAResolver) getParameterFromMap(params map[string]interface{}, key string) (interface{}, bool) {
+	if m.DeployerFormat {
+		return getValueFromMap(params, key)
+	}
+	return getStringFromMap(params, key)
+}
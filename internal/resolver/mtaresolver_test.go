@@ -14,7 +14,7 @@ import (
 )
 
 func callResolveAndGetOutput(wd, moduleName, yamlPath string, extensions []string, envFileName string) (ResolveResult, []string) {
-	result, messages, err := Resolve(wd, moduleName, yamlPath, extensions, envFileName)
+	result, messages, err := Resolve(wd, moduleName, yamlPath, extensions, envFileName, Options{})
 	Ω(err).Should(Succeed())
 	return result, messages
 }
@@ -126,26 +126,51 @@ var _ = Describe("Resolve", func() {
 		expected.Messages = append(expected.Messages, `Missing ed-aaa/service-name`)
 		callResolveAndValidateOutput("", "eb-java", yamlPath, nil, "", expected, BeEmpty())
 	})
+	It("resolves the values which the module inherits from its type and the types which the type extends", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mtaTypes.yaml")
+		envGetter = mockEnvGetter
+		result, messages, err := Resolve(wd, "srv", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			`profile`:    `prod`,
+			`url`:        `https://srv.dev.com`,
+			`JBP_CONFIG`: `sap_java_buildpack with 1G`,
+		}))
+	})
+
+	It("resolves the values of the module without its type when the type inheritance fails", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mtaTypesCycle.yaml")
+		envGetter = mockEnvGetter
+		result, messages, err := Resolve(wd, "srv", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(ConsistOf(ContainSubstring(`"service"`)))
+		Ω(result.Properties).Should(Equal(map[string]string{
+			`profile`: `prod`,
+		}))
+	})
 	It("returns error when module name is empty", func() {
-		_, _, err := Resolve("", "", getTestPath("test-project", "mta.yaml"), nil, "")
+		_, _, err := Resolve("", "", getTestPath("test-project", "mta.yaml"), nil, "", Options{})
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(Equal(emptyModuleNameMsg))
 	})
 	It("returns error when module does not exist", func() {
-		_, _, err := Resolve("", "aaa", getTestPath("test-project", "mta.yaml"), nil, "")
+		_, _, err := Resolve("", "aaa", getTestPath("test-project", "mta.yaml"), nil, "", Options{})
 
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(Equal(fmt.Sprintf(moduleNotFoundMsg, "aaa")))
 	})
 	It("returns error when mta yaml path is not found", func() {
 		path := getTestPath("test-project", "mtaNotExist.yaml")
-		_, _, err := Resolve("", "eb-java", path, nil, "")
+		_, _, err := Resolve("", "eb-java", path, nil, "", Options{})
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(fs.PathNotFoundMsg, path)))
 	})
 	It("returns error when mta.yaml is invalid", func() {
 		path := getTestPath("test-project", "mtaBad.yaml")
-		_, _, err := Resolve("", "eb-java", path, nil, "")
+		_, _, err := Resolve("", "eb-java", path, nil, "", Options{})
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(mta.UnmarshalFailsMsg, path)))
 	})
@@ -215,6 +240,244 @@ var _ = Describe("Resolve", func() {
 	})
 })
 
+var _ = Describe("ResolveResource", func() {
+	It("resolves the resource properties and parameters", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mtaEntities.yaml")
+		envGetter = mockEnvGetter
+		result, messages, err := ResolveResource(wd, "dbResource", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(result).Should(Equal(ResolveResult{
+			Properties: map[string]string{
+				`schema`: `db-service_schema`,
+			},
+			Parameters: map[string]string{
+				`service`:      `hana`,
+				`service-name`: `db-service`,
+			},
+			Messages: []string{},
+		}))
+	})
+	It("resolves variables from the required property sets of the resource", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mtaEntities.yaml")
+		envGetter = mockEnvGetter
+		result, _, err := ResolveResource(wd, "apiConsumer", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			`apiUrl`:       `https://srv.dev.com`,
+			`apiLandscape`: `dev`,
+		}))
+		Ω(result.Parameters).Should(Equal(map[string]string{
+			`plan`:   `standard`,
+			`config`: `{"plan":"standard","url":"https://srv.dev.com"}`,
+		}))
+		// The properties of the requires section are resolved too
+		Ω(result.Messages).Should(Equal([]string{`Missing srvModule/unknown`}))
+	})
+	It("resolves the values which the resource inherits from its type", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mtaTypes.yaml")
+		envGetter = mockEnvGetter
+		result, _, err := ResolveResource(wd, "db", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			`schema`: `db-service_hana`,
+		}))
+		Ω(result.Parameters).Should(Equal(map[string]string{
+			`service`:      `hana`,
+			`service-plan`: `hdi-shared`,
+			`service-name`: `db-service`,
+		}))
+	})
+	It("takes the service name from VCAP_SERVICES", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mta.yaml")
+		envGetter = mockEnvGetterWithVcapServices
+		result, _, err := ResolveResource(wd, "ed-bbb", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties[`the-service-name`]).Should(Equal(`ed-bbb-service`))
+	})
+	It("returns error when resource name is empty", func() {
+		_, _, err := ResolveResource("", "", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(emptyResourceNameMsg))
+	})
+	It("returns error when resource does not exist", func() {
+		_, _, err := ResolveResource("", "aaa", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(fmt.Sprintf(resourceNotFoundMsg, "aaa")))
+	})
+	It("returns error when mta.yaml is invalid", func() {
+		path := getTestPath("test-project", "mtaBad.yaml")
+		_, _, err := ResolveResource("", "dbResource", path, nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(mta.UnmarshalFailsMsg, path)))
+	})
+})
+
+var _ = Describe("Resolve with includes", func() {
+	wd := getTestPath("test-project")
+	yamlPath := getTestPath("test-project", "mtaIncludes.yaml")
+
+	It("resolves the parameters loaded from the files in the includes", func() {
+		envGetter = mockEnvGetter
+		result, _, err := ResolveResource(wd, "uaa", yamlPath, nil, "", Options{ExpandIncludes: true, DeployerFormat: true})
+		Ω(err).Should(Succeed())
+		Ω(result.Parameters).Should(Equal(map[string]string{`config`: `{"xsappname":"app"}`}))
+		Ω(result.Properties).Should(Equal(map[string]string{`app-name`: `{"xsappname":"app"}`}))
+	})
+
+	It("doesn't load the files in the includes by default", func() {
+		envGetter = mockEnvGetter
+		result, _, err := ResolveResource(wd, "uaa", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Parameters).Should(BeEmpty())
+	})
+
+	It("returns error when an included file doesn't exist", func() {
+		_, _, err := ResolveResource(wd, "uaa", getTestPath("test-project", "mtaIncludesMissing.yaml"), nil, "", Options{ExpandIncludes: true})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(`could not expand the "config" include of the "missing" resource`))
+	})
+})
+
+var _ = Describe("ResolveResourceConfigs", func() {
+	wd := getTestPath("test-project")
+	yamlPath := getTestPath("test-project", "mtaResourceConfig.yaml")
+
+	It("resolves the configuration merged from the config parameter and the file", func() {
+		envGetter = mockEnvGetter
+		result, messages, err := ResolveResourceConfigs(wd, []string{"uaa"}, yamlPath, nil, "", mta.DeepMerge, Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(result.Configs).Should(Equal(map[string]map[string]interface{}{
+			"uaa": {
+				"xsappname": "myapp",
+				"oauth2-configuration": map[string]interface{}{
+					"token-validity": float64(3600),
+					"redirect-uris":  []interface{}{"https://srv.dev.com/**"},
+				},
+			},
+		}))
+		Ω(result.Messages).Should(BeEmpty())
+	})
+	It("resolves the configurations of all the active resources when no resource names are sent", func() {
+		envGetter = mockEnvGetter
+		result, _, err := ResolveResourceConfigs(wd, nil, yamlPath, nil, "", mta.ShallowMerge, Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Configs).Should(HaveLen(1))
+		Ω(result.Configs).Should(HaveKeyWithValue("uaa", HaveKeyWithValue("oauth2-configuration", HaveLen(1))))
+	})
+	It("resolves the configuration of an inactive resource with a message", func() {
+		envGetter = mockEnvGetter
+		result, _, err := ResolveResourceConfigs(wd, []string{"disabled"}, yamlPath, nil, "", mta.ShallowMerge, Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Configs).Should(HaveKeyWithValue("disabled", map[string]interface{}{"xsappname": "disabled-app"}))
+		Ω(result.Messages).Should(ConsistOf(fmt.Sprintf(inactiveResourceMsg, "disabled")))
+	})
+	It("returns error when resource does not exist", func() {
+		_, _, err := ResolveResourceConfigs(wd, []string{"aaa"}, yamlPath, nil, "", mta.ShallowMerge, Options{})
+		Ω(err).Should(MatchError(fmt.Sprintf(resourceNotFoundMsg, "aaa")))
+	})
+	It("returns error when the merge strategy is not supported", func() {
+		_, _, err := ResolveResourceConfigs(wd, []string{"uaa"}, yamlPath, nil, "", "other", Options{})
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("Resolve with inactive resources", func() {
+	wd := getTestPath("test-project")
+	yamlPath := getTestPath("test-project", "mtaInactive.yaml")
+
+	It("ignores the requires sections of the inactive resources and reports them unless the resources are optional", func() {
+		envGetter = mockEnvGetterWithVcapServices
+		result, messages, err := Resolve(wd, "srv", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			`db_url`:      `~{url}`,
+			`bbb_service`: `ed-bbb-service`,
+		}))
+		Ω(result.Messages).Should(Equal([]string{fmt.Sprintf(ignoredRequiresMsg, "ed-aaa", "srv")}))
+	})
+
+	It("reports that the resource is not active when it is resolved", func() {
+		envGetter = mockEnvGetterWithVcapServices
+		result, _, err := ResolveResource(wd, "ed-aaa", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{`url`: `https://aaa`}))
+		Ω(result.Messages).Should(Equal([]string{fmt.Sprintf(inactiveResourceMsg, "ed-aaa")}))
+	})
+
+	It("does not match the inactive resources with the service instances in VCAP_SERVICES", func() {
+		mtaObj, _, err := mta.GetMtaFromFile(yamlPath, nil, false)
+		Ω(err).Should(Succeed())
+		envGetter = mockEnvGetterWithVcapServices
+		m := NewMTAResolver(mtaObj, wd)
+		m.loadContext("")
+		Ω(m.context.resources["ed-aaa"]).ShouldNot(HaveKey("service-name"))
+		Ω(m.context.resources["ed-bbb"]).Should(HaveKeyWithValue("service-name", "ed-bbb-service"))
+	})
+})
+
+var _ = Describe("ResolveHook", func() {
+	It("resolves the hook parameters and the properties in the hook environment", func() {
+		wd := getTestPath("test-project")
+		yamlPath := getTestPath("test-project", "mtaEntities.yaml")
+		envGetter = mockEnvGetter
+		result, messages, err := ResolveHook(wd, "srvModule", "migrate", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(result).Should(Equal(ResolveResult{
+			Properties: map[string]string{
+				`moduleProp`:  `512M`,
+				`dbSchema`:    `db-service_schema`,
+				`HOOK_SCHEMA`: `db-service_schema`,
+			},
+			Parameters: map[string]string{
+				`name`:    `migrate-512M`,
+				`command`: `migrate --schema db-service_schema --env dev`,
+			},
+			Messages: []string{`Missing dbResource/task-timeout`},
+		}))
+	})
+	It("returns error when hook name is empty", func() {
+		_, _, err := ResolveHook("", "srvModule", "", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(emptyHookNameMsg))
+	})
+	It("returns error when module name is empty", func() {
+		_, _, err := ResolveHook("", "", "migrate", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(emptyModuleNameMsg))
+	})
+	It("returns error when module does not exist", func() {
+		_, _, err := ResolveHook("", "aaa", "migrate", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(fmt.Sprintf(moduleNotFoundMsg, "aaa")))
+	})
+	It("returns error when hook does not exist", func() {
+		_, _, err := ResolveHook("", "srvModule", "aaa", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(Equal(fmt.Sprintf(hookNotFoundMsg, "aaa", "srvModule")))
+	})
+})
+
+var _ = Describe("ResolveProperties", func() {
+	It("resolves the parameters of the module requires", func() {
+		mtaObj, _, err := mta.GetMtaFromFile(getTestPath("test-project", "mtaEntities.yaml"), nil, false)
+		Ω(err).Should(Succeed())
+		envGetter = mockEnvGetter
+		m := NewMTAResolver(mtaObj, getTestPath("test-project"))
+		module, err := m.GetModuleByName("srvModule")
+		Ω(err).Should(Succeed())
+		m.ResolveProperties(module, defaultEnvFileName)
+		Ω(module.Requires[0].Parameters["binding-name"]).Should(Equal("db-service_schema-binding"))
+	})
+})
+
 var _ = Describe("getPropertiesAsEnvVar", func() {
 	It("fails on marshalling", func() {
 		mod := mta.Module{
@@ -255,21 +518,214 @@ var _ = Describe("getPropertiesAsEnvVar", func() {
 		Ω(props["group1"]).Should(Equal(`[{"prop1":"value1","prop2":"value2"},{"prop3":"value3"}]`))
 		Ω(props["prop4"]).Should(Equal(`value4`))
 	})
+	It("required lists defined", func() {
+		mod := mta.Module{
+			Requires: []mta.Requires{
+				{
+					Name: "config1",
+					List: "list1",
+					Properties: map[string]interface{}{
+						"prop1": "value1",
+					},
+				},
+				{
+					Name: "config1",
+					List: "list1",
+					Properties: map[string]interface{}{
+						"prop1": "value2",
+					},
+				},
+				{
+					Name: "config2",
+					List: "list2",
+					Properties: map[string]interface{}{
+						"prop2": "value3",
+					},
+				},
+			},
+		}
+		props, err := getPropertiesAsEnvVar(&mod)
+		Ω(err).Should(Succeed())
+		Ω(len(props)).Should(Equal(2))
+		Ω(props["list1"]).Should(Equal(`[{"prop1":"value1"},{"prop1":"value2"}]`))
+		Ω(props["list2"]).Should(Equal(`[{"prop2":"value3"}]`))
+	})
+	It("required lists defined in groups", func() {
+		mod := mta.Module{
+			Requires: []mta.Requires{
+				{
+					Name:  "config1",
+					List:  "list1",
+					Group: "group1",
+					Properties: map[string]interface{}{
+						"prop1": "value1",
+					},
+				},
+				{
+					Name:  "config1",
+					List:  "list1",
+					Group: "group1",
+					Properties: map[string]interface{}{
+						"prop1": "value2",
+					},
+				},
+				{
+					Group: "group1",
+					Properties: map[string]interface{}{
+						"prop2": "value3",
+					},
+				},
+			},
+		}
+		props, err := getPropertiesAsEnvVar(&mod)
+		Ω(err).Should(Succeed())
+		Ω(len(props)).Should(Equal(1))
+		Ω(props["group1"]).Should(Equal(`[{"list1":[{"prop1":"value1"},{"prop1":"value2"}]},{"prop2":"value3"}]`))
+	})
+})
+
+var _ = Describe("Resolve with lists", func() {
+	It("assembles the configurations matched by a configuration resource", func() {
+		envGetter = mockEnvGetter
+		result, messages, err := Resolve(getTestPath("test-project", "list"), "consumer",
+			getTestPath("test-project", "list", "mta.yaml"), nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(result.Properties["plugins"]).Should(Equal(
+			`[{"plugin_name":"plugin1","plugin_url":"https://plugin1.com/dev"},` +
+				`{"plugin_name":"plugin2","plugin_url":"https://plugin2.com/dev"}]`))
+		Ω(result.Properties["all_plugins"]).Should(Equal(
+			`[{"plugins":[{"plugin_name":"plugin1"},{"plugin_name":"plugin2"}]}]`))
+		Ω(result.Messages).Should(BeEmpty())
+	})
+	It("reports missing configuration when the configurations are not defined", func() {
+		mtaObj, _, err := mta.GetMtaFromFile(getTestPath("test-project", "list", "mta.yaml"), nil, false)
+		Ω(err).Should(Succeed())
+		envGetter = mockEnvGetter
+		m := NewMTAResolver(mtaObj, getTestPath("test-project"))
+		module, err := m.GetModuleByName("consumer")
+		Ω(err).Should(Succeed())
+		m.ResolveProperties(module, defaultEnvFileName)
+		Ω(len(module.Requires)).Should(Equal(2))
+		Ω(m.messages).Should(ContainElement("Missing configuration com.acme.plugins:plugins/name"))
+	})
+	It("reports wrong configurations", func() {
+		mtaObj, _, err := mta.GetMtaFromFile(getTestPath("test-project", "list", "mta.yaml"), nil, false)
+		Ω(err).Should(Succeed())
+		m := NewMTAResolver(mtaObj, getTestPath("test-project"))
+		m.context.resources["plugins"] = map[string]string{configurationsContextKey: "{"}
+		configurations, ok := m.getConfigurations("plugins")
+		Ω(ok).Should(BeFalse())
+		Ω(configurations).Should(BeNil())
+		Ω(m.messages).Should(ConsistOf(fmt.Sprintf(wrongConfigurationsMsg, "plugins", configurationsContextKey)))
+	})
 })
 
 var _ = Describe("convertToString", func() {
 	It("fails to marshal function", func() {
-		_, success := convertToString(func() {})
+		resolver := MTAResolver{}
+		_, success := resolver.convertToString(func() {})
 		Ω(success).Should(BeFalse())
 	})
 	It("map conversion", func() {
-		str, success := convertToString(map[string]interface{}{
+		resolver := MTAResolver{}
+		str, success := resolver.convertToString(map[string]interface{}{
 			"field1": "value1",
 			"field2": "value2",
 		})
 		Ω(success).Should(BeTrue())
 		Ω(str).Should(Equal(`{"field1":"value1","field2":"value2"}`))
 	})
+	It("map conversion, the same as the deployer", func() {
+		resolver := MTAResolver{DeployerFormat: true}
+		str, success := resolver.convertToString(map[string]interface{}{
+			"field2": "value2",
+			"field1": "value1",
+		})
+		Ω(success).Should(BeTrue())
+		Ω(str).Should(Equal(`{field1=value1, field2=value2}`))
+	})
+	It("string is not converted", func() {
+		resolver := MTAResolver{}
+		str, success := resolver.convertToString("value")
+		Ω(success).Should(BeFalse())
+		Ω(str).Should(Equal("value"))
+	})
+})
+
+var _ = Describe("Resolve structured values", func() {
+	var mtaObj *mta.MTA
+
+	BeforeEach(func() {
+		var err error
+		mtaObj, _, err = mta.GetMtaFromFile(getTestPath("test-project", "mtaValues.yaml"), nil, false)
+		Ω(err).Should(Succeed())
+		envGetter = mockEnvGetter
+	})
+
+	It("renders the values inside strings the same as the deployer in deployer format", func() {
+		m := NewMTAResolver(mtaObj, getTestPath("test-project"))
+		m.DeployerFormat = true
+		module, err := m.GetModuleByName("valuesModule")
+		Ω(err).Should(Succeed())
+		m.ResolveProperties(module, defaultEnvFileName)
+		Ω(module.Properties["mapInString"]).Should(Equal("config: {enabled=true, ratio=0.5, size=10, tags=[a, b]}"))
+		Ω(module.Properties["listInString"]).Should(Equal("tags: [a, b]"))
+		Ω(module.Properties["intInString"]).Should(Equal("instances: 2"))
+		Ω(module.Properties["boolInString"]).Should(Equal("enabled: true"))
+		Ω(module.Properties["floatInString"]).Should(Equal("version: 1.0"))
+		Ω(module.Properties["nullInString"]).Should(Equal("config: {empty=null}"))
+		Ω(m.messages).Should(BeEmpty())
+	})
+
+	It("keeps the type of whole values in deployer format", func() {
+		m := NewMTAResolver(mtaObj, getTestPath("test-project"))
+		m.DeployerFormat = true
+		module, err := m.GetModuleByName("valuesModule")
+		Ω(err).Should(Succeed())
+		m.ResolveProperties(module, defaultEnvFileName)
+		Ω(module.Properties["intValue"]).Should(Equal(2))
+		Ω(module.Properties["boolValue"]).Should(Equal(true))
+		Ω(module.Properties["mapValue"]).Should(Equal(map[string]interface{}{"field": "abc"}))
+		Ω(module.Properties["listValue"]).Should(Equal([]interface{}{"a", "b"}))
+	})
+
+	It("renders the values inside strings as JSON and ignores the non-string parameters by default", func() {
+		m := NewMTAResolver(mtaObj, getTestPath("test-project"))
+		module, err := m.GetModuleByName("valuesModule")
+		Ω(err).Should(Succeed())
+		m.ResolveProperties(module, defaultEnvFileName)
+		Ω(module.Properties["nullInString"]).Should(Equal(`config: {"empty":null}`))
+		Ω(module.Properties["intValue"]).Should(Equal(2))
+		Ω(module.Properties["mapValue"]).Should(Equal(map[string]interface{}{"field": "abc"}))
+		Ω(module.Properties["mapInString"]).Should(Equal("config: ${config}"))
+		Ω(module.Properties["intInString"]).Should(Equal("instances: ${instances}"))
+	})
+
+	It("serializes the whole values as JSON in the environment", func() {
+		result, _, err := Resolve(getTestPath("test-project"), "valuesModule", getTestPath("test-project", "mtaValues.yaml"), nil, "", Options{DeployerFormat: true})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["intValue"]).Should(Equal("2"))
+		Ω(result.Properties["mapValue"]).Should(Equal(`{"field":"abc"}`))
+		Ω(result.Properties["mapInString"]).Should(Equal("config: {enabled=true, ratio=0.5, size=10, tags=[a, b]}"))
+	})
+})
+
+var _ = Describe("stringifyValue", func() {
+	It("renders nested values", func() {
+		Ω(stringifyValue(map[interface{}]interface{}{
+			"b": []interface{}{1, map[string]interface{}{"c": nil}},
+			"a": "x",
+		})).Should(Equal("{a=x, b=[1, {c=null}]}"))
+	})
+	It("renders floating point numbers", func() {
+		Ω(stringifyValue(1.5)).Should(Equal("1.5"))
+		Ω(stringifyValue(float64(3))).Should(Equal("3.0"))
+		Ω(stringifyValue(float32(2))).Should(Equal("2.0"))
+	})
+	It("renders lists of property sets", func() {
+		Ω(stringifyValue([]map[string]interface{}{{"a": "b"}})).Should(Equal("[{a=b}]"))
+	})
 })
 
 var _ = Describe("getParameter", func() {
@@ -305,6 +761,26 @@ var _ = Describe("getParameter", func() {
 		res := resolver.getParameter(nil, &source, nil, "param1")
 		Ω(res).Should(Equal("${param1}"))
 	})
+	It("parameter found in source parameters but isn't a string keeps its type in the deployer format", func() {
+		resolver := MTAResolver{
+			DeployerFormat: true,
+			context: &ResolveContext{
+				modules:   map[string]map[string]string{},
+				resources: map[string]map[string]string{},
+			},
+		}
+		source := mtaSource{
+			Parameters: map[string]interface{}{
+				"param1": struct {
+					a string
+				}{a: "a"},
+			},
+		}
+		res := resolver.getParameter(nil, &source, nil, "param1")
+		Ω(res).Should(Equal(struct {
+			a string
+		}{a: "a"}))
+	})
 	It("parameter found in the module (defined by the source) of the context", func() {
 		resolver := MTAResolver{
 			context: &ResolveContext{
@@ -348,7 +824,7 @@ var _ = Describe("getParameter", func() {
 			Name: "module1",
 		}
 
-		res := resolver.getParameter(&mod, nil, nil, "param1")
+		res := resolver.getParameter(newModuleSource(&mod), nil, nil, "param1")
 		Ω(res).Should(Equal("value1"))
 	})
 	It("parameter found on the MTA root scope", func() {
@@ -424,7 +900,7 @@ var _ = Describe("resolvePlaceholders", func() {
 var _ = Describe("getVariableValue", func() {
 	It("missing required prefix", func() {
 		resolver := MTAResolver{messages: []string{}}
-		res := resolver.getVariableValue(nil, nil, "var_without_prefix")
+		res := resolver.getVariableValue(nil, nil, nil, "var_without_prefix")
 		Ω(res).Should(Equal("~{var_without_prefix}"))
 		Ω(resolver.messages).Should(Equal([]string{fmt.Sprintf(missingPrefixMsg, "var_without_prefix")}))
 	})
@@ -440,18 +916,33 @@ var _ = Describe("getVariableValue", func() {
 				},
 			},
 		}
-		res := resolver.getVariableValue(nil, nil, "provider/var")
+		res := resolver.getVariableValue(nil, nil, nil, "provider/var")
 		Ω(res).Should(Equal("~{var}"))
 		Ω(resolver.messages).Should(Equal([]string{"Missing configuration id/var"}))
 	})
 })
 
-var _ = Describe("parseNextVariable", func() {
-	It("double end sign", func() {
-		start, end, whole := parseNextVariable(0, "a ~{{var1}}", "~")
-		Ω(start).Should(Equal(2))
-		Ω(end).Should(Equal("{var1}"))
-		Ω(whole).Should(BeFalse())
+var _ = Describe("resolveString", func() {
+	It("keeps an expression start at the end of the value", func() {
+		resolver := NewMTAResolver(&mta.MTA{}, "")
+		Ω(resolver.resolveString(nil, nil, nil, "abc ~{")).Should(Equal("abc ~{"))
+		Ω(resolver.resolvePlaceholdersString(nil, nil, nil, "abc ${")).Should(Equal("abc ${"))
+		Ω(resolver.messages).Should(BeEmpty())
+	})
+	It("keeps an expression in double braces", func() {
+		resolver := NewMTAResolver(&mta.MTA{}, "")
+		Ω(resolver.resolveString(nil, nil, nil, "a ~{{var1}}")).Should(Equal("a ~{{var1}}"))
+		Ω(resolver.resolveString(nil, nil, nil, "~{{var1}}")).Should(Equal("~{{var1}}"))
+		Ω(resolver.resolvePlaceholdersString(nil, nil, nil, "a ${{par1}}")).Should(Equal("a ${{par1}}"))
+		Ω(resolver.messages).Should(BeEmpty())
+		Ω(resolver.errors).Should(BeEmpty())
+	})
+	It("does not resolve escaped expressions", func() {
+		resolver := NewMTAResolver(&mta.MTA{Parameters: map[string]interface{}{"a": "b"}}, "")
+		value := resolver.resolveString(nil, nil, nil, `\~{x/y} \${a} ${a}`)
+		Ω(value).Should(Equal(`\~{x/y} \${a} ${a}`))
+		Ω(resolver.resolvePlaceholders(nil, nil, nil, value)).Should(Equal("~{x/y} ${a} b"))
+		Ω(resolver.messages).Should(BeEmpty())
 	})
 })
 
@@ -482,3 +973,67 @@ func mockEnvGetterExt() []string {
 	result = append(result, "env_var1=vvv")
 	return result
 }
+
+var _ = Describe("Resolve with cycles", func() {
+	wd := getTestPath("test-project")
+	yamlPath := getTestPath("test-project", "mtaCycles.yaml")
+
+	It("reports the cycle path of cyclic references", func() {
+		envGetter = mockEnvGetter
+		result, _, err := Resolve(wd, "cycleModule", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["paramCycle"]).Should(Equal("host.${host}"))
+		Ω(result.Properties["mtaCycle"]).Should(Equal("${a}"))
+		Ω(result.Properties["setCycle"]).Should(Equal("x-~{set1/a}"))
+		Ω(result.Properties["nested"]).Should(Equal("https://host:8080"))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "${cycleModule/host}",
+			"${cycleModule/host} -> ${cycleModule/domain} -> ${cycleModule/host}")))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "${a}", "${a} -> ${b} -> ${a}")))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "~{set1/a}", "~{set1/a} -> ~{set2/b} -> ~{set1/a}")))
+		Ω(result.Errors).Should(BeNil())
+	})
+
+	It("returns the unresolved values as errors in strict mode", func() {
+		envGetter = mockEnvGetter
+		result, _, err := Resolve(wd, "cycleModule", yamlPath, nil, "", Options{Strict: true})
+		Ω(err).Should(Succeed())
+		location := func(requires string, name string) ValueLocation {
+			return ValueLocation{EntityType: "module", Entity: "cycleModule", Requires: requires, Section: propertiesSection, Name: name}
+		}
+		Ω(result.Errors).Should(ContainElement(&ResolveError{
+			ValueLocation: location("", "setCycle"),
+			Kind:          CyclicReferenceError,
+			Expression:    "~{set1/a}",
+			Cycle:         []string{"~{set1/a}", "~{set2/b}", "~{set1/a}"},
+			Message:       fmt.Sprintf(cyclicReferenceMsg, "~{set1/a}", "~{set1/a} -> ~{set2/b} -> ~{set1/a}"),
+		}))
+		Ω(result.Errors).Should(ContainElement(&ResolveError{
+			ValueLocation: location("", "missing"),
+			Kind:          UnresolvedPlaceholderError,
+			Expression:    "${unknown}",
+			Message:       fmt.Sprintf(missingParameterMsg, "${unknown}"),
+		}))
+		Ω(result.Errors).Should(ContainElement(&ResolveError{
+			ValueLocation: location("", "unknownSet"),
+			Kind:          UnresolvedVariableError,
+			Expression:    "~{unknownSet/x}",
+			Message:       fmt.Sprintf(unresolvedVariableMsg, "~{unknownSet/x}"),
+		}))
+		Ω(result.Errors).Should(ContainElement(&ResolveError{
+			ValueLocation: location("config", "configProp"),
+			Kind:          MissingConfigurationError,
+			Expression:    "~{missing}",
+			Message:       "Missing configuration com.acme:provider/missing",
+		}))
+		for _, resolveError := range result.Errors {
+			Ω(resolveError.Name).ShouldNot(Equal("nested"))
+		}
+	})
+
+	It("returns no errors in strict mode when all the values are resolved", func() {
+		envGetter = mockEnvGetter
+		result, _, err := Resolve(wd, "srvModule", getTestPath("test-project", "mtaEntities.yaml"), nil, "", Options{Strict: true})
+		Ω(err).Should(Succeed())
+		Ω(result.Errors).Should(BeEmpty())
+	})
+})
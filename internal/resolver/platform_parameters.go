@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/SAP/cloud-mta/mta"
@@ -25,21 +26,30 @@ type ResolveContext struct {
 	global    map[string]string
 	modules   map[string]map[string]string
 	resources map[string]map[string]string
+	// origins holds the environment variable or environment file of each value in the context, by its key
+	origins map[string]string
 }
 
-func (m *MTAResolver) addServiceNames(module *mta.Module) {
+func (m *MTAResolver) addServiceNames() {
 	vcapServices := m.getvcapServicesFromEnv()
 	if vcapServices == nil {
 		return
 	}
 
 	for _, resource := range m.Resources {
+		// the deployer doesn't create the inactive resources, so they don't have service instances
+		if !resource.IsActive() {
+			continue
+		}
 		resCtx := m.context.resources[resource.Name]
 
 		//try to find the service-name in VCAP_SERVICES
 		serviceName := findServiceInvcapServices(vcapServices, resource)
 		if len(serviceName) > 0 {
 			resCtx["service-name"] = serviceName
+			if m.context.origins != nil {
+				m.context.origins[resource.Name+"/service-name"] = fmt.Sprintf(vcapOrigin, m.context.origins["VCAP_SERVICES"])
+			}
 		}
 	}
 }
@@ -0,0 +1,70 @@
+package resolver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stringifyValue renders a value the way the deployer renders it inside a string.
+// The deployer is implemented in Java and uses the toString representation of the value, for example:
+//
+//	map:    {field1=abc, field2=1}
+//	list:   [abc, 1, true]
+//	number: 1 (integers) or 1.5, 1.0 (floating point numbers)
+//	null:   null
+//
+// The deployer keeps the order of the map fields from the descriptor; since it is not available after unmarshalling,
+// the map fields are sorted by name.
+func stringifyValue(value interface{}) string {
+	switch v := convertToJSONSafe(value).(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, key := range keys {
+			fields = append(fields, key+"="+stringifyValue(v[key]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case []interface{}:
+		elements := make([]string, 0, len(v))
+		for _, element := range v {
+			elements = append(elements, stringifyValue(element))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case []map[string]interface{}:
+		elements := make([]string, 0, len(v))
+		for _, element := range v {
+			elements = append(elements, stringifyValue(element))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case float64:
+		return stringifyFloat(v)
+	case float32:
+		return stringifyFloat(float64(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// stringifyFloat renders a floating point number like Java's Double.toString for the common cases:
+// a floating point number always has a fraction part
+func stringifyFloat(value float64) string {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return fmt.Sprint(value)
+	}
+	str := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(str, ".") {
+		str += ".0"
+	}
+	return str
+}
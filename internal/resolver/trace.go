@@ -0,0 +1,162 @@
+package resolver
+
+import "fmt"
+
+// Scopes which are searched for the value of a variable or placeholder
+const (
+	// ContextScope holds the values configured externally for an entity (e.g. "<module>/<name>" in the environment)
+	ContextScope = "context"
+	// ParametersScope holds the parameters of an entity
+	ParametersScope = "parameters"
+	// PropertiesScope holds the properties of a provided property set or a resource
+	PropertiesScope = "properties"
+	// RequiresParametersScope holds the parameters of the requires section
+	RequiresParametersScope = "requires parameters"
+	// MtaParametersScope holds the global parameters of the MTA
+	MtaParametersScope = "mta parameters"
+	// GlobalContextScope holds the global values configured externally (environment variables and the environment file)
+	GlobalContextScope = "global context"
+)
+
+const (
+	envVarOrigin  = `environment variable "%s"`
+	envFileOrigin = `"%s" in the "%s" file`
+	vcapOrigin    = `"VCAP_SERVICES" (%s)`
+)
+
+// ValueLocation is the location of a property or parameter value in the MTA
+type ValueLocation struct {
+	// EntityType is the type of the entity the value belongs to: module, resource or hook
+	EntityType string `json:"entityType"`
+	// Entity is the name of the entity; a hook is named in the "<module>/<hook>" format
+	Entity string `json:"entity"`
+	// Requires is the name of the requires section, if the value is in a requires section
+	Requires string `json:"requires,omitempty"`
+	// Section is the section of the value: properties or parameters
+	Section string `json:"section"`
+	Name    string `json:"name"`
+}
+
+func newValueLocation(entity *mtaSource, requires string, section string, name string) ValueLocation {
+	entityType, entityName := describeSource(entity)
+	return ValueLocation{EntityType: entityType, Entity: entityName, Requires: requires, Section: section, Name: name}
+}
+
+// ValueTrace is the resolution trace of a property or parameter value
+type ValueTrace struct {
+	ValueLocation
+	Value         interface{}          `json:"value"`
+	Substitutions []*SubstitutionTrace `json:"substitutions,omitempty"`
+}
+
+// SubstitutionTrace describes how a variable or placeholder in a value was substituted
+type SubstitutionTrace struct {
+	// Expression is the variable or placeholder, e.g. "~{db/url}" or "${default-url}"
+	Expression string      `json:"expression"`
+	Resolved   bool        `json:"resolved"`
+	Value      interface{} `json:"value,omitempty"`
+	// Supplier is the lookup which supplied the value, if it was resolved
+	Supplier *LookupTrace `json:"supplier,omitempty"`
+	// Lookups is the chain of lookups tried, in order
+	Lookups []*LookupTrace `json:"lookups"`
+	// Substitutions are the substitutions done in the supplied value, e.g. placeholders in a provided property
+	Substitutions []*SubstitutionTrace `json:"substitutions,omitempty"`
+}
+
+// LookupTrace is a single lookup in the chain of scopes searched for a variable or placeholder
+type LookupTrace struct {
+	Scope      string `json:"scope"`
+	EntityType string `json:"entityType,omitempty"`
+	Entity     string `json:"entity,omitempty"`
+	Found      bool   `json:"found"`
+	// Origin is the environment variable or environment file which supplied a value from the context
+	Origin string `json:"origin,omitempty"`
+}
+
+// tracer records the resolution trace. All its methods can be called on a nil tracer, which records nothing.
+type tracer struct {
+	values []*ValueTrace
+	// substitutions is the list the next substitution is added to
+	substitutions *[]*SubstitutionTrace
+	// current is the substitution which is being resolved; lookups are added to it
+	current *SubstitutionTrace
+}
+
+type tracerState struct {
+	substitutions *[]*SubstitutionTrace
+	current       *SubstitutionTrace
+}
+
+func (t *tracer) beginValue(location ValueLocation) *ValueTrace {
+	if t == nil {
+		return nil
+	}
+	value := &ValueTrace{ValueLocation: location}
+	t.values = append(t.values, value)
+	t.substitutions = &value.Substitutions
+	t.current = nil
+	return value
+}
+
+func (t *tracer) endValue(value *ValueTrace, resolvedValue interface{}) {
+	if t == nil {
+		return
+	}
+	value.Value = resolvedValue
+	t.substitutions = nil
+	t.current = nil
+}
+
+func (t *tracer) beginSubstitution(expression string) tracerState {
+	if t == nil {
+		return tracerState{}
+	}
+	state := tracerState{t.substitutions, t.current}
+	substitution := &SubstitutionTrace{Expression: expression, Lookups: []*LookupTrace{}}
+	if t.substitutions != nil {
+		*t.substitutions = append(*t.substitutions, substitution)
+	}
+	t.substitutions = &substitution.Substitutions
+	t.current = substitution
+	return state
+}
+
+func (t *tracer) endSubstitution(state tracerState, value interface{}) {
+	if t == nil {
+		return
+	}
+	if t.current.Resolved {
+		t.current.Value = value
+	}
+	t.substitutions = state.substitutions
+	t.current = state.current
+}
+
+func (t *tracer) lookup(scope string, entity *mtaSource, found bool, origin string) {
+	if t == nil || t.current == nil {
+		return
+	}
+	lookup := &LookupTrace{Scope: scope, Found: found, Origin: origin}
+	lookup.EntityType, lookup.Entity = describeSource(entity)
+	t.current.Lookups = append(t.current.Lookups, lookup)
+	if found && !t.current.Resolved {
+		t.current.Resolved = true
+		t.current.Supplier = lookup
+	}
+}
+
+// describeSource returns the type and name of the entity of the source
+func describeSource(source *mtaSource) (string, string) {
+	if source == nil {
+		return "", ""
+	}
+	switch source.Type {
+	case moduleType:
+		return "module", source.Name
+	case resourceType:
+		return "resource", source.Name
+	case hookType:
+		return "hook", fmt.Sprintf("%s/%s", source.Module.Name, source.Name)
+	}
+	return "", source.Name
+}
@@ -0,0 +1,125 @@
+package resolver
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func findValueTrace(trace []*ValueTrace, requires string, section string, name string) *ValueTrace {
+	for _, value := range trace {
+		if value.Requires == requires && value.Section == section && value.Name == name {
+			return value
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Resolve with explain", func() {
+	wd := getTestPath("test-project")
+	yamlPath := getTestPath("test-project", "mtaEntities.yaml")
+
+	It("does not return the trace when explain is not requested", func() {
+		envGetter = mockEnvGetter
+		result, _, err := Resolve(wd, "srvModule", yamlPath, nil, "", Options{})
+		Ω(err).Should(Succeed())
+		Ω(result.Trace).Should(BeNil())
+	})
+
+	It("returns the scope which supplied each substituted placeholder and variable", func() {
+		envGetter = mockEnvGetter
+		result, _, err := Resolve(wd, "srvModule", yamlPath, nil, "", Options{Explain: true})
+		Ω(err).Should(Succeed())
+		Ω(len(result.Trace)).Should(Equal(3))
+
+		moduleProp := findValueTrace(result.Trace, "", propertiesSection, "moduleProp")
+		Ω(moduleProp).ShouldNot(BeNil())
+		Ω(moduleProp.EntityType).Should(Equal("module"))
+		Ω(moduleProp.Entity).Should(Equal("srvModule"))
+		Ω(moduleProp.Value).Should(Equal("512M"))
+		Ω(len(moduleProp.Substitutions)).Should(Equal(1))
+		Ω(*moduleProp.Substitutions[0]).Should(Equal(SubstitutionTrace{
+			Expression: "${memory}",
+			Resolved:   true,
+			Value:      "512M",
+			Supplier:   &LookupTrace{Scope: ParametersScope, EntityType: "module", Entity: "srvModule", Found: true},
+			Lookups:    []*LookupTrace{{Scope: ParametersScope, EntityType: "module", Entity: "srvModule", Found: true}},
+		}))
+
+		bindingName := findValueTrace(result.Trace, "dbResource", parametersSection, "binding-name")
+		Ω(bindingName).ShouldNot(BeNil())
+		Ω(bindingName.Value).Should(Equal("db-service_schema-binding"))
+		Ω(len(bindingName.Substitutions)).Should(Equal(1))
+		schema := bindingName.Substitutions[0]
+		Ω(schema.Expression).Should(Equal("~{schema}"))
+		Ω(schema.Value).Should(Equal("db-service_schema"))
+		Ω(*schema.Supplier).Should(Equal(LookupTrace{Scope: PropertiesScope, EntityType: "resource", Entity: "dbResource", Found: true}))
+		// The placeholder in the provided property is resolved in the scope of the resource
+		Ω(len(schema.Substitutions)).Should(Equal(1))
+		Ω(schema.Substitutions[0].Expression).Should(Equal("${service-name}"))
+		Ω(schema.Substitutions[0].Lookups).Should(Equal([]*LookupTrace{
+			{Scope: ContextScope, EntityType: "resource", Entity: "dbResource", Found: false},
+			{Scope: ParametersScope, EntityType: "resource", Entity: "dbResource", Found: true},
+		}))
+	})
+
+	It("returns the chain of lookups for an unresolved placeholder", func() {
+		envGetter = mockEnvGetter
+		result, _, err := ResolveHook(wd, "srvModule", "migrate", yamlPath, nil, "", Options{Explain: true})
+		Ω(err).Should(Succeed())
+		timeout := findValueTrace(result.Trace, "dbResource", parametersSection, "timeout")
+		Ω(timeout).ShouldNot(BeNil())
+		Ω(timeout.EntityType).Should(Equal("hook"))
+		Ω(timeout.Entity).Should(Equal("srvModule/migrate"))
+		Ω(timeout.Value).Should(Equal("${task-timeout}"))
+		Ω(len(timeout.Substitutions)).Should(Equal(1))
+		Ω(*timeout.Substitutions[0]).Should(Equal(SubstitutionTrace{
+			Expression: "${task-timeout}",
+			Resolved:   false,
+			Lookups: []*LookupTrace{
+				{Scope: ContextScope, EntityType: "resource", Entity: "dbResource", Found: false},
+				{Scope: ParametersScope, EntityType: "resource", Entity: "dbResource", Found: false},
+				{Scope: RequiresParametersScope, Found: false},
+				{Scope: ParametersScope, EntityType: "hook", Entity: "srvModule/migrate", Found: false},
+				{Scope: ParametersScope, EntityType: "module", Entity: "srvModule", Found: false},
+				{Scope: ContextScope, EntityType: "module", Entity: "srvModule", Found: false},
+				{Scope: MtaParametersScope, Found: false},
+				{Scope: GlobalContextScope, Found: false},
+			},
+		}))
+	})
+
+	It("returns the environment variable which supplied a value", func() {
+		envGetter = func() []string {
+			return []string{"task-timeout=5m"}
+		}
+		result, _, err := ResolveHook(wd, "srvModule", "migrate", yamlPath, nil, "", Options{Explain: true})
+		Ω(err).Should(Succeed())
+		timeout := findValueTrace(result.Trace, "dbResource", parametersSection, "timeout")
+		Ω(timeout).ShouldNot(BeNil())
+		Ω(timeout.Value).Should(Equal("5m"))
+		Ω(*timeout.Substitutions[0].Supplier).Should(Equal(LookupTrace{
+			Scope:  GlobalContextScope,
+			Found:  true,
+			Origin: fmt.Sprintf(envVarOrigin, "task-timeout"),
+		}))
+	})
+
+	It("returns the environment file which supplied a value", func() {
+		envGetter = func() []string {
+			return []string{"task-timeout=5m"}
+		}
+		result, _, err := ResolveHook(wd, "srvModule", "migrate", yamlPath, nil, ".envTrace", Options{Explain: true})
+		Ω(err).Should(Succeed())
+		timeout := findValueTrace(result.Trace, "dbResource", parametersSection, "timeout")
+		Ω(timeout).ShouldNot(BeNil())
+		Ω(timeout.Value).Should(Equal("10m"))
+		Ω(*timeout.Substitutions[0].Supplier).Should(Equal(LookupTrace{
+			Scope:  GlobalContextScope,
+			Found:  true,
+			Origin: fmt.Sprintf(envFileOrigin, "task-timeout", filepath.Join(wd, "srv", ".envTrace")),
+		}))
+	})
+})
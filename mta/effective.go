@@ -0,0 +1,195 @@
+package mta
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// ModuleInheritanceKind is the kind of the errors in the inheritance of a module type by a module
+	ModuleInheritanceKind = "module"
+	// ResourceInheritanceKind is the kind of the errors in the inheritance of a resource type by a resource
+	ResourceInheritanceKind = "resource"
+	// ModuleTypeInheritanceKind is the kind of the errors in the "extends" chain of a module type
+	ModuleTypeInheritanceKind = "module type"
+	// ResourceTypeInheritanceKind is the kind of the errors in the "extends" chain of a resource type
+	ResourceTypeInheritanceKind = "resource type"
+
+	typeCycleErrorMsg                 = `the "%s" %s extends itself in its "extends" chain`
+	inheritModulePropertiesErrorMsg   = `could not inherit the properties of the "%s" module type in the "%s" module`
+	inheritModuleParametersErrorMsg   = `could not inherit the parameters of the "%s" module type in the "%s" module`
+	inheritResourcePropertiesErrorMsg = `could not inherit the properties of the "%s" resource type in the "%s" resource`
+	inheritResourceParametersErrorMsg = `could not inherit the parameters of the "%s" resource type in the "%s" resource`
+)
+
+// InheritanceError is an error in the inheritance of the module types and the resource types which are declared in the MTA
+type InheritanceError struct {
+	// Kind - the kind of the entity in which the error is found: module, resource, module type or resource type
+	Kind string
+	// Name - the name of the entity
+	Name string
+	err  error
+}
+
+func (e InheritanceError) Error() string {
+	return e.err.Error()
+}
+
+// typeLayer is a module type or a resource type which is declared in the MTA
+type typeLayer struct {
+	extends            string
+	properties         map[string]interface{}
+	propertiesMetaData map[string]MetaData
+	parameters         map[string]interface{}
+	parametersMetaData map[string]MetaData
+}
+
+// Effective returns the effective view of the MTA, in which the modules and the resources inherit the properties
+// and parameters of their types which are declared in the "module-types" and "resource-types" sections.
+// The values of a type are extended by the types which extend it, and then by the module or the resource,
+// as an MTA extension descriptor extends them: the values which are not overwritable in the metadata can't be
+// overwritten, unless they are empty. The metadata are inherited too.
+// The MTA is not changed; the modules and resources of the effective MTA are copies.
+func (mta *MTA) Effective() (*MTA, error) {
+	moduleTypes := make(map[string]typeLayer)
+	for _, moduleType := range mta.ModuleTypes {
+		moduleTypes[moduleType.Name] = typeLayer{moduleType.Extends, moduleType.Properties, moduleType.PropertiesMetaData,
+			moduleType.Parameters, moduleType.ParametersMetaData}
+	}
+	resourceTypes := make(map[string]typeLayer)
+	for _, resourceType := range mta.ResourceTypes {
+		resourceTypes[resourceType.Name] = typeLayer{resourceType.Extends, resourceType.Properties, resourceType.PropertiesMetaData,
+			resourceType.Parameters, resourceType.ParametersMetaData}
+	}
+
+	effective := *mta
+	effective.Modules = make([]*Module, 0, len(mta.Modules))
+	for _, module := range mta.Modules {
+		effectiveModule := *module
+		chain, err := getTypeChain(module.Type, moduleTypes, ModuleTypeInheritanceKind)
+		if err != nil {
+			return nil, err
+		}
+		if len(chain) > 0 {
+			err = inheritTypeChain(chain, &effectiveModule.Properties, &effectiveModule.PropertiesMetaData,
+				&effectiveModule.Parameters, &effectiveModule.ParametersMetaData,
+				inheritModulePropertiesErrorMsg, inheritModuleParametersErrorMsg, module.Type, module.Name)
+			if err != nil {
+				return nil, &InheritanceError{ModuleInheritanceKind, module.Name, err}
+			}
+		}
+		effective.Modules = append(effective.Modules, &effectiveModule)
+	}
+
+	effective.Resources = make([]*Resource, 0, len(mta.Resources))
+	for _, resource := range mta.Resources {
+		effectiveResource := *resource
+		chain, err := getTypeChain(resource.Type, resourceTypes, ResourceTypeInheritanceKind)
+		if err != nil {
+			return nil, err
+		}
+		if len(chain) > 0 {
+			err = inheritTypeChain(chain, &effectiveResource.Properties, &effectiveResource.PropertiesMetaData,
+				&effectiveResource.Parameters, &effectiveResource.ParametersMetaData,
+				inheritResourcePropertiesErrorMsg, inheritResourceParametersErrorMsg, resource.Type, resource.Name)
+			if err != nil {
+				return nil, &InheritanceError{ResourceInheritanceKind, resource.Name, err}
+			}
+		}
+		effective.Resources = append(effective.Resources, &effectiveResource)
+	}
+	return &effective, nil
+}
+
+// getTypeChain returns the declared type and the declared types which it extends, starting from the type
+// which doesn't extend a declared type. It returns an empty chain if the type is not declared.
+func getTypeChain(typeName string, types map[string]typeLayer, kind string) ([]typeLayer, error) {
+	var chain []typeLayer
+	visited := make(map[string]bool)
+	for name := typeName; ; {
+		layer, ok := types[name]
+		if !ok {
+			return chain, nil
+		}
+		if visited[name] {
+			return nil, &InheritanceError{kind, typeName, errors.Errorf(typeCycleErrorMsg, typeName, kind)}
+		}
+		visited[name] = true
+		chain = append([]typeLayer{layer}, chain...)
+		name = layer.extends
+	}
+}
+
+// inheritTypeChain replaces the properties and parameters of the entity with the values and metadata of the type chain,
+// extended by the values and metadata of the entity
+func inheritTypeChain(types []typeLayer, properties *map[string]interface{}, propertiesMetaData *map[string]MetaData,
+	parameters *map[string]interface{}, parametersMetaData *map[string]MetaData,
+	propertiesMsg string, parametersMsg string, typeName string, name string) error {
+	entity := typeLayer{"", *properties, *propertiesMetaData, *parameters, *parametersMetaData}
+	var effectiveProperties, effectiveParameters map[string]interface{}
+	var effectivePropertiesMetaData, effectiveParametersMetaData map[string]MetaData
+	for _, layer := range append(types, entity) {
+		err := chain().
+			extendMap(&effectiveProperties, effectivePropertiesMetaData, deepCopyMap(layer.properties), propertiesMsg, typeName, name).
+			extendMap(&effectiveParameters, effectiveParametersMetaData, deepCopyMap(layer.parameters), parametersMsg, typeName, name).
+			err
+		if err != nil {
+			return err
+		}
+		effectivePropertiesMetaData = extendMetaData(effectivePropertiesMetaData, layer.propertiesMetaData)
+		effectiveParametersMetaData = extendMetaData(effectiveParametersMetaData, layer.parametersMetaData)
+	}
+	*properties, *propertiesMetaData = effectiveProperties, effectivePropertiesMetaData
+	*parameters, *parametersMetaData = effectiveParameters, effectiveParametersMetaData
+	return nil
+}
+
+// extendMetaData returns the metadata extended with the other metadata. The metadata of the values which are not overwritable is kept.
+func extendMetaData(meta map[string]MetaData, ext map[string]MetaData) map[string]MetaData {
+	if ext == nil {
+		return meta
+	}
+	result := make(map[string]MetaData, len(meta)+len(ext))
+	for key, metaData := range meta {
+		result[key] = metaData
+	}
+	for key, metaData := range ext {
+		if existing, ok := result[key]; ok && existing.OverWritable != nil && !*existing.OverWritable {
+			continue
+		}
+		result[key] = metaData
+	}
+	return result
+}
+
+// deepCopyMap returns a deep copy of the map, so extending it doesn't change the original values
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	return deepCopyValue(m).(map[string]interface{})
+}
+
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[key] = deepCopyValue(val)
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(v))
+		for key, val := range v {
+			res[key] = deepCopyValue(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			res[i] = deepCopyValue(val)
+		}
+		return res
+	default:
+		return v
+	}
+}
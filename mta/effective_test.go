@@ -0,0 +1,207 @@
+package mta
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Effective", func() {
+	It("applies the declared types and their extends chains to the modules and resources", func() {
+		mta, err := Unmarshal([]byte(`
+ID: types
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: java-service
+   properties:
+     profile: prod
+     nested:
+       b: 2
+   parameters:
+     memory: 2G
+ - name: ui
+   type: html5
+
+module-types:
+ - name: java-service
+   extends: java-base
+   properties:
+     profile: dev
+     nested:
+       a: 1
+   properties-metadata:
+     profile:
+       datatype: str
+ - name: java-base
+   extends: java.tomcat
+   parameters:
+     buildpack: sap_java_buildpack
+     memory: 1G
+   parameters-metadata:
+     buildpack:
+       overwritable: false
+`))
+		Ω(err).Should(Succeed())
+		effective, err := mta.Effective()
+		Ω(err).Should(Succeed())
+
+		overwritable := false
+		Ω(effective.Modules[0].Properties).Should(Equal(map[string]interface{}{
+			"profile": "prod",
+			"nested":  map[string]interface{}{"a": 1, "b": 2},
+		}))
+		Ω(effective.Modules[0].PropertiesMetaData).Should(Equal(map[string]MetaData{"profile": {Datatype: "str"}}))
+		Ω(effective.Modules[0].Parameters).Should(Equal(map[string]interface{}{"buildpack": "sap_java_buildpack", "memory": "2G"}))
+		Ω(effective.Modules[0].ParametersMetaData).Should(Equal(map[string]MetaData{"buildpack": {OverWritable: &overwritable}}))
+		// Modules of types which are not declared are not changed
+		Ω(effective.Modules[1]).Should(Equal(mta.Modules[1]))
+		// The MTA is not changed
+		Ω(mta.Modules[0].Properties).Should(Equal(map[string]interface{}{"profile": "prod", "nested": map[interface{}]interface{}{"b": 2}}))
+		Ω(mta.Modules[0].Parameters).Should(Equal(map[string]interface{}{"memory": "2G"}))
+		Ω(mta.ModuleTypes[0].Properties).Should(Equal(map[string]interface{}{"profile": "dev", "nested": map[interface{}]interface{}{"a": 1}}))
+	})
+
+	It("applies the declared resource types to the resources", func() {
+		mta, err := Unmarshal([]byte(`
+ID: types
+_schema-version: '3.2'
+version: 0.0.1
+
+resources:
+ - name: db
+   type: hana
+   parameters:
+     service-name: db-service
+ - name: other
+   type: org.cloudfoundry.managed-service
+
+resource-types:
+ - name: hana
+   extends: org.cloudfoundry.managed-service
+   parameters:
+     service: hana
+   properties:
+     kind: db
+`))
+		Ω(err).Should(Succeed())
+		effective, err := mta.Effective()
+		Ω(err).Should(Succeed())
+		Ω(effective.Resources[0].Parameters).Should(Equal(map[string]interface{}{"service": "hana", "service-name": "db-service"}))
+		Ω(effective.Resources[0].Properties).Should(Equal(map[string]interface{}{"kind": "db"}))
+		Ω(effective.Resources[1]).Should(Equal(mta.Resources[1]))
+	})
+
+	It("fills the empty values of the type which are not overwritable", func() {
+		mta, err := Unmarshal([]byte(`
+ID: types
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: service
+   parameters:
+     route: srv.example.com
+
+module-types:
+ - name: service
+   parameters:
+     route:
+   parameters-metadata:
+     route:
+       overwritable: false
+       optional: false
+`))
+		Ω(err).Should(Succeed())
+		effective, err := mta.Effective()
+		Ω(err).Should(Succeed())
+		Ω(effective.Modules[0].Parameters).Should(Equal(map[string]interface{}{"route": "srv.example.com"}))
+	})
+
+	It("fails when a module overwrites a value of its type which is not overwritable", func() {
+		mta, err := Unmarshal([]byte(`
+ID: types
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: service
+   parameters:
+     buildpack: other
+
+module-types:
+ - name: service
+   parameters:
+     buildpack: sap_java_buildpack
+   parameters-metadata:
+     buildpack:
+       overwritable: false
+`))
+		Ω(err).Should(Succeed())
+		_, err = mta.Effective()
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(inheritModuleParametersErrorMsg, "service", "srv")))
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(overwriteNonOverwritableErrorMsg, "buildpack")))
+		inheritanceErr, ok := err.(*InheritanceError)
+		Ω(ok).Should(BeTrue())
+		Ω(inheritanceErr.Kind).Should(Equal(ModuleInheritanceKind))
+		Ω(inheritanceErr.Name).Should(Equal("srv"))
+	})
+
+	It("fails when a type extends itself", func() {
+		mta, err := Unmarshal([]byte(`
+ID: types
+_schema-version: '3.2'
+version: 0.0.1
+
+resources:
+ - name: db
+   type: a
+
+resource-types:
+ - name: a
+   extends: b
+ - name: b
+   extends: a
+`))
+		Ω(err).Should(Succeed())
+		_, err = mta.Effective()
+		Ω(err).Should(MatchError(fmt.Sprintf(typeCycleErrorMsg, "a", ResourceTypeInheritanceKind)))
+		inheritanceErr, ok := err.(*InheritanceError)
+		Ω(ok).Should(BeTrue())
+		Ω(inheritanceErr.Kind).Should(Equal(ResourceTypeInheritanceKind))
+		Ω(inheritanceErr.Name).Should(Equal("a"))
+	})
+})
+
+var _ = Describe("GetEffectiveModules", func() {
+	It("returns the modules with the values of their types", func() {
+		modules, messages, err := GetEffectiveModules(getTestPath("mtaTypes.yaml"), nil)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(modules).Should(HaveLen(2))
+		Ω(modules[0].Properties).Should(Equal(map[string]interface{}{"profile": "prod", "JBP_CONFIG": "tomcat"}))
+		Ω(modules[0].Parameters).Should(Equal(map[string]interface{}{"memory": "2G", "buildpack": "sap_java_buildpack"}))
+		Ω(modules[1].Properties).Should(BeNil())
+	})
+
+	It("returns an error when the mta.yaml does not exist", func() {
+		_, _, err := GetEffectiveModules(getTestPath("result", "mta.yaml"), nil)
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetEffectiveResources", func() {
+	It("returns the resources with the values of their types", func() {
+		resources, messages, err := GetEffectiveResources(getTestPath("mtaTypes.yaml"), nil)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(resources).Should(HaveLen(1))
+		Ω(resources[0].Parameters).Should(Equal(map[string]interface{}{"service": "hana", "service-plan": "hdi-shared", "service-name": "db-service"}))
+	})
+})
@@ -110,6 +110,20 @@ func mergeWithExtensionFiles(mta *MTA, extensions []string, mtaPath string) *Ext
 	return nil
 }
 
+// SortExtensionFiles returns the paths of the MTA extension descriptors in the order in which they are merged into the MTA,
+// which is the order of their 'extends' chain. The chain is verified as in the merge.
+func SortExtensionFiles(extensions []string, mtaID string, mtaPath string) ([]string, error) {
+	extensionsDetails, extErr := getSortedExtensions(extensions, mtaID, mtaPath)
+	if extErr != nil {
+		return nil, extErr
+	}
+	result := make([]string, len(extensionsDetails))
+	for i, extDetails := range extensionsDetails {
+		result[i] = extDetails.fileName
+	}
+	return result, nil
+}
+
 func getSortedExtensions(extensionFileNames []string, mtaID string, mtaPath string) ([]extensionDetails, *ExtensionError) {
 	// Parse all extension files and put them in a slice of extension details (the extension with the file name)
 	extensions, err := parseExtensionsWithDetails(extensionFileNames)
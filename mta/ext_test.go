@@ -2688,6 +2688,25 @@ var _ = Describe("MTA Extensions", func() {
 		)
 	})
 
+	var _ = Describe("SortExtensionFiles", func() {
+		wd, _ := os.Getwd()
+		folderPath := filepath.Join(wd, "testdata", "testext")
+		mtaYamlPath := filepath.Join(folderPath, "mta.yaml")
+
+		It("returns the extension files sorted by extends chain order", func() {
+			extensions := []string{filepath.Join(folderPath, "third.mtaext"), filepath.Join(folderPath, "cf-mtaext.yaml"), filepath.Join(folderPath, "other.mtaext")}
+			Ω(SortExtensionFiles(extensions, "mtahtml5", mtaYamlPath)).Should(Equal([]string{
+				filepath.Join(folderPath, "cf-mtaext.yaml"), filepath.Join(folderPath, "other.mtaext"), filepath.Join(folderPath, "third.mtaext"),
+			}))
+		})
+		It("fails when the extends chain is not valid", func() {
+			extensions := []string{filepath.Join(folderPath, "other.mtaext")}
+			_, err := SortExtensionFiles(extensions, "mtahtml5", mtaYamlPath)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(extendsMsg, filepath.Join(folderPath, "other.mtaext"), "mtahtml5ext"))
+		})
+	})
+
 	ptr := func(str string) *string {
 		return &str
 	}
@@ -0,0 +1,139 @@
+package mta
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+const (
+	emptyIncludeNameErrorMsg = `could not expand the includes of the %s; an include does not have a name`
+	emptyIncludePathErrorMsg = `could not expand the "%s" include of the %s; the include does not have a path`
+	readIncludeErrorMsg      = `could not expand the "%s" include of the %s`
+	parseIncludeErrorMsg     = `could not expand the "%s" include of the %s; the "%s" file is not a valid %s file`
+	includeNotMapErrorMsg    = `could not expand the "%s" include of the %s; the "%s" file does not contain a map of parameters`
+)
+
+// ExpandIncludes loads the files in the includes of the modules, the resources and their requires sections,
+// and injects the content of each file into the parameters, under the name of the include, the same as a builder does
+// when it generates the deployment descriptor. The include replaces a parameter with the same name.
+// The paths are relative to the workspace folder. The includes are removed from the MTA after they are expanded.
+// It returns an error if a file does not exist or is not a valid JSON or YAML file which contains a map.
+func ExpandIncludes(mta *MTA, workspaceDir string) error {
+	for _, module := range mta.Modules {
+		owner := fmt.Sprintf(`"%s" module`, module.Name)
+		err := expandIncludes(&module.Parameters, &module.Includes, workspaceDir, owner)
+		if err != nil {
+			return err
+		}
+		err = expandRequiresIncludes(&module.Requires, workspaceDir, owner)
+		if err != nil {
+			return err
+		}
+		if len(module.Hooks) > 0 {
+			module.Hooks = append([]Hook(nil), module.Hooks...)
+		}
+		for i := range module.Hooks {
+			hook := &module.Hooks[i]
+			err = expandRequiresIncludes(&hook.Requires, workspaceDir, fmt.Sprintf(`"%s" hook of the "%s" module`, hook.Name, module.Name))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, resource := range mta.Resources {
+		owner := fmt.Sprintf(`"%s" resource`, resource.Name)
+		err := expandIncludes(&resource.Parameters, &resource.Includes, workspaceDir, owner)
+		if err != nil {
+			return err
+		}
+		err = expandRequiresIncludes(&resource.Requires, workspaceDir, owner)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandRequiresIncludes expands the includes of the requires sections in a copy of the list, so a list which is
+// shared with another MTA (e.g. the MTA from which an effective MTA is created) is not changed
+func expandRequiresIncludes(requiresList *[]Requires, workspaceDir string, owner string) error {
+	if len(*requiresList) == 0 {
+		return nil
+	}
+	*requiresList = append([]Requires(nil), *requiresList...)
+	for i := range *requiresList {
+		requires := &(*requiresList)[i]
+		err := expandIncludes(&requires.Parameters, &requires.Includes, workspaceDir,
+			fmt.Sprintf(`"%s" requires section of the %s`, requires.Name, owner))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandIncludes injects the content of the files in the includes into a copy of the parameters and removes the includes.
+// The parameters are copied because the map can be shared with another MTA.
+func expandIncludes(parameters *map[string]interface{}, includes *[]Includes, workspaceDir string, owner string) error {
+	if len(*includes) == 0 {
+		return nil
+	}
+	expanded := make(map[string]interface{}, len(*parameters)+len(*includes))
+	for name, value := range *parameters {
+		expanded[name] = value
+	}
+	for _, include := range *includes {
+		content, err := ReadInclude(include, workspaceDir, owner)
+		if err != nil {
+			return err
+		}
+		expanded[include.Name] = content
+	}
+	*parameters = expanded
+	*includes = nil
+	return nil
+}
+
+// ReadInclude reads the file in the path of the include, relative to the workspace folder, and returns its content.
+// A file with the ".json" extension is parsed as JSON, and other files are parsed as YAML. The owner describes
+// the entity of the include in the errors, e.g. "srv" module.
+func ReadInclude(include Includes, workspaceDir string, owner string) (map[string]interface{}, error) {
+	if include.Name == "" {
+		return nil, errors.Errorf(emptyIncludeNameErrorMsg, owner)
+	}
+	if include.Path == "" {
+		return nil, errors.Errorf(emptyIncludePathErrorMsg, include.Name, owner)
+	}
+	path := include.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workspaceDir, path)
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, readIncludeErrorMsg, include.Name, owner)
+	}
+
+	var content interface{}
+	format := "YAML"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "JSON"
+		err = json.Unmarshal(data, &content)
+	} else {
+		err = yaml.Unmarshal(data, &content)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, parseIncludeErrorMsg, include.Name, owner, include.Path, format)
+	}
+	result, ok, _ := getMapValue(content)
+	if !ok {
+		return nil, errors.Errorf(includeNotMapErrorMsg, include.Name, owner, include.Path)
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package mta
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+var _ = Describe("ExpandIncludes", func() {
+	It("injects the content of the included files into the parameters under the include names", func() {
+		mta, _, err := GetMtaFromFile(getTestPath("includes", "mta.yaml"), nil, false)
+		Ω(err).Should(Succeed())
+		Ω(ExpandIncludes(mta, getTestPath("includes"))).Should(Succeed())
+
+		binding := map[string]interface{}{"permissions": map[string]interface{}{"read": true}}
+		module := mta.Modules[0]
+		Ω(module.Includes).Should(BeNil())
+		Ω(module.Parameters).Should(Equal(map[string]interface{}{
+			"memory": "256M",
+			"env": map[string]interface{}{
+				"NODE_ENV": "production",
+				"limits":   map[string]interface{}{"memory": "512M"},
+			},
+		}))
+		Ω(module.Requires[0].Includes).Should(BeNil())
+		Ω(module.Requires[0].Parameters).Should(Equal(map[string]interface{}{"config": binding}))
+		Ω(module.Hooks[0].Requires[0].Parameters).Should(Equal(map[string]interface{}{"config": binding}))
+		Ω(mta.Resources[0].Includes).Should(BeNil())
+		Ω(mta.Resources[0].Parameters).Should(Equal(map[string]interface{}{
+			"config": map[string]interface{}{"xsappname": "app", "tenant-mode": "dedicated"},
+		}))
+	})
+
+	It("doesn't change the MTA from which the effective MTA is created", func() {
+		mta, _, err := GetMtaFromFile(getTestPath("includes", "mta.yaml"), nil, false)
+		Ω(err).Should(Succeed())
+		effective, err := mta.Effective()
+		Ω(err).Should(Succeed())
+		Ω(ExpandIncludes(effective, getTestPath("includes"))).Should(Succeed())
+		Ω(effective.Modules[0].Parameters).Should(HaveKey("env"))
+		Ω(effective.Modules[0].Hooks[0].Requires[0].Parameters).Should(HaveKey("config"))
+
+		module := mta.Modules[0]
+		Ω(module.Includes).Should(HaveLen(1))
+		Ω(module.Parameters).Should(Equal(map[string]interface{}{"memory": "256M", "env": "old"}))
+		Ω(module.Requires[0].Includes).Should(HaveLen(1))
+		Ω(module.Requires[0].Parameters).Should(BeNil())
+		Ω(module.Hooks[0].Requires[0].Includes).Should(HaveLen(1))
+		Ω(module.Hooks[0].Requires[0].Parameters).Should(BeNil())
+		Ω(mta.Resources[0].Includes).Should(HaveLen(1))
+		Ω(mta.Resources[0].Parameters).Should(BeNil())
+	})
+
+	DescribeTable("fails when an include can't be expanded", func(include Includes, expectedMsg string) {
+		mta := &MTA{Modules: []*Module{{Name: "srv", Includes: []Includes{include}}}}
+		err := ExpandIncludes(mta, getTestPath("includes"))
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(expectedMsg))
+	},
+		Entry("without a name", Includes{Path: "config/env.yaml"},
+			fmt.Sprintf(emptyIncludeNameErrorMsg, `"srv" module`)),
+		Entry("without a path", Includes{Name: "env"},
+			fmt.Sprintf(emptyIncludePathErrorMsg, "env", `"srv" module`)),
+		Entry("file doesn't exist", Includes{Name: "env", Path: "config/unknown.yaml"},
+			fmt.Sprintf(readIncludeErrorMsg+": "+fs.PathNotFoundMsg, "env", `"srv" module`, getTestPath("includes", "config", "unknown.yaml"))),
+		Entry("invalid JSON", Includes{Name: "env", Path: "config/invalid.json"},
+			fmt.Sprintf(parseIncludeErrorMsg, "env", `"srv" module`, "config/invalid.json", "JSON")),
+		Entry("not a map", Includes{Name: "env", Path: "config/list.yaml"},
+			fmt.Sprintf(includeNotMapErrorMsg, "env", `"srv" module`, "config/list.yaml")),
+	)
+})
+
+var _ = Describe("GetModulesWithOptions", func() {
+	It("returns the modules with the expanded includes", func() {
+		modules, messages, err := GetModulesWithOptions(getTestPath("includes", "mta.yaml"), nil, GetOptions{ExpandIncludes: true})
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(modules[0].Parameters).Should(HaveKey("env"))
+		Ω(modules[0].Includes).Should(BeNil())
+	})
+
+	It("returns an error when the includes can't be expanded", func() {
+		_, _, err := GetModulesWithOptions(getTestPath("includes", "mta.yaml"), nil,
+			GetOptions{ExpandIncludes: true, WorkspaceDir: getTestPath("includes", "config")})
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetResourcesWithOptions", func() {
+	It("returns the resources with the expanded includes", func() {
+		resources, _, err := GetResourcesWithOptions(getTestPath("includes", "mta.yaml"), nil, GetOptions{ExpandIncludes: true})
+		Ω(err).Should(Succeed())
+		Ω(resources[0].Parameters).Should(HaveKeyWithValue("config", HaveKeyWithValue("xsappname", "app")))
+	})
+})
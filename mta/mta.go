@@ -83,6 +83,12 @@ func (resource *Resource) GetRequiresByName(name string) *Requires {
 	return nil
 }
 
+// IsActive returns true if the resource is active. A resource is active unless its "active" field is set to false;
+// the deployer doesn't create an inactive resource and ignores the requires sections which reference it.
+func (resource *Resource) IsActive() bool {
+	return resource.Active == nil || *resource.Active
+}
+
 // GetRequiresByName returns a specific requires by name
 func (hook *Hook) GetRequiresByName(name string) *Requires {
 	for i, r := range hook.Requires {
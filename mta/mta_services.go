@@ -17,7 +17,11 @@ import (
 	"github.com/SAP/cloud-mta/internal/logs"
 )
 
-const UnmarshalFailsMsg = `the "%s" file is not a valid MTA descriptor`
+const (
+	UnmarshalFailsMsg = `the "%s" file is not a valid MTA descriptor`
+
+	outsideWorkspaceRootMsg = `the "%s" path is outside of the "%s" workspace root folder`
+)
 
 func createMtaYamlFile(path string, mkDirs func(string, os.FileMode) error) (rerr error) {
 	folder := filepath.Dir(path)
@@ -93,6 +97,15 @@ func DeleteMta(path string) error {
 	return fs.DeleteDir(path)
 }
 
+// DeleteMtaInWorkspace - deletes the MTA if its folder is in the workspace root folder.
+// The workspace root isn't checked if it is empty.
+func DeleteMtaInWorkspace(workspaceRoot string, path string) error {
+	if err := CheckWorkspaceRoot(workspaceRoot, path); err != nil {
+		return err
+	}
+	return DeleteMta(path)
+}
+
 //AddModule - adds a new module.
 func AddModule(path string, moduleDataJSON string, marshal func(*MTA) ([]byte, error)) ([]string, error) {
 	mta, messages, err := GetMtaFromFile(filepath.Join(path), nil, false)
@@ -145,10 +158,95 @@ func GetResources(path string, extensions []string) ([]*Resource, []string, erro
 	return mta.Resources, messages, nil
 }
 
+// GetEffectiveModules - gets all modules, with the properties and parameters which they inherit from the module types
+// declared in the MTA.
+func GetEffectiveModules(path string, extensions []string) ([]*Module, []string, error) {
+	return GetModulesWithOptions(path, extensions, GetOptions{Effective: true})
+}
+
+// GetEffectiveResources - gets all resources, with the properties and parameters which they inherit from the resource types
+// declared in the MTA.
+func GetEffectiveResources(path string, extensions []string) ([]*Resource, []string, error) {
+	return GetResourcesWithOptions(path, extensions, GetOptions{Effective: true})
+}
+
+// GetOptions are the options of the view of the MTA which the get functions return
+type GetOptions struct {
+	// Effective - the modules and resources inherit the properties and parameters of the types declared in the MTA
+	Effective bool
+	// ExpandIncludes - the files in the includes are loaded into the parameters (see ExpandIncludes)
+	ExpandIncludes bool
+	// WorkspaceDir - the folder of the paths in the includes; the default folder is the folder of the mta.yaml file
+	WorkspaceDir string
+	// ActiveState - the "active" field of each resource is set to its active state after the extensions are merged
+	ActiveState bool
+}
+
+// GetModulesWithOptions - gets all modules, in the view of the MTA described by the options.
+func GetModulesWithOptions(path string, extensions []string, options GetOptions) ([]*Module, []string, error) {
+	mta, messages, err := getMtaViewFromFile(path, extensions, options)
+	if err != nil {
+		return nil, messages, err
+	}
+	return mta.Modules, messages, nil
+}
+
+// GetResourcesWithOptions - gets all resources, in the view of the MTA described by the options.
+func GetResourcesWithOptions(path string, extensions []string, options GetOptions) ([]*Resource, []string, error) {
+	mta, messages, err := getMtaViewFromFile(path, extensions, options)
+	if err != nil {
+		return nil, messages, err
+	}
+	if options.ActiveState {
+		setActiveState(mta.Resources)
+	}
+	return mta.Resources, messages, nil
+}
+
+// setActiveState sets the "active" field of the resources which don't set it, so it shows whether each resource is active
+func setActiveState(resources []*Resource) {
+	for _, resource := range resources {
+		active := resource.IsActive()
+		resource.Active = &active
+	}
+}
+
+// getMtaViewFromFile returns the view of the MTA merged with the extensions which is described by the options
+func getMtaViewFromFile(path string, extensions []string, options GetOptions) (*MTA, []string, error) {
+	mta, messages, err := GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	if options.Effective {
+		mta, err = mta.Effective()
+		if err != nil {
+			return nil, messages, err
+		}
+	}
+	if options.ExpandIncludes {
+		workspaceDir := options.WorkspaceDir
+		if len(workspaceDir) == 0 {
+			workspaceDir = filepath.Dir(path)
+		}
+		err = ExpandIncludes(mta, workspaceDir)
+		if err != nil {
+			return nil, messages, err
+		}
+	}
+	return mta, messages, nil
+}
+
 // GetResourceConfig returns the configuration for a resource (its service creation parameters).
-// If both the config and path parameters are defined, the result is merged.
+// If both the config and path parameters are defined, the result is merged shallowly, the same as the deployer does.
 func GetResourceConfig(path string, extensions []string, resourceName string, workspaceDir string) (map[string]interface{}, []string, error) {
-	mta, messages, err := GetMtaFromFile(path, extensions, false)
+	return GetResourceConfigWithStrategy(path, extensions, resourceName, workspaceDir, ShallowMerge)
+}
+
+// GetResourceConfigWithStrategy returns the configuration for a resource (its service creation parameters).
+// If both the config and path parameters are defined, the result is merged with the merge strategy.
+// The parameters which the resource inherits from its declared type are taken into account.
+func GetResourceConfigWithStrategy(path string, extensions []string, resourceName string, workspaceDir string, strategy MergeStrategy) (map[string]interface{}, []string, error) {
+	mta, messages, err := getInheritedMtaFromFile(path, extensions)
 	if err != nil {
 		return nil, messages, err
 	}
@@ -160,55 +258,51 @@ func GetResourceConfig(path string, extensions []string, resourceName string, wo
 	if resource == nil {
 		return nil, messages, fmt.Errorf("the '%s' resource does not exist", resourceName)
 	}
-
-	// Get the resource config from its parameters
-	configParam := resource.Parameters["config"]
-	var config map[string]interface{}
-	if configParam != nil {
-		config, _, _ = getMapValue(configParam)
+	config, err := resource.GetConfig(workspaceDir, strategy)
+	if err != nil {
+		return nil, messages, err
 	}
+	return config, messages, nil
+}
 
-	// Get the resource service creation parameters file path
-	filePath := resource.Parameters["path"]
-	var fileConfig map[string]interface{}
+// GetResourceConfigs returns the configurations of all the active resources (their service creation parameters),
+// by the names of the resources. The config and path parameters of each resource, including the ones which it inherits
+// from its declared type, are merged with the merge strategy.
+func GetResourceConfigs(path string, extensions []string, workspaceDir string, strategy MergeStrategy) (map[string]map[string]interface{}, []string, error) {
+	mta, messages, err := getInheritedMtaFromFile(path, extensions)
+	if err != nil {
+		return nil, messages, err
+	}
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
 
-	if filePath != nil {
-		fileConfig, err = fs.GetJSONContent(filepath.Join(workspaceDir, filePath.(string)))
+	configs := make(map[string]map[string]interface{})
+	for _, resource := range mta.Resources {
+		if !resource.IsActive() {
+			continue
+		}
+		configs[resource.Name], err = resource.GetConfig(workspaceDir, strategy)
 		if err != nil {
 			return nil, messages, err
 		}
 	}
+	return configs, messages, nil
+}
 
-	return mergeMaps(config, fileConfig), messages, nil
-}
-
-// Shallow merge the maps. If the first map is not nil the merge result is inlined in it.
-// The first map keys override the second map keys.
-func mergeMaps(first map[string]interface{}, second map[string]interface{}) map[string]interface{} {
-	var result map[string]interface{}
-	if first == nil && second == nil {
-		// Both maps are nil - return empty map
-		result = make(map[string]interface{})
-	} else if first == nil {
-		// Only second exists
-		result = second
-	} else if second == nil {
-		// Only first exists
-		result = first
-	} else {
-		// Both maps are not nil at this point.
-		// Shallow merge the maps (same as the deployer).
-		result = first
-		for key, value := range second {
-			// The first map keys override the second map keys.
-			_, ok := result[key]
-			if !ok {
-				result[key] = value
-			}
-		}
+// getInheritedMtaFromFile returns the effective view of the MTA merged with the extensions, the same as the resolver
+// uses, so the resources inherit the parameters of their declared types. If the inheritance fails, the MTA is returned
+// without it and the error is returned in the messages.
+func getInheritedMtaFromFile(path string, extensions []string) (*MTA, []string, error) {
+	mta, messages, err := GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
 	}
-
-	return result
+	effective, err := mta.Effective()
+	if err != nil {
+		return mta, append(messages, err.Error()), nil
+	}
+	return effective, messages, nil
 }
 
 // UpdateModule updates an existing module according to the module name. If more than one module with this
@@ -380,11 +474,47 @@ func CopyFile(src, dst string, create func(string) (*os.File, error)) (rerr erro
 	return err
 }
 
+// CopyFileInWorkspace - copies a file from the source path to the target path if both paths are in the workspace root folder.
+// The workspace root isn't checked if it is empty.
+func CopyFileInWorkspace(workspaceRoot string, src, dst string, create func(string) (*os.File, error)) error {
+	if err := CheckWorkspaceRoot(workspaceRoot, src, dst); err != nil {
+		return err
+	}
+	return CopyFile(src, dst, create)
+}
+
 // DeleteFile - deletes the file.
 func DeleteFile(path string) error {
 	return fs.DeleteFile(path)
 }
 
+// DeleteFileInWorkspace - deletes the file if it is in the workspace root folder.
+// The workspace root isn't checked if it is empty.
+func DeleteFileInWorkspace(workspaceRoot string, path string) error {
+	if err := CheckWorkspaceRoot(workspaceRoot, path); err != nil {
+		return err
+	}
+	return DeleteFile(path)
+}
+
+// CheckWorkspaceRoot returns an error if one of the paths is not in the workspace root folder. The symbolic links
+// are resolved, so a path can't escape the workspace root through a link. Any path is allowed if the workspace root is empty.
+func CheckWorkspaceRoot(workspaceRoot string, paths ...string) error {
+	if workspaceRoot == "" {
+		return nil
+	}
+	for _, path := range paths {
+		inWorkspace, err := fs.IsInFolder(workspaceRoot, path)
+		if err != nil {
+			return err
+		}
+		if !inWorkspace {
+			return errors.Errorf(outsideWorkspaceRootMsg, path, workspaceRoot)
+		}
+	}
+	return nil
+}
+
 // GetMtaHash - gets the hashcode of the MTA file.
 func GetMtaHash(path string) (int, bool, error) {
 	mtaContent, err := ioutil.ReadFile(filepath.Join(path))
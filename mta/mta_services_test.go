@@ -434,6 +434,81 @@ var _ = Describe("MtaServices", func() {
 		})
 	})
 
+	var _ = Describe("workspace root", func() {
+		var workspaceRoot string
+		var outsideFilePath string
+
+		BeforeEach(func() {
+			workspaceRoot = getTestPath("result", "workspace")
+			outsideFilePath = getTestPath("result", "outside.txt")
+			Ω(os.MkdirAll(workspaceRoot, os.ModePerm)).Should(Succeed())
+			Ω(ioutil.WriteFile(outsideFilePath, []byte("outside"), 0644)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(workspaceRoot, "inside.txt"), []byte("inside"), 0644)).Should(Succeed())
+			Ω(os.Symlink(getTestPath("result"), filepath.Join(workspaceRoot, "link"))).Should(Succeed())
+		})
+
+		It("allows any path if the workspace root is empty", func() {
+			Ω(CheckWorkspaceRoot("", outsideFilePath)).Should(Succeed())
+		})
+
+		It("allows the paths in the workspace root, also if they don't exist", func() {
+			Ω(CheckWorkspaceRoot(workspaceRoot, workspaceRoot, filepath.Join(workspaceRoot, "inside.txt"),
+				filepath.Join(workspaceRoot, "new", "new.txt"))).Should(Succeed())
+		})
+
+		It("refuses a path outside of the workspace root", func() {
+			path := filepath.Join(workspaceRoot, "..", "outside.txt")
+			Ω(CheckWorkspaceRoot(workspaceRoot, path)).Should(MatchError(fmt.Sprintf(outsideWorkspaceRootMsg, path, workspaceRoot)))
+		})
+
+		It("refuses a path which is outside of the workspace root through a symbolic link", func() {
+			path := filepath.Join(workspaceRoot, "link", "outside.txt")
+			Ω(CheckWorkspaceRoot(workspaceRoot, path)).Should(MatchError(fmt.Sprintf(outsideWorkspaceRootMsg, path, workspaceRoot)))
+			path = filepath.Join(workspaceRoot, "link", "new.txt")
+			Ω(CheckWorkspaceRoot(workspaceRoot, path)).Should(MatchError(fmt.Sprintf(outsideWorkspaceRootMsg, path, workspaceRoot)))
+		})
+
+		It("copies a file in the workspace root", func() {
+			targetFilePath := filepath.Join(workspaceRoot, "copy", "inside.txt")
+			Ω(CopyFileInWorkspace(workspaceRoot, filepath.Join(workspaceRoot, "inside.txt"), targetFilePath, os.Create)).Should(Succeed())
+			Ω(targetFilePath).Should(BeAnExistingFile())
+		})
+
+		It("doesn't copy a file from outside of the workspace root", func() {
+			targetFilePath := filepath.Join(workspaceRoot, "copy.txt")
+			Ω(CopyFileInWorkspace(workspaceRoot, outsideFilePath, targetFilePath, os.Create)).Should(HaveOccurred())
+			Ω(targetFilePath).ShouldNot(BeAnExistingFile())
+		})
+
+		It("doesn't copy a file to outside of the workspace root", func() {
+			targetFilePath := filepath.Join(workspaceRoot, "link", "copy.txt")
+			Ω(CopyFileInWorkspace(workspaceRoot, filepath.Join(workspaceRoot, "inside.txt"), targetFilePath, os.Create)).Should(HaveOccurred())
+			Ω(getTestPath("result", "copy.txt")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("deletes a file in the workspace root", func() {
+			path := filepath.Join(workspaceRoot, "inside.txt")
+			Ω(DeleteFileInWorkspace(workspaceRoot, path)).Should(Succeed())
+			Ω(path).ShouldNot(BeAnExistingFile())
+		})
+
+		It("doesn't delete a file outside of the workspace root", func() {
+			Ω(DeleteFileInWorkspace(workspaceRoot, outsideFilePath)).Should(HaveOccurred())
+			Ω(outsideFilePath).Should(BeAnExistingFile())
+		})
+
+		It("deletes an MTA project in the workspace root", func() {
+			Ω(os.MkdirAll(filepath.Join(workspaceRoot, "project"), os.ModePerm)).Should(Succeed())
+			Ω(DeleteMtaInWorkspace(workspaceRoot, filepath.Join(workspaceRoot, "project"))).Should(Succeed())
+			Ω(filepath.Join(workspaceRoot, "project")).ShouldNot(BeADirectory())
+		})
+
+		It("doesn't delete an MTA project outside of the workspace root", func() {
+			Ω(DeleteMtaInWorkspace(workspaceRoot, filepath.Join(workspaceRoot, ".."))).Should(HaveOccurred())
+			Ω(workspaceRoot).Should(BeADirectory())
+		})
+	})
+
 	var _ = Describe("printResult", func() {
 		var printed string
 		printer := func(s ...interface{}) (int, error) {
@@ -1115,6 +1190,26 @@ var _ = Describe("MtaServices", func() {
 			Ω(messages).Should(ContainElement(ContainSubstring(`testResourceNotExisting`)))
 		})
 
+		It("returns the active state of the resources after the extensions are merged", func() {
+			resources, messages, err := GetResourcesWithOptions(getTestPath("mtaResourceActive.yaml"),
+				[]string{getTestPath("resource_active.mtaext")}, GetOptions{ActiveState: true})
+			Ω(err).Should(Succeed())
+			Ω(messages).Should(BeEmpty())
+			Ω(resources).Should(HaveLen(3))
+			Ω(*resources[0].Active).Should(BeTrue())
+			Ω(*resources[1].Active).Should(BeTrue())
+			Ω(*resources[2].Active).Should(BeFalse())
+		})
+
+		It("returns the active state of the resources only as it is set in the mta.yaml and extensions by default", func() {
+			resources, _, err := GetResources(getTestPath("mtaResourceActive.yaml"), []string{getTestPath("resource_active.mtaext")})
+			Ω(err).Should(Succeed())
+			Ω(resources).Should(HaveLen(3))
+			Ω(resources[0].Active).Should(BeNil())
+			Ω(*resources[1].Active).Should(BeTrue())
+			Ω(*resources[2].Active).Should(BeFalse())
+		})
+
 		It("returns an error when mta.yaml does not exist", func() {
 			mtaPath := getTestPath("result", "mta.yaml")
 			resources, messages, err := GetResources(mtaPath, nil)
@@ -1299,6 +1394,86 @@ var _ = Describe("MtaServices", func() {
 		})
 	})
 
+	var _ = Describe("GetResourceConfigWithStrategy", func() {
+		It("merges the nested maps and the named lists of the file with the deep merge strategy", func() {
+			mtaPath := getTestPath("resourceConfig", "mta.yaml")
+			resourceConfig, messages, err := GetResourceConfigWithStrategy(mtaPath, nil, "uaa", "", DeepMerge)
+			Ω(err).Should(Succeed())
+			Ω(messages).Should(BeEmpty())
+			Ω(resourceConfig).Should(Equal(map[string]interface{}{
+				"xsappname":   "${app-name}",
+				"tenant-mode": "dedicated",
+				"oauth2-configuration": map[string]interface{}{
+					"token-validity": float64(3600),
+					"redirect-uris":  []interface{}{"https://app.example.com/**"},
+				},
+				"role-collections": []interface{}{
+					map[string]interface{}{
+						"name":                     "Viewer",
+						"description":              "Viewer of the app",
+						"role-template-references": []interface{}{"$XSAPPNAME.Viewer"},
+					},
+					map[string]interface{}{
+						"name":                     "Admin",
+						"role-template-references": []interface{}{"$XSAPPNAME.Admin"},
+					},
+				},
+			}))
+		})
+
+		It("replaces the nested maps of the file with the shallow merge strategy", func() {
+			mtaPath := getTestPath("resourceConfig", "mta.yaml")
+			resourceConfig, _, err := GetResourceConfigWithStrategy(mtaPath, nil, "uaa", "", ShallowMerge)
+			Ω(err).Should(Succeed())
+			Ω(resourceConfig).Should(HaveKeyWithValue("oauth2-configuration", HaveLen(1)))
+			Ω(resourceConfig).Should(HaveKeyWithValue("role-collections", HaveLen(2)))
+			Ω(resourceConfig).Should(HaveKeyWithValue("tenant-mode", "dedicated"))
+		})
+
+		It("returns an error for an unknown merge strategy", func() {
+			mtaPath := getTestPath("resourceConfig", "mta.yaml")
+			_, _, err := GetResourceConfigWithStrategy(mtaPath, nil, "uaa", "", "other")
+			Ω(err).Should(MatchError(fmt.Sprintf(wrongMergeStrategyMsg, "other")))
+		})
+	})
+
+	var _ = Describe("GetResourceConfig with resource types", func() {
+		It("returns the file content when the path is inherited from the resource type", func() {
+			resourceConfig, messages, err := GetResourceConfig(getTestPath("mtaConfigTypes.yaml"), nil, "resourceWithInheritedPath", "")
+			Ω(err).Should(Succeed())
+			Ω(messages).Should(BeEmpty())
+			Ω(resourceConfig).Should(HaveKeyWithValue("xsappname", "nameFromPath"))
+		})
+
+		It("merges the config which is inherited from the resource type over the file content", func() {
+			configs, messages, err := GetResourceConfigs(getTestPath("mtaConfigTypes.yaml"), nil, "", ShallowMerge)
+			Ω(err).Should(Succeed())
+			Ω(messages).Should(BeEmpty())
+			Ω(configs).Should(HaveKeyWithValue("resourceWithInheritedPath", HaveKeyWithValue("xsappname", "nameFromPath")))
+			Ω(configs).Should(HaveKeyWithValue("resourceWithInheritedConfig", HaveKeyWithValue("xsappname", "nameFromType")))
+			Ω(configs).Should(HaveKeyWithValue("resourceWithInheritedConfig", HaveKeyWithValue("paramFromPath", "paramValueFromPath")))
+		})
+	})
+
+	var _ = Describe("GetResourceConfigs", func() {
+		It("returns the configurations of the active resources by their names", func() {
+			mtaPath := getTestPath("resourceConfig", "mta.yaml")
+			configs, messages, err := GetResourceConfigs(mtaPath, nil, "", DeepMerge)
+			Ω(err).Should(Succeed())
+			Ω(messages).Should(BeEmpty())
+			Ω(configs).Should(HaveLen(2))
+			Ω(configs).Should(HaveKeyWithValue("db", map[string]interface{}{"schema": "${schema-name}"}))
+			Ω(configs).Should(HaveKeyWithValue("uaa", HaveKeyWithValue("tenant-mode", "dedicated")))
+		})
+
+		It("returns an error when the file of a resource does not exist", func() {
+			mtaPath := getTestPath("mtaConfig.yaml")
+			configs, _, err := GetResourceConfigs(mtaPath, nil, "", ShallowMerge)
+			Ω(err).Should(HaveOccurred())
+			Ω(configs).Should(BeNil())
+		})
+	})
+
 	var _ = Describe("GetMtaID", func() {
 		It("Get MTA ID", func() {
 			mtaPath := getTestPath("result", "temp.mta.yaml")
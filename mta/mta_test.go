@@ -276,6 +276,10 @@ var _ = Describe("Mta", func() {
 				Ω(resources[2].Name).Should(Equal("activeIsTrue"))
 				Ω(resources[2].Active).ShouldNot(BeNil())
 				Ω(*resources[2].Active).Should(BeTrue())
+
+				Ω(resources[0].IsActive()).Should(BeTrue())
+				Ω(resources[1].IsActive()).Should(BeFalse())
+				Ω(resources[2].IsActive()).Should(BeTrue())
 			})
 		})
 
@@ -0,0 +1,177 @@
+package mta
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+// MergeStrategy is the way the config parameter of a resource is merged over the content of the file in its path parameter
+type MergeStrategy string
+
+const (
+	// ShallowMerge - the top-level keys of the config parameter replace the keys of the file, the same as the deployer does
+	ShallowMerge MergeStrategy = "shallow"
+	// DeepMerge - the maps are merged at any depth. The lists of maps which all have a "name" key (e.g. the scopes
+	// and the role collections of an xs-security.json file) are merged by the names of their elements, and the other
+	// values of the config parameter replace the values of the file.
+	DeepMerge MergeStrategy = "deep"
+
+	wrongMergeStrategyMsg = `the "%s" merge strategy is not supported; expected one of the following: shallow, deep`
+
+	configParameter = "config"
+	pathParameter   = "path"
+	nameKey         = "name"
+)
+
+// GetConfig returns the configuration of the resource (its service creation parameters): the config parameter merged
+// over the content of the JSON file in the path parameter, which is relative to the workspace folder.
+func (resource *Resource) GetConfig(workspaceDir string, strategy MergeStrategy) (map[string]interface{}, error) {
+	if strategy != ShallowMerge && strategy != DeepMerge {
+		return nil, errors.Errorf(wrongMergeStrategyMsg, strategy)
+	}
+
+	// Get the resource config from its parameters
+	configParam := resource.Parameters[configParameter]
+	var config map[string]interface{}
+	if configParam != nil {
+		config, _, _ = getMapValue(configParam)
+	}
+
+	// Get the resource service creation parameters file path
+	filePath := resource.Parameters[pathParameter]
+	var fileConfig map[string]interface{}
+	if filePath != nil {
+		var err error
+		fileConfig, err = fs.GetJSONContent(filepath.Join(workspaceDir, fmt.Sprint(filePath)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if strategy == DeepMerge {
+		return deepMergeMaps(config, fileConfig), nil
+	}
+	return mergeMaps(config, fileConfig), nil
+}
+
+// Shallow merge the maps. If the first map is not nil the merge result is inlined in it.
+// The first map keys override the second map keys.
+func mergeMaps(first map[string]interface{}, second map[string]interface{}) map[string]interface{} {
+	var result map[string]interface{}
+	if first == nil && second == nil {
+		// Both maps are nil - return empty map
+		result = make(map[string]interface{})
+	} else if first == nil {
+		// Only second exists
+		result = second
+	} else if second == nil {
+		// Only first exists
+		result = first
+	} else {
+		// Both maps are not nil at this point.
+		// Shallow merge the maps (same as the deployer).
+		result = first
+		for key, value := range second {
+			// The first map keys override the second map keys.
+			_, ok := result[key]
+			if !ok {
+				result[key] = value
+			}
+		}
+	}
+
+	return result
+}
+
+// deepMergeMaps merges the first map over the second map at any depth and returns a new map.
+// The values of the first map override the values of the second map, unless both are maps or lists of named maps.
+func deepMergeMaps(first map[string]interface{}, second map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(first)+len(second))
+	for key, value := range second {
+		result[key] = toStringMaps(value)
+	}
+	for key, value := range first {
+		result[key] = deepMergeValues(toStringMaps(value), result[key])
+	}
+	return result
+}
+
+func deepMergeValues(value interface{}, other interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if otherMap, ok := other.(map[string]interface{}); ok {
+			return deepMergeMaps(v, otherMap)
+		}
+	case []interface{}:
+		if otherList, ok := other.([]interface{}); ok && isNamedList(v) && isNamedList(otherList) {
+			return deepMergeNamedLists(v, otherList)
+		}
+	}
+	return value
+}
+
+// deepMergeNamedLists merges the elements of the first list over the elements of the second list with the same name.
+// The elements of the second list come first, and the elements of the first list which are not in it are appended.
+func deepMergeNamedLists(first []interface{}, second []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(first)+len(second))
+	indexes := make(map[interface{}]int, len(second))
+	for _, element := range second {
+		indexes[element.(map[string]interface{})[nameKey]] = len(result)
+		result = append(result, element)
+	}
+	for _, element := range first {
+		elementMap := element.(map[string]interface{})
+		if i, ok := indexes[elementMap[nameKey]]; ok {
+			result[i] = deepMergeMaps(elementMap, result[i].(map[string]interface{}))
+		} else {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// isNamedList returns true if all the elements of the list are maps with a comparable "name" key
+func isNamedList(list []interface{}) bool {
+	for _, element := range list {
+		elementMap, ok := element.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		switch elementMap[nameKey].(type) {
+		case string, int, float64, bool:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// toStringMaps returns a copy of the value in which the maps at any depth have string keys
+func toStringMaps(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[key] = toStringMaps(val)
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[fmt.Sprint(key)] = toStringMaps(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			res[i] = toStringMaps(val)
+		}
+		return res
+	default:
+		return v
+	}
+}
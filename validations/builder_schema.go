@@ -17,6 +17,7 @@ func checkStringProperty(props map[string]interface{}, propsNode *yaml.Node, pro
 				Msg:    fmt.Sprintf(`the "%s" property is defined incorrectly; the property must be a string`, propName),
 				Line:   propNode.Line,
 				Column: propNode.Column,
+				node:   propNode,
 			},
 		}
 	}
@@ -46,7 +47,7 @@ func checkBuilderSchema(mta *mta.MTA, mtaNode *yaml.Node, source string) []YamlV
 				if !ok {
 					buildParamsNode := getPropValueByName(modulesNode[i], buildParametersYamlField)
 					commandsParamsNode := getPropValueByName(buildParamsNode, commandsYamlField)
-					issues = appendIssue(issues, `the "commands" property is defined incorrectly; the property must be a sequence of strings`, commandsParamsNode.Line, commandsParamsNode.Column)
+					issues = appendNodeIssue(issues, `the "commands" property is defined incorrectly; the property must be a sequence of strings`, commandsParamsNode)
 				}
 			}
 		}
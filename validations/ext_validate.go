@@ -15,12 +15,20 @@ const (
 )
 
 // Mtaext validates an MTA extension file.
+// The rules are configured by the lint configuration file in the project folder, if it exists; the excluded rules don't run
+// regardless of the configuration, and the opt-in rules run only if the configuration enables them.
+// If the configuration file is not valid, its issues are returned with the warnings and the default configuration is used.
+// The issues with an info severity are returned with the warnings.
 func Mtaext(projectPath, extPath string,
 	validateSchema, validateSemantic, strict bool, exclude string) (warning string, err error) {
-	errIssues, warnIssues, e := validateMtaext(projectPath, extPath, validateSchema, validateSemantic, strict, exclude)
+	config, configIssues := loadLintConfigWithIssues(projectPath)
+	config = config.withDefaultRules(withOptInRulesOff(RulesConfig{}))
+	errIssues, warnIssues, infoIssues, e := validateMtaext(projectPath, extPath, validateSchema, validateSemantic, strict, exclude, config)
 	if e != nil {
 		return "", e
 	}
+	warnIssues = append(append(configIssues, warnIssues...), infoIssues...)
+	warnIssues.Sort()
 	if len(errIssues) > 0 {
 		return warnIssues.String(), errors.Errorf(validationErrorsMsg, extPath, errIssues.String())
 	}
@@ -28,32 +36,36 @@ func Mtaext(projectPath, extPath string,
 }
 
 func validateMtaext(projectPath, extPath string, validateSchema, validateSemantic, strict bool,
-	exclude string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues, err error) {
+	exclude string, config *LintConfig) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues, infoIssues YamlValidationIssues, err error) {
 	if validateSemantic || validateSchema {
-		var errIssues, warnIssues YamlValidationIssues
-
 		// ParseFile contains MTA yaml content.
 		yamlContent, e := fs.ReadFile(extPath)
 		if e != nil {
-			return nil, nil, errors.Wrapf(e, couldNotValidateErrorMsg, extPath)
+			return nil, nil, nil, errors.Wrapf(e, couldNotValidateErrorMsg, extPath)
 		}
 
 		// Validates MTA content.
+		exclude = config.getExcludedRules(exclude)
 		contentErrIssues, contentWarnIssues := validateExt(yamlContent, projectPath, extPath,
-			validateSchema, validateSemantic, strict, exclude)
-		errIssues = append(errIssues, contentErrIssues...)
+			validateSchema, validateSemantic, strict, exclude, config)
+		if validateSemantic {
+			contentErrIssues, contentWarnIssues = applySuppressions(yamlContent, getRanRules(exclude, true), contentErrIssues, contentWarnIssues)
+		}
+		extNode, _ := getContentNode(yamlContent)
+		errIssues, warnIssues, infoIssues = config.applyLintConfig(getRelativePath(projectPath, extPath), extNode, contentErrIssues, contentWarnIssues)
+		setIssueRanges(yamlContent, extNode, &errIssues, &warnIssues, &infoIssues)
 		errIssues.Sort()
-		warnIssues = append(warnIssues, contentWarnIssues...)
 		warnIssues.Sort()
-		return errIssues, warnIssues, nil
+		infoIssues.Sort()
+		return errIssues, warnIssues, infoIssues, nil
 	}
 
-	return nil, nil, nil
+	return nil, nil, nil, nil
 }
 
 // validateExt validates the MTA extension descriptor
 func validateExt(yamlContent []byte, projectPath string, extFileName string,
-	validateSchema, validateSemantic, strict bool, exclude string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
+	validateSchema, validateSemantic, strict bool, exclude string, config *LintConfig) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
 	extNode, err := getContentNode(yamlContent)
 	if err != nil {
 		errIssues = convertError(err)
@@ -75,7 +87,7 @@ func validateExt(yamlContent []byte, projectPath string, extFileName string,
 	}
 
 	if validateSemantic {
-		errs, warns := runExtSemanticValidations(mtaExt, extNode, projectPath, exclude, strict)
+		errs, warns := runExtSemanticValidations(mtaExt, extNode, projectPath, exclude, strict, config)
 		errIssues = append(errIssues, errs...)
 		warnIssues = append(warnIssues, warns...)
 	}
@@ -64,7 +64,7 @@ var _ = Describe("MTAEXT validation tests", func() {
 	var _ = Describe("validateExt - unmarshalling fails", func() {
 		It("Sanity", func() {
 			err, warn := validateExt([]byte("bad Yaml"), getTestPath("mtahtml5"), "my.mtaext",
-				true, false, true, "")
+				true, false, true, "", nil)
 			Ω(warn).Should(BeNil())
 			Ω(err).ShouldNot(BeNil())
 			Ω(len(err)).Should(Equal(5))
@@ -74,7 +74,7 @@ var _ = Describe("MTAEXT validation tests", func() {
 		It("Only returns unmarshaling error once", func() {
 			err, warn := validateExt([]byte(`- bad Yaml
 a b`), getTestPath("mtahtml5"), "my.mtaext",
-				true, false, true, "")
+				true, false, true, "", nil)
 			Ω(warn).Should(BeNil())
 			Ω(err).ShouldNot(BeNil())
 
@@ -90,7 +90,7 @@ a b`), getTestPath("mtahtml5"), "my.mtaext",
 
 		It("Empty mta content", func() {
 			err, warn := validateExt([]byte(""), "a.mtaext", getTestPath("mtahtml5"),
-				true, false, true, "")
+				true, false, true, "", nil)
 			Ω(warn).Should(BeNil())
 			Ω(err).ShouldNot(BeNil())
 			Ω(err[0].Msg).Should(Equal("EOF"))
@@ -127,7 +127,7 @@ ID: mymtaext
 extends: somemta
 _schema-version: '3.1'
 `), getTestPath("mtahtml5"), "ext.yaml",
-			false, true, true, "")
+			false, true, true, "", nil)
 		Ω(warn).Should(BeNil())
 		Ω(err).Should(BeNil())
 	})
@@ -169,17 +169,17 @@ resources:
     properties-metadata:
       a:
 `), getTestPath("mtahtml5"), "my.mtaext",
-			true, false, true, "")
+			true, false, true, "", nil)
 		Ω(warn).Should(BeNil())
-		Ω(err).Should(ConsistOf(
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "public", "modules[0].provides[0]"), 10, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].requires[0]"), 13, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].hooks[0].requires[0]"), 18, 7},
-			YamlValidationIssue{`field optional not found in type mta.ResourceExt`, 21, 0},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "list", "resources[0].requires[0]"), 24, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[1].requires[0]"), 28, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "parameters-metadata", "resources[1].requires[1]"), 30, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[2].requires[0]"), 34, 5},
+		Ω(withoutNodes(err)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "public", "modules[0].provides[0]"), Line: 10, Column: 5},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].requires[0]"), Line: 13, Column: 5},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].hooks[0].requires[0]"), Line: 18, Column: 7},
+			YamlValidationIssue{Msg: `field optional not found in type mta.ResourceExt`, Line: 21, Column: 0},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "list", "resources[0].requires[0]"), Line: 24, Column: 5},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[1].requires[0]"), Line: 28, Column: 5},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "parameters-metadata", "resources[1].requires[1]"), Line: 30, Column: 5},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[2].requires[0]"), Line: 34, Column: 5},
 		))
 	})
 })
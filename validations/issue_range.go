@@ -0,0 +1,328 @@
+package validate
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IssuePosition is a position in a file
+type IssuePosition struct {
+	// Line - the line number, starting from 1
+	Line int `json:"line"`
+	// Column - the column number, starting from 1. The columns are counted in characters.
+	Column int `json:"column"`
+	// Offset - the byte offset from the beginning of the file, starting from 0
+	Offset int `json:"offset"`
+}
+
+// IssueRange is the range of the text in which an issue is found. The end position is exclusive.
+type IssueRange struct {
+	Start IssuePosition `json:"start"`
+	End   IssuePosition `json:"end"`
+}
+
+var (
+	// The messages of the decoder errors, after the line prefix is removed
+	unmarshalErrorRegex = regexp.MustCompile("^cannot unmarshal (!!\\w+)(?: `(.*)`)? into ")
+	unknownFieldRegex   = regexp.MustCompile(`^field (\S+) not found in type `)
+	duplicateKeyRegex   = regexp.MustCompile(`^mapping key (".*") already defined at line \d+`)
+)
+
+// sourceText is the content of a file with the offsets of its lines
+type sourceText struct {
+	content    []byte
+	lineStarts []int
+	// crBreaks - the number of the CR line breaks before each line. The files are read with fs.ReadFile, which replaces
+	// the CRLF line breaks with CR, so each CR line break is a byte shorter than in the file.
+	crBreaks []int
+}
+
+func newSourceText(content []byte) *sourceText {
+	lineStarts := getLineStarts(content)
+	crBreaks := make([]int, len(lineStarts))
+	for i := 1; i < len(lineStarts); i++ {
+		crBreaks[i] = crBreaks[i-1]
+		if content[lineStarts[i]-1] == '\r' {
+			crBreaks[i]++
+		}
+	}
+	return &sourceText{content, lineStarts, crBreaks}
+}
+
+// lineEnd returns the offset of the end of the line, before its line break
+func (s *sourceText) lineEnd(line int) int {
+	if line >= len(s.lineStarts) {
+		return len(s.content)
+	}
+	end := s.lineStarts[line]
+	for end > s.lineStarts[line-1] && (s.content[end-1] == '\n' || s.content[end-1] == '\r') {
+		end--
+	}
+	return end
+}
+
+// offset returns the offset of the line and column, which start from 1. A column after the end of the line is moved to its end.
+func (s *sourceText) offset(line int, column int) int {
+	if line < 1 {
+		return 0
+	}
+	if line > len(s.lineStarts) {
+		return len(s.content)
+	}
+	offset := s.lineStarts[line-1]
+	lineEnd := s.lineEnd(line)
+	for i := 1; i < column && offset < lineEnd; i++ {
+		_, size := utf8.DecodeRune(s.content[offset:lineEnd])
+		offset += size
+	}
+	return offset
+}
+
+// position returns the position of the offset. The offset of the position is the offset in the file.
+func (s *sourceText) position(offset int) IssuePosition {
+	line := sort.Search(len(s.lineStarts), func(i int) bool {
+		return s.lineStarts[i] > offset
+	})
+	return IssuePosition{Line: line, Column: utf8.RuneCount(s.content[s.lineStarts[line-1]:offset]) + 1, Offset: offset + s.crBreaks[line-1]}
+}
+
+// lineIndent returns the number of the spaces at the beginning of the line of the offset
+func (s *sourceText) lineIndent(offset int) int {
+	start := s.lineStarts[s.position(offset).Line-1]
+	indent := 0
+	for start+indent < len(s.content) && s.content[start+indent] == ' ' {
+		indent++
+	}
+	return indent
+}
+
+// lineRange returns the range of the content of the line, without the leading and trailing spaces.
+// If the column is greater than 0, the range starts from the column.
+func (s *sourceText) lineRange(line int, column int) *IssueRange {
+	start := s.offset(line, column)
+	end := s.lineEnd(line)
+	if column <= 0 {
+		for start < end && (s.content[start] == ' ' || s.content[start] == '\t') {
+			start++
+		}
+	}
+	for end > start && (s.content[end-1] == ' ' || s.content[end-1] == '\t') {
+		end--
+	}
+	return &IssueRange{s.position(start), s.position(end)}
+}
+
+// nodeRange returns the range of the node and its content
+func (s *sourceText) nodeRange(node *yaml.Node) *IssueRange {
+	start := s.offset(node.Line, node.Column)
+	return &IssueRange{s.position(start), s.position(s.nodeEnd(node, start))}
+}
+
+// nodeEnd returns the offset of the end of the node which starts in the offset
+func (s *sourceText) nodeEnd(node *yaml.Node, start int) int {
+	start = s.skipProperties(start)
+	switch {
+	case node.Kind == yaml.AliasNode:
+		return s.skipToken(start + 1)
+	case node.Kind == yaml.ScalarNode:
+		return s.scalarEnd(node, start)
+	case node.Style&yaml.FlowStyle != 0:
+		return s.flowEnd(start)
+	case len(node.Content) > 0:
+		last := node.Content[len(node.Content)-1]
+		return s.nodeEnd(last, s.offset(last.Line, last.Column))
+	}
+	return start
+}
+
+// skipProperties skips the anchor and the tag of the node
+func (s *sourceText) skipProperties(offset int) int {
+	for offset < len(s.content) && (s.content[offset] == '&' || s.content[offset] == '!') {
+		offset = s.skipToken(offset)
+		for offset < len(s.content) && (s.content[offset] == ' ' || s.content[offset] == '\t') {
+			offset++
+		}
+	}
+	return offset
+}
+
+// skipToken returns the offset of the first space, line break or flow indicator after the offset
+func (s *sourceText) skipToken(offset int) int {
+	for offset < len(s.content) && !strings.ContainsRune(" \t\r\n,[]{}", rune(s.content[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func (s *sourceText) scalarEnd(node *yaml.Node, start int) int {
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		return s.quotedEnd(start, '"')
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		return s.quotedEnd(start, '\'')
+	case node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		return s.blockScalarEnd(start)
+	}
+	return s.plainEnd(node.Value, start)
+}
+
+// quotedEnd returns the offset after the closing quote of the scalar which starts with the quote in the offset
+func (s *sourceText) quotedEnd(start int, quote byte) int {
+	for i := start + 1; i < len(s.content); i++ {
+		switch {
+		case quote == '"' && s.content[i] == '\\':
+			i++
+		case s.content[i] == quote && quote == '\'' && i+1 < len(s.content) && s.content[i+1] == '\'':
+			i++
+		case s.content[i] == quote:
+			return i + 1
+		}
+	}
+	return len(s.content)
+}
+
+// blockScalarEnd returns the end of the last line of the literal or folded scalar. The lines of the scalar are indented
+// more than the line of its header.
+func (s *sourceText) blockScalarEnd(start int) int {
+	indent := s.lineIndent(start)
+	line := s.position(start).Line
+	end := s.lineEnd(line)
+	for line++; line <= len(s.lineStarts); line++ {
+		lineStart := s.lineStarts[line-1]
+		lineEnd := s.lineEnd(line)
+		if strings.TrimSpace(string(s.content[lineStart:lineEnd])) == "" {
+			continue
+		}
+		if s.lineIndent(lineStart) <= indent {
+			break
+		}
+		end = lineEnd
+	}
+	return end
+}
+
+// plainEnd returns the end of the plain scalar. The line breaks of a multi-line plain scalar are folded to spaces
+// in its value, so the spaces in the value match any white space in the content.
+func (s *sourceText) plainEnd(value string, start int) int {
+	offset := start
+	for _, r := range value {
+		if r == ' ' || r == '\t' || r == '\n' {
+			if offset < len(s.content) && strings.ContainsRune(" \t\r\n", rune(s.content[offset])) {
+				for offset < len(s.content) && strings.ContainsRune(" \t\r\n", rune(s.content[offset])) {
+					offset++
+				}
+				continue
+			}
+		}
+		c, size := utf8.DecodeRune(s.content[offset:])
+		if c != r {
+			// The value doesn't match the content, for example because of an escaped character
+			return s.lineEnd(s.position(start).Line)
+		}
+		offset += size
+	}
+	return offset
+}
+
+// flowEnd returns the offset after the closing bracket of the flow collection which starts in the offset
+func (s *sourceText) flowEnd(start int) int {
+	depth := 0
+	for i := start; i < len(s.content); i++ {
+		switch s.content[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '"', '\'':
+			i = s.quotedEnd(i, s.content[i]) - 1
+		case '#':
+			if i > 0 && (s.content[i-1] == ' ' || s.content[i-1] == '\t') {
+				i = s.lineEnd(s.position(i).Line) - 1
+			}
+		}
+	}
+	return len(s.content)
+}
+
+// getDecoderErrorNode returns the node of the decoder error on the line of the issue, in the document which was
+// validated. The decoder errors have only a line, so the node is found by the value or the key in the message.
+func getDecoderErrorNode(document *yaml.Node, issue YamlValidationIssue) *yaml.Node {
+	nodes := getLineNodes(document, issue.Line, nil)
+	if match := unmarshalErrorRegex.FindStringSubmatch(issue.Msg); match != nil {
+		tag, value := match[1], match[2]
+		for _, node := range nodes {
+			if node.ShortTag() == tag && (node.Kind != yaml.ScalarNode || node.Value == value ||
+				strings.HasSuffix(value, "...") && strings.HasPrefix(node.Value, strings.TrimSuffix(value, "..."))) {
+				return node
+			}
+		}
+	}
+	key := ""
+	if match := unknownFieldRegex.FindStringSubmatch(issue.Msg); match != nil {
+		key = match[1]
+	} else if match := duplicateKeyRegex.FindStringSubmatch(issue.Msg); match != nil {
+		key, _ = strconv.Unquote(match[1])
+	}
+	if key != "" {
+		// The key is the last one with the name on the line, in case of a duplicate key in a flow mapping
+		var keyNode *yaml.Node
+		for _, node := range nodes {
+			if node.Kind == yaml.ScalarNode && node.Value == key {
+				keyNode = node
+			}
+		}
+		return keyNode
+	}
+	return nil
+}
+
+// getLineNodes returns the node and its content which start on the line, from the outer node to the inner node
+func getLineNodes(node *yaml.Node, line int, nodes []*yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nodes
+	}
+	if node.Line == line {
+		nodes = append(nodes, node)
+	}
+	for _, child := range node.Content {
+		nodes = getLineNodes(child, line, nodes)
+	}
+	return nodes
+}
+
+// setIssueRanges sets the ranges of the issues in the validated content. The ranges of the issues are the ranges
+// of their nodes, or the content of their lines if they are not on a node. The decoder errors are mapped to their nodes
+// in the document which was validated, which is nil if the content can't be parsed; they are still reported without
+// a column. The issues which are not on a line don't have a range.
+func setIssueRanges(content []byte, document *yaml.Node, issues ...*YamlValidationIssues) {
+	text := newSourceText(content)
+	for _, fileIssues := range issues {
+		if len(*fileIssues) == 0 {
+			continue
+		}
+		result := make(YamlValidationIssues, 0, len(*fileIssues))
+		for _, issue := range *fileIssues {
+			if issue.Line > 0 && issue.Line <= len(text.lineStarts) {
+				node := issue.node
+				if node == nil && issue.Column == 0 && document != nil {
+					node = getDecoderErrorNode(document, issue)
+				}
+				if node != nil {
+					issue.Range = text.nodeRange(node)
+				} else {
+					issue.Range = text.lineRange(issue.Line, issue.Column)
+				}
+			}
+			result = append(result, issue)
+		}
+		*fileIssues = result
+	}
+}
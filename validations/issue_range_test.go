@@ -0,0 +1,115 @@
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("IssueRange", func() {
+	content := []byte(`ID: mtahtml5
+description: "a \"quoted\"
+  description"
+modules:
+  - name: ui5app
+    type: html5
+    path: &path ui5app
+    parameters: {memory: 256M, disk-quota: [1, 2]}
+    properties:
+      text: |
+        line 1
+
+        line 2
+      other: a plain
+        multi-line value
+  - name: ui5app2
+    path: *path
+    type: [html5]
+    requires: abc
+`)
+
+	// rangeOf returns the range from the start line and column to the end line and column
+	rangeOf := func(startLine, startColumn, endLine, endColumn int) *IssueRange {
+		text := newSourceText(content)
+		return &IssueRange{text.position(text.offset(startLine, startColumn)), text.position(text.offset(endLine, endColumn))}
+	}
+
+	var document yaml.Node
+	_ = yaml.Unmarshal(content, &document)
+
+	// nodeAt returns the node of the kind in the position
+	var nodeAt func(node *yaml.Node, line, column int, kind yaml.Kind) *yaml.Node
+	nodeAt = func(node *yaml.Node, line, column int, kind yaml.Kind) *yaml.Node {
+		if node.Line == line && node.Column == column && node.Kind == kind {
+			return node
+		}
+		for _, child := range node.Content {
+			if found := nodeAt(child, line, column, kind); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	var _ = DescribeTable("setIssueRanges of an issue on a node", func(line, column int, kind yaml.Kind, expectedRange *IssueRange) {
+		node := nodeAt(&document, line, column, kind)
+		Ω(node).ShouldNot(BeNil())
+		issues := YamlValidationIssues(appendNodeIssue(nil, "issue", node))
+		setIssueRanges(content, &document, &issues)
+		Ω(issues).Should(HaveLen(1))
+		Ω(issues[0].Column).Should(Equal(column))
+		Ω(issues[0].Range).Should(Equal(expectedRange))
+	},
+		Entry("plain scalar", 1, 5, yaml.ScalarNode, rangeOf(1, 5, 1, 13)),
+		Entry("multi-line double-quoted scalar", 2, 14, yaml.ScalarNode, rangeOf(2, 14, 3, 15)),
+		Entry("mapping key", 6, 5, yaml.ScalarNode, rangeOf(6, 5, 6, 9)),
+		Entry("scalar with an anchor", 7, 11, yaml.ScalarNode, rangeOf(7, 11, 7, 23)),
+		Entry("flow mapping", 8, 17, yaml.MappingNode, rangeOf(8, 17, 8, 51)),
+		Entry("flow sequence in a flow mapping", 8, 44, yaml.SequenceNode, rangeOf(8, 44, 8, 50)),
+		Entry("literal scalar", 10, 13, yaml.ScalarNode, rangeOf(10, 13, 13, 15)),
+		Entry("multi-line plain scalar", 14, 14, yaml.ScalarNode, rangeOf(14, 14, 15, 25)),
+		Entry("first key of a block mapping", 9, 5, yaml.ScalarNode, rangeOf(9, 5, 9, 15)),
+		Entry("block mapping in the position of its first key", 5, 5, yaml.MappingNode, rangeOf(5, 5, 15, 25)),
+		Entry("alias", 17, 11, yaml.AliasNode, rangeOf(17, 11, 17, 16)),
+	)
+
+	var _ = DescribeTable("setIssueRanges of an issue without a node", func(issue YamlValidationIssue, expectedColumn int, expectedRange *IssueRange) {
+		issues := YamlValidationIssues{issue}
+		setIssueRanges(content, &document, &issues)
+		Ω(issues).Should(HaveLen(1))
+		Ω(issues[0].Column).Should(Equal(expectedColumn))
+		Ω(issues[0].Range).Should(Equal(expectedRange))
+	},
+		Entry("position which is not on a node", YamlValidationIssue{Msg: "issue", Line: 12, Column: 3}, 3, rangeOf(12, 3, 12, 3)),
+		Entry("position inside a scalar", YamlValidationIssue{Msg: "issue", Line: 1, Column: 8}, 8, rangeOf(1, 8, 1, 13)),
+		Entry("decoder error on a scalar",
+			YamlValidationIssue{Msg: "cannot unmarshal !!str `abc` into []mta.Requires", Line: 19}, 0, rangeOf(19, 15, 19, 18)),
+		Entry("decoder error on a sequence",
+			YamlValidationIssue{Msg: "cannot unmarshal !!seq into string", Line: 18}, 0, rangeOf(18, 11, 18, 18)),
+		Entry("decoder error on a truncated value",
+			YamlValidationIssue{Msg: "cannot unmarshal !!str `a plain...` into int", Line: 14}, 0, rangeOf(14, 14, 15, 25)),
+		Entry("unknown field", YamlValidationIssue{Msg: "field path not found in type mta.Module", Line: 17}, 0, rangeOf(17, 5, 17, 9)),
+		Entry("duplicate key", YamlValidationIssue{Msg: `mapping key "type" already defined at line 6`, Line: 18}, 0, rangeOf(18, 5, 18, 9)),
+		Entry("other error on a line", YamlValidationIssue{Msg: "yaml: did not find expected key", Line: 5}, 0, rangeOf(5, 3, 5, 17)),
+	)
+
+	It("doesn't set a range to an issue which is not on a line", func() {
+		issues := YamlValidationIssues{{Msg: "issue"}, {Msg: "issue", Line: 100}}
+		setIssueRanges(content, &document, &issues)
+		Ω(issues[0].Range).Should(BeNil())
+		Ω(issues[1].Range).Should(BeNil())
+	})
+
+	It("sets the ranges by the lines of a content which can't be parsed", func() {
+		issues := YamlValidationIssues{{Msg: "issue", Line: 2, Column: 3}}
+		setIssueRanges([]byte("a: b\r\n  c: [d\r\n"), nil, &issues)
+		Ω(issues[0].Range).Should(Equal(&IssueRange{IssuePosition{2, 3, 8}, IssuePosition{2, 8, 13}}))
+	})
+
+	It("sets the offsets in the file of a content which is read with CRLF line breaks", func() {
+		issues := YamlValidationIssues{{Msg: "issue", Line: 2, Column: 3}}
+		setIssueRanges([]byte("a: b\r  c: [d\r"), nil, &issues)
+		Ω(issues[0].Range).Should(Equal(&IssueRange{IssuePosition{2, 3, 8}, IssuePosition{2, 8, 13}}))
+	})
+})
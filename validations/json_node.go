@@ -0,0 +1,152 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	unexpectedJSONContentMsg = `unexpected content after the JSON value`
+	unexpectedJSONEndMsg     = `unexpected end of the JSON content`
+)
+
+// getJSONContentNode parses the JSON content into nodes with the positions of the values in the content, the same as
+// the content node of a YAML file, so the JSON files referenced by the MTA can be validated with the same functions.
+// JSON can't be parsed with the YAML parser because not all the JSON escape sequences are valid in YAML.
+// A syntax error is returned as a contentSyntaxError with its position in the content.
+func getJSONContentNode(content []byte) (*yaml.Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	parser := &jsonNodeParser{content: content, decoder: decoder}
+	node, err := parser.parseValue()
+	if err == nil {
+		line, column := parser.nextTokenPosition()
+		if _, err = decoder.Token(); err == io.EOF {
+			return node, nil
+		}
+		if _, ok := err.(*json.SyntaxError); !ok {
+			return nil, &contentSyntaxError{line, column, unexpectedJSONContentMsg}
+		}
+	}
+
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		// The error occurs after the offset is read, on the last character
+		line, column := getOffsetPosition(content, int(syntaxErr.Offset)-1)
+		return nil, &contentSyntaxError{line, column, syntaxErr.Error()}
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		line, column := getOffsetPosition(content, len(content))
+		return nil, &contentSyntaxError{line, column, unexpectedJSONEndMsg}
+	}
+	return nil, err
+}
+
+// contentSyntaxError is a syntax error at a position in the content of a file; the column is 0 if it is not known
+type contentSyntaxError struct {
+	line   int
+	column int
+	msg    string
+}
+
+func (e *contentSyntaxError) Error() string {
+	return e.msg
+}
+
+type jsonNodeParser struct {
+	content []byte
+	decoder *json.Decoder
+}
+
+func (p *jsonNodeParser) parseValue() (*yaml.Node, error) {
+	line, column := p.nextTokenPosition()
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &yaml.Node{Line: line, Column: column}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			node.Kind, node.Tag, node.Style = yaml.MappingNode, "!!map", yaml.FlowStyle
+			err = p.parseObject(node)
+		} else {
+			node.Kind, node.Tag, node.Style = yaml.SequenceNode, "!!seq", yaml.FlowStyle
+			err = p.parseArray(node)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case string:
+		node.Kind, node.Tag, node.Value, node.Style = yaml.ScalarNode, "!!str", value, yaml.DoubleQuotedStyle
+	case json.Number:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!float", value.String()
+		if _, err := value.Int64(); err == nil {
+			node.Tag = "!!int"
+		}
+	case bool:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!bool", "false"
+		if value {
+			node.Value = "true"
+		}
+	default:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!null", "null"
+	}
+	return node, nil
+}
+
+// parseObject adds the keys and the values of the object to the mapping node, in the same layout as a YAML mapping
+func (p *jsonNodeParser) parseObject(node *yaml.Node) error {
+	for p.decoder.More() {
+		key, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		node.Content = append(node.Content, key, value)
+	}
+	// The closing delimiter
+	_, err := p.decoder.Token()
+	return err
+}
+
+func (p *jsonNodeParser) parseArray(node *yaml.Node) error {
+	for p.decoder.More() {
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		node.Content = append(node.Content, value)
+	}
+	_, err := p.decoder.Token()
+	return err
+}
+
+// nextTokenPosition returns the line and column of the next token, after the whitespaces and the separators
+func (p *jsonNodeParser) nextTokenPosition() (int, int) {
+	offset := int(p.decoder.InputOffset())
+	for offset < len(p.content) && bytes.IndexByte([]byte(" \t\r\n,:"), p.content[offset]) >= 0 {
+		offset++
+	}
+	return getOffsetPosition(p.content, offset)
+}
+
+// getOffsetPosition returns the line and column (starting from 1) of the byte offset in the content.
+// The column counts characters, the same as in the YAML nodes.
+func getOffsetPosition(content []byte, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	} else if offset < 0 {
+		offset = 0
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
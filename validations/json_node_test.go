@@ -0,0 +1,34 @@
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("getJSONContentNode", func() {
+	It("returns the nodes of the JSON values with their positions", func() {
+		root, err := getJSONContentNode([]byte("{\n\t\"a\": [1, \"\\/x\"],\n  \"b\": {\"c\": null}\n}"))
+		Ω(err).Should(Succeed())
+		Ω(root.Kind).Should(Equal(yaml.MappingNode))
+		array := getPropValueByName(root, "a")
+		Ω(array.Kind).Should(Equal(yaml.SequenceNode))
+		Ω([]int{array.Line, array.Column}).Should(Equal([]int{2, 7}))
+		Ω(array.Content[0].Tag).Should(Equal("!!int"))
+		Ω(array.Content[1].Value).Should(Equal("/x"))
+		Ω([]int{array.Content[1].Line, array.Content[1].Column}).Should(Equal([]int{2, 11}))
+		key := getPropByName(root, "b")
+		Ω([]int{key.Line, key.Column}).Should(Equal([]int{3, 3}))
+		Ω(getPropValueByName(getPropValueByName(root, "b"), "c").Tag).Should(Equal("!!null"))
+	})
+
+	It("returns an error when the content is not valid JSON", func() {
+		_, err := getJSONContentNode([]byte("{\n  \"a\": }"))
+		Ω(err).Should(BeAssignableToTypeOf(&contentSyntaxError{}))
+		Ω([]int{err.(*contentSyntaxError).line, err.(*contentSyntaxError).column}).Should(Equal([]int{2, 8}))
+		_, err = getJSONContentNode([]byte(`{"a": 1} {}`))
+		Ω(err).Should(Equal(&contentSyntaxError{1, 10, unexpectedJSONContentMsg}))
+		_, err = getJSONContentNode([]byte(" \n"))
+		Ω(err).Should(Equal(&contentSyntaxError{2, 1, unexpectedJSONEndMsg}))
+	})
+})
@@ -0,0 +1,361 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+const (
+	// LintConfigFileName is the name of the lint configuration file. It is discovered in the project folder, next to the mta.yaml file.
+	LintConfigFileName = ".mtalint.yaml"
+
+	readLintConfigErrorMsg   = `could not read the %q lint configuration file`
+	unknownRuleInConfigMsg   = `line %d: the "%s" rule is unknown`
+	wrongSeverityInConfigMsg = `line %d: the "%s" severity is incorrect; expected one of the following: error, warning, info, off`
+	wrongRuleConfigMsg       = `line %d: the configuration of the "%s" rule is incorrect; expected a severity, a boolean, or a map with the "enabled" and "severity" fields`
+	wrongRulesConfigMsg      = `line %d: the rules configuration is incorrect; expected a map of rule IDs`
+	wrongPatternInConfigMsg  = `the "%s" pattern is incorrect`
+	wrongRegexpInConfigMsg   = `the "%s" regular expression is incorrect`
+	lintConfigIgnoredMsg     = `the %q lint configuration file is not valid, so the default configuration is used: line %d: %s`
+)
+
+// LintConfig is the lint configuration of a project. It configures the semantic validation rules.
+type LintConfig struct {
+	// Rules configures the rules in all the files
+	Rules RulesConfig `yaml:"rules"`
+	// Overrides configure the rules in specific files, modules and resources. A later override takes precedence.
+	Overrides []*LintOverride `yaml:"overrides"`
+	// Secrets configures the rules which detect secrets in the descriptors
+	Secrets SecretsConfig `yaml:"secrets"`
+}
+
+// SecretsConfig is the configuration of the rules which detect secrets in the values of the parameters and properties
+type SecretsConfig struct {
+	// Allowlist - the values which are not reported as secrets
+	Allowlist SecretsAllowlist `yaml:"allowlist"`
+}
+
+// SecretsAllowlist is the list of the parameters and properties whose values are not reported as secrets.
+// A value is allowed if its key matches one of the key patterns or the value matches one of the value expressions.
+type SecretsAllowlist struct {
+	// Keys - the patterns of the names of the parameters and properties, with the syntax of filepath.Match
+	Keys []string `yaml:"keys"`
+	// Values - the regular expressions of the values, e.g. of documented example values; an expression must match the whole value
+	Values []string `yaml:"values"`
+}
+
+// RulesConfig is the configuration of the rules by their IDs
+type RulesConfig map[string]*RuleConfig
+
+// RuleConfig is the configuration of a rule. In the configuration file it can be written as a severity ("paths: off"),
+// as a boolean ("paths: false") or as a map ("paths: {enabled: true, severity: warning}").
+type RuleConfig struct {
+	// Enabled - false disables the rule
+	Enabled *bool `yaml:"enabled"`
+	// Severity - the severity of the issues reported by the rule: error, warning, info or off.
+	// If it is not set, the issues keep the severity reported by the rule.
+	Severity string `yaml:"severity"`
+}
+
+// LintOverride configures the rules in the files, modules and resources which match its patterns.
+// The patterns have the syntax of filepath.Match; the file patterns match the file paths relative to the project folder.
+// If both file patterns and module or resource patterns are set, an issue must be in a matching file
+// and in a matching module or resource.
+type LintOverride struct {
+	Files     []string    `yaml:"files"`
+	Modules   []string    `yaml:"modules"`
+	Resources []string    `yaml:"resources"`
+	Rules     RulesConfig `yaml:"rules"`
+}
+
+// UnmarshalYAML unmarshals the rules configuration and verifies that the rules are registered
+func (c *RulesConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.Errorf(wrongRulesConfigMsg, node.Line)
+	}
+	*c = make(RulesConfig)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if _, ok := GetRule(keyNode.Value); !ok {
+			return errors.Errorf(unknownRuleInConfigMsg, keyNode.Line, keyNode.Value)
+		}
+		ruleConfig := &RuleConfig{}
+		if err := ruleConfig.unmarshal(keyNode.Value, node.Content[i+1]); err != nil {
+			return err
+		}
+		(*c)[keyNode.Value] = ruleConfig
+	}
+	return nil
+}
+
+func (c *RuleConfig) unmarshal(ruleID string, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!bool" {
+			var enabled bool
+			if err := node.Decode(&enabled); err != nil {
+				return err
+			}
+			c.Enabled = &enabled
+			return nil
+		}
+		c.Severity = node.Value
+	case yaml.MappingNode:
+		type plainRuleConfig RuleConfig
+		if err := node.Decode((*plainRuleConfig)(c)); err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf(wrongRuleConfigMsg, node.Line, ruleID)
+	}
+
+	switch c.Severity {
+	case "", SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return nil
+	}
+	return errors.Errorf(wrongSeverityInConfigMsg, node.Line, c.Severity)
+}
+
+// getSeverity returns the configured severity of the rule: "off" if the rule is disabled,
+// and an empty string if the issues keep the severity reported by the rule
+func (c *RuleConfig) getSeverity() string {
+	if c.Enabled != nil && !*c.Enabled {
+		return SeverityOff
+	}
+	return c.Severity
+}
+
+// LoadLintConfig loads the lint configuration file from the project folder.
+// It returns nil if the project doesn't have a lint configuration file.
+func LoadLintConfig(projectPath string) (*LintConfig, error) {
+	configPath := filepath.Join(projectPath, LintConfigFileName)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return ReadLintConfig(configPath)
+}
+
+// loadLintConfigWithIssues loads the lint configuration of the project. If the configuration file is not valid,
+// it returns no configuration, so the default configuration is used, and the issues of the file as warnings.
+func loadLintConfigWithIssues(projectPath string) (*LintConfig, YamlValidationIssues) {
+	config, err := LoadLintConfig(projectPath)
+	if err == nil {
+		return config, nil
+	}
+	var issues YamlValidationIssues
+	configPath := filepath.Join(projectPath, LintConfigFileName)
+	for _, issue := range convertError(err) {
+		issues = appendIssue(issues, fmt.Sprintf(lintConfigIgnoredMsg, configPath, issue.Line, issue.Msg), 0, 0)
+	}
+	return nil, issues
+}
+
+// ReadLintConfig reads a lint configuration file
+func ReadLintConfig(configPath string) (*LintConfig, error) {
+	content, err := fs.ReadFile(configPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, readLintConfigErrorMsg, configPath)
+	}
+	config := &LintConfig{}
+	if err = yaml.Unmarshal(content, config); err != nil {
+		return nil, err
+	}
+	for _, override := range config.Overrides {
+		for _, pattern := range append(append(append([]string{}, override.Files...), override.Modules...), override.Resources...) {
+			if _, err = filepath.Match(pattern, ""); err != nil {
+				return nil, errors.Wrapf(err, wrongPatternInConfigMsg, pattern)
+			}
+		}
+	}
+	for _, pattern := range config.Secrets.Allowlist.Keys {
+		if _, err = filepath.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, wrongPatternInConfigMsg, pattern)
+		}
+	}
+	for _, expr := range config.Secrets.Allowlist.Values {
+		if _, err = regexp.Compile(expr); err != nil {
+			return nil, errors.Wrapf(err, wrongRegexpInConfigMsg, expr)
+		}
+	}
+	return config, nil
+}
+
+// withDefaultRules returns a copy of the configuration in which the rules which are not configured for all the files
+// have the default configuration
+func (c *LintConfig) withDefaultRules(defaults RulesConfig) *LintConfig {
+	result := &LintConfig{Rules: make(RulesConfig)}
+	for id, ruleConfig := range defaults {
+		result.Rules[id] = ruleConfig
+	}
+	if c != nil {
+		for id, ruleConfig := range c.Rules {
+			result.Rules[id] = ruleConfig
+		}
+		result.Overrides = c.Overrides
+		result.Secrets = c.Secrets
+	}
+	return result
+}
+
+// getExcludedRules returns the comma-separated list of the excluded rules and the rules which are disabled
+// for all the files, modules and resources. Without a configuration the opt-in rules are disabled.
+func (c *LintConfig) getExcludedRules(exclude string) string {
+	if c == nil {
+		return exclude + ruleIDSeparator + strings.Join(optInRules, ruleIDSeparator)
+	}
+	for id, ruleConfig := range c.Rules {
+		if ruleConfig.getSeverity() == SeverityOff && !c.isEnabledInOverride(id) {
+			exclude += ruleIDSeparator + id
+		}
+	}
+	return exclude
+}
+
+func (c *LintConfig) isEnabledInOverride(ruleID string) bool {
+	for _, override := range c.Overrides {
+		if ruleConfig, ok := override.Rules[ruleID]; ok && ruleConfig.getSeverity() != SeverityOff {
+			return true
+		}
+	}
+	return false
+}
+
+// getRuleSeverity returns the configured severity of the rule for an issue in the file and the entity,
+// or an empty string if the issue keeps the severity reported by the rule
+func (c *LintConfig) getRuleSeverity(ruleID string, file string, entity issueEntity) string {
+	severity := ""
+	if ruleConfig, ok := c.Rules[ruleID]; ok {
+		severity = ruleConfig.getSeverity()
+	}
+	for _, override := range c.Overrides {
+		if ruleConfig, ok := override.Rules[ruleID]; ok && override.matches(file, entity) {
+			severity = ruleConfig.getSeverity()
+		}
+	}
+	return severity
+}
+
+func (o *LintOverride) matches(file string, entity issueEntity) bool {
+	if len(o.Files) > 0 && !matchesAny(o.Files, file) {
+		return false
+	}
+	if len(o.Modules) == 0 && len(o.Resources) == 0 {
+		return true
+	}
+	return (entity.kind == moduleEntityKind && matchesAny(o.Modules, entity.name)) ||
+		(entity.kind == resourceEntityKind && matchesAny(o.Resources, entity.name))
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		// The patterns are verified when the configuration is read
+		if matched, _ := filepath.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// applyLintConfig applies the configuration to the issues in the file, and returns the issues by their configured severity.
+// The file is the path of the file relative to the project folder and the root is its content node.
+func (c *LintConfig) applyLintConfig(file string, root *yaml.Node, errIssues, warnIssues YamlValidationIssues) (errs, warns, infos YamlValidationIssues) {
+	if c == nil {
+		return errIssues, warnIssues, nil
+	}
+	file = filepath.ToSlash(file)
+	entities := getIssueEntities(root)
+	add := func(issue YamlValidationIssue, reportedSeverity string) {
+		severity := ""
+		if issue.Rule != "" {
+			severity = c.getRuleSeverity(issue.Rule, file, entities.getEntityAtLine(issue.Line))
+		}
+		if severity == "" {
+			severity = reportedSeverity
+		}
+		switch severity {
+		case SeverityError:
+			errs = append(errs, issue)
+		case SeverityWarning:
+			warns = append(warns, issue)
+		case SeverityInfo:
+			infos = append(infos, issue)
+		}
+	}
+	for _, issue := range errIssues {
+		add(issue, SeverityError)
+	}
+	for _, issue := range warnIssues {
+		add(issue, SeverityWarning)
+	}
+	return errs, warns, infos
+}
+
+// issueEntity is the module or resource in which an issue is found
+type issueEntity struct {
+	kind      string
+	name      string
+	startLine int
+	endLine   int
+}
+
+type issueEntities []issueEntity
+
+// getIssueEntities returns the modules and resources in the descriptor with their lines
+func getIssueEntities(root *yaml.Node) issueEntities {
+	var entities issueEntities
+	for _, section := range []struct{ field, kind string }{{modulesYamlField, moduleEntityKind}, {resourcesYamlField, resourceEntityKind}} {
+		for _, entityNode := range getPropContent(root, section.field) {
+			nameNode := getPropValueByName(entityNode, nameYamlField)
+			if nameNode == nil {
+				continue
+			}
+			entities = append(entities, issueEntity{section.kind, nameNode.Value, entityNode.Line, getLastLine(entityNode)})
+		}
+	}
+	return entities
+}
+
+func (entities issueEntities) getEntityAtLine(line int) issueEntity {
+	for _, entity := range entities {
+		if line >= entity.startLine && line <= entity.endLine {
+			return entity
+		}
+	}
+	return issueEntity{}
+}
+
+// getLastLine returns the last line of the node and its content
+func getLastLine(node *yaml.Node) int {
+	line := node.Line
+	for _, child := range node.Content {
+		if childLine := getLastLine(child); childLine > line {
+			line = childLine
+		}
+	}
+	return line
+}
+
+// getLintConfigIssues returns the issues of a lint configuration which could not be loaded
+func getLintConfigIssues(err error) []FileValidationIssue {
+	issues := make([]FileValidationIssue, 0)
+	for _, issue := range convertError(err) {
+		issues = append(issues, FileValidationIssue{SeverityError, issue.Msg, issue.Line, issue.Column, "", nil, nil})
+	}
+	return issues
+}
+
+func getRelativePath(projectPath, path string) string {
+	relPath, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		return path
+	}
+	return relPath
+}
@@ -0,0 +1,237 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("LintConfig", func() {
+	Describe("LoadLintConfig", func() {
+		It("returns nil when the project doesn't have a lint configuration file", func() {
+			config, err := LoadLintConfig(getTestPath("testproject"))
+			Ω(err).Should(Succeed())
+			Ω(config).Should(BeNil())
+		})
+
+		It("reads the lint configuration file of the project", func() {
+			config, err := LoadLintConfig(getTestPath("lintProject"))
+			Ω(err).Should(Succeed())
+			disabled := false
+			enabled := true
+			Ω(config).Should(Equal(&LintConfig{
+				Rules: RulesConfig{
+					pathsValidation:       {Severity: SeverityError},
+					expressionsValidation: {Severity: SeverityInfo},
+					namesValidation:       {Enabled: &enabled},
+					metadataValidation:    {Enabled: &disabled},
+				},
+				Overrides: []*LintOverride{
+					{Modules: []string{"ui5app2"}, Rules: RulesConfig{deployerConstrValidation: {Severity: SeverityOff}}},
+					{Files: []string{"*.mtaext"}, Rules: RulesConfig{expressionsValidation: {Severity: SeverityWarning}}},
+				},
+			}))
+		})
+
+		It("fails when the lint configuration file is not valid", func() {
+			_, err := LoadLintConfig(getTestPath("lintProject", "badConfig"))
+			Ω(err).Should(MatchError(fmt.Sprintf(unknownRuleInConfigMsg, 3, "unknownRule")))
+		})
+	})
+
+	var _ = DescribeTable("fails to unmarshal a wrong configuration", func(content string, expectedMsg string) {
+		config := &LintConfig{}
+		err := yaml.Unmarshal([]byte(content), config)
+		Ω(err).Should(MatchError(expectedMsg))
+	},
+		Entry("unknown rule", "rules:\n  paths: off\n  unknown: off", fmt.Sprintf(unknownRuleInConfigMsg, 3, "unknown")),
+		Entry("wrong severity", "rules:\n  paths: fatal", fmt.Sprintf(wrongSeverityInConfigMsg, 2, "fatal")),
+		Entry("wrong severity in a map", "rules:\n  paths:\n    severity: fatal", fmt.Sprintf(wrongSeverityInConfigMsg, 3, "fatal")),
+		Entry("rule configuration which is a list", "rules:\n  paths: [off]", fmt.Sprintf(wrongRuleConfigMsg, 2, "paths")),
+		Entry("rules which are a list", "rules:\n  - paths", fmt.Sprintf(wrongRulesConfigMsg, 2)),
+		Entry("rules in an override which are not a map", "overrides:\n- rules: paths", fmt.Sprintf(wrongRulesConfigMsg, 2)),
+	)
+
+	It("fails to read a configuration with a wrong pattern", func() {
+		configPath := getTestPath("lintProject", "badConfig", "badPattern.yaml")
+		_, err := ReadLintConfig(configPath)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(wrongPatternInConfigMsg, "[ui5app")))
+	})
+
+	It("fails to read a configuration with a wrong regular expression in the secrets allowlist", func() {
+		configPath := getTestPath("lintProject", "badConfig", "badRegexp.yaml")
+		_, err := ReadLintConfig(configPath)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(wrongRegexpInConfigMsg, "dummy-(")))
+	})
+
+	It("reads the secrets allowlist", func() {
+		config, err := LoadLintConfig(getTestPath("secretsProject"))
+		Ω(err).Should(Succeed())
+		Ω(config.Secrets.Allowlist).Should(Equal(SecretsAllowlist{Keys: []string{"example-*"}, Values: []string{"dummy-.*"}}))
+		Ω(config.withDefaultRules(RulesConfig{}).Secrets).Should(Equal(config.Secrets))
+	})
+
+	It("fails to read a configuration file which doesn't exist", func() {
+		configPath := getTestPath("lintProject", "notExisting.yaml")
+		_, err := ReadLintConfig(configPath)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(readLintConfigErrorMsg, configPath)))
+	})
+
+	Describe("applyLintConfig", func() {
+		mtaContent := []byte(`
+modules:
+- name: m1
+  type: html5
+
+resources:
+- name: r1
+  type: configuration
+`)
+		issues := YamlValidationIssues{
+			{Msg: "module issue", Line: 3, Column: 3, Rule: namesValidation},
+			{Msg: "resource issue", Line: 8, Column: 3, Rule: namesValidation},
+			{Msg: "schema issue", Line: 4, Column: 3},
+		}
+
+		It("returns the issues by their configured severity in the modules and resources", func() {
+			config := &LintConfig{
+				Rules: RulesConfig{namesValidation: {Severity: SeverityWarning}},
+				Overrides: []*LintOverride{
+					{Modules: []string{"m*"}, Rules: RulesConfig{namesValidation: {Severity: SeverityInfo}}},
+					{Resources: []string{"r1"}, Files: []string{"other.yaml"}, Rules: RulesConfig{namesValidation: {Severity: SeverityOff}}},
+				},
+			}
+			root, _ := getContentNode(mtaContent)
+			errs, warns, infos := config.applyLintConfig("mta.yaml", root, issues, nil)
+			Ω(errs).Should(ConsistOf(issues[2]))
+			Ω(warns).Should(ConsistOf(issues[1]))
+			Ω(infos).Should(ConsistOf(issues[0]))
+
+			errs, warns, infos = config.applyLintConfig("other.yaml", root, issues, nil)
+			Ω(errs).Should(ConsistOf(issues[2]))
+			Ω(warns).Should(BeEmpty())
+			Ω(infos).Should(ConsistOf(issues[0]))
+		})
+
+		It("keeps the reported severities when there is no configuration", func() {
+			var config *LintConfig
+			root, _ := getContentNode(mtaContent)
+			errs, warns, infos := config.applyLintConfig("mta.yaml", root, issues[:1], issues[1:])
+			Ω(errs).Should(Equal(issues[:1]))
+			Ω(warns).Should(Equal(issues[1:]))
+			Ω(infos).Should(BeEmpty())
+		})
+	})
+
+	It("excludes the rules which are disabled everywhere", func() {
+		disabled := false
+		config := &LintConfig{
+			Rules: RulesConfig{
+				pathsValidation:       {Severity: SeverityOff},
+				namesValidation:       {Enabled: &disabled},
+				expressionsValidation: {Severity: SeverityOff},
+			},
+			Overrides: []*LintOverride{{Files: []string{"*.mtaext"}, Rules: RulesConfig{expressionsValidation: {Severity: SeverityWarning}}}},
+		}
+		excluded := parseExcludedRules(config.getExcludedRules(requiredValidation))
+		Ω(excluded).Should(Equal(map[string]bool{pathsValidation: true, namesValidation: true, requiredValidation: true}))
+	})
+
+	Describe("Validate", func() {
+		It("applies the lint configuration of the project", func() {
+			mtaYamlPath := getTestPath("lintProject", "mta.yaml")
+			mtaExtPath := getTestPath("lintProject", "dev.mtaext")
+			result := Validate(mtaYamlPath, []string{mtaExtPath})
+			Ω(result).Should(MatchAllKeys(Keys{
+				mtaYamlPath: ConsistOf(
+					// The deployer constraints issue of the ui5app2 module is disabled by an override
+					MatchAllFields(Fields{
+						"Severity": Equal(SeverityError),
+						"Message":  ContainSubstring(`"ui5app" module does not contain`),
+						"Line":     Equal(8),
+						"Column":   Equal(4),
+						"Rule":     Equal(deployerConstrValidation),
+						"Fixes":    HaveLen(2),
+						"Range":    Not(BeNil()),
+					}),
+					// The paths rule is enabled by the configuration
+					MatchAllFields(Fields{
+						"Severity": Equal(SeverityError),
+						"Message":  Equal(`the "ui5app" path of the "ui5app" module does not exist`),
+						"Line":     Equal(10),
+						"Column":   Equal(10),
+						"Rule":     Equal(pathsValidation),
+						"Fixes":    BeEmpty(),
+						"Range":    Not(BeNil()),
+					}),
+					MatchAllFields(Fields{
+						"Severity": Equal(SeverityInfo),
+						"Message":  ContainSubstring(`"~{url"`),
+						"Line":     Equal(12),
+						"Column":   Equal(11),
+						"Rule":     Equal(expressionsValidation),
+						"Fixes":    BeEmpty(),
+						"Range":    Not(BeNil()),
+					}),
+				),
+				mtaExtPath: ConsistOf(MatchAllFields(Fields{
+					"Severity": Equal(SeverityWarning),
+					"Message":  ContainSubstring(`"${url"`),
+					"Line":     Equal(8),
+					"Column":   Equal(11),
+					"Rule":     Equal(expressionsValidation),
+					"Fixes":    BeEmpty(),
+					"Range":    Not(BeNil()),
+				})),
+			}))
+		})
+
+		It("returns the issues of a lint configuration file which is not valid and uses the default configuration", func() {
+			mtaYamlPath := getTestPath("lintProject", "badConfig", "mta.yaml")
+			result := Validate(mtaYamlPath, nil)
+			Ω(result).Should(HaveKeyWithValue(getTestPath("lintProject", "badConfig", LintConfigFileName), ConsistOf(
+				FileValidationIssue{SeverityError, fmt.Sprintf(`the "%s" rule is unknown`, "unknownRule"), 3, 0, "", nil, nil},
+			)))
+			Ω(result[mtaYamlPath]).Should(HaveLen(3))
+			for _, issue := range result[mtaYamlPath] {
+				Ω(issue.Rule).ShouldNot(Equal(pathsValidation))
+			}
+		})
+	})
+
+	Describe("MtaYaml and Mtaext", func() {
+		It("apply the lint configuration of the project", func() {
+			warn, err := MtaYaml(getTestPath("lintProject"), "mta.yaml", true, true, true, deployerConstrValidation)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(`the "ui5app" path of the "ui5app" module does not exist`))
+			Ω(err.Error()).ShouldNot(ContainSubstring("build configurations"))
+			Ω(warn).Should(ContainSubstring(`line 12: the "~{url" expression is not terminated`))
+
+			warn, err = Mtaext(getTestPath("lintProject"), getTestPath("lintProject", "dev.mtaext"), true, true, true, "")
+			Ω(err).Should(Succeed())
+			Ω(warn).Should(ContainSubstring(`line 8: the "${url" expression is not terminated`))
+		})
+
+		It("return the issues of the lint configuration file which is not valid and use the default configuration", func() {
+			configIssue := fmt.Sprintf(lintConfigIgnoredMsg, getTestPath("lintProject", "badConfig", LintConfigFileName),
+				3, `the "unknownRule" rule is unknown`)
+			warn, err := MtaYaml(getTestPath("lintProject", "badConfig"), "mta.yaml", true, true, true, "")
+			Ω(warn).Should(ContainSubstring(configIssue))
+			Ω(warn).Should(ContainSubstring(`the "~{url" expression is not terminated`))
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(`the "ui5app" path of the "ui5app" module does not exist`))
+
+			warn, err = Mtaext(getTestPath("lintProject", "badConfig"), getTestPath("lintProject", "dev.mtaext"), true, true, true, "")
+			Ω(err).Should(Succeed())
+			Ω(warn).Should(ContainSubstring(configIssue))
+			Ω(warn).Should(ContainSubstring(`the "${url" expression is not terminated`))
+		})
+	})
+})
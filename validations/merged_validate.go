@@ -0,0 +1,175 @@
+package validate
+
+import (
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+type checkMergedSemantic func(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (errors map[string][]YamlValidationIssue, warnings map[string][]YamlValidationIssue)
+
+// fileIssues are the issues of a file by their severity
+type fileIssues struct {
+	errs  YamlValidationIssues
+	warns YamlValidationIssues
+	infos YamlValidationIssues
+}
+
+// getMergedValidations - gets list of all registered merged MTA rules minus excluded rules;
+// exclude is a comma-separated list of rule IDs
+func getMergedValidations(exclude string) []MergedRule {
+	var validations []MergedRule
+	excluded := parseExcludedRules(exclude)
+	for _, rule := range GetRules() {
+		if mergedRule, ok := rule.(MergedRule); ok && !excluded[rule.ID()] {
+			validations = append(validations, mergedRule)
+		}
+	}
+	return validations
+}
+
+// validateMergedMta runs the merged MTA rules on the MTA which is merged with the MTA extension descriptors,
+// and returns the issues by the paths of the descriptors in which they are found.
+// The suppression comments and the lint configuration apply to the issues as in the validation of each descriptor.
+func validateMergedMta(mergedMta *mta.MTA, mtaPath string, extensions []string, config *LintConfig) map[string]*fileIssues {
+	sortedExtensions, err := mta.SortExtensionFiles(extensions, mergedMta.ID, mtaPath)
+	if err != nil {
+		// The merge errors are reported on the extension files
+		return nil
+	}
+	projectPath := filepath.Dir(mtaPath)
+	descriptors := make([]*MergedDescriptor, 0, len(sortedExtensions)+1)
+	contents := make(map[string][]byte)
+	for _, path := range append([]string{mtaPath}, sortedExtensions...) {
+		content, err := fs.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		root, err := getContentNode(content)
+		if err != nil {
+			return nil
+		}
+		descriptors = append(descriptors, &MergedDescriptor{Path: path, Root: root})
+		contents[path] = content
+	}
+
+	errIssues := make(map[string]YamlValidationIssues)
+	warnIssues := make(map[string]YamlValidationIssues)
+	for _, validation := range getMergedValidations(config.getExcludedRules("")) {
+		rule, ok := configureRule(validation, config).(MergedRule)
+		if !ok {
+			rule = validation
+		}
+		errs, warns := rule.CheckMerged(mergedMta, descriptors, true)
+		for path, issues := range errs {
+			errIssues[path] = append(errIssues[path], setIssuesRule(issues, rule.ID())...)
+		}
+		for path, issues := range warns {
+			warnIssues[path] = append(warnIssues[path], setIssuesRule(issues, rule.ID())...)
+		}
+	}
+
+	result := make(map[string]*fileIssues)
+	for _, descriptor := range descriptors {
+		errs, warns := errIssues[descriptor.Path], warnIssues[descriptor.Path]
+		if len(errs) == 0 && len(warns) == 0 {
+			continue
+		}
+		content := contents[descriptor.Path]
+		errs, warns = removeSuppressedIssues(content, errs, warns)
+		issues := &fileIssues{}
+		issues.errs, issues.warns, issues.infos = config.applyLintConfig(getRelativePath(projectPath, descriptor.Path), descriptor.Root, errs, warns)
+		setIssueRanges(content, descriptor.Root, &issues.errs, &issues.warns, &issues.infos)
+		result[descriptor.Path] = issues
+	}
+	return result
+}
+
+// entityStep is a step in the path of an entity in a descriptor: the entity with the name in the list in the field
+type entityStep struct {
+	field string
+	name  string
+}
+
+// getEntityNode returns the node of the entity in the path, or nil if the descriptor doesn't define it.
+// An empty path is the root of the descriptor.
+func getEntityNode(root *yaml.Node, path []entityStep) *yaml.Node {
+	node := root
+	for _, step := range path {
+		var entityNode *yaml.Node
+		for _, node := range getPropContent(node, step.field) {
+			if nameNode := getPropValueByName(node, nameYamlField); nameNode != nil && nameNode.Value == step.name {
+				entityNode = node
+				break
+			}
+		}
+		if entityNode == nil {
+			return nil
+		}
+		node = entityNode
+	}
+	return node
+}
+
+// getSupplyingDescriptor returns the index of the last descriptor which defines the key in the map field of the entity
+// in the path, and the nodes of the key and its value. The value in the merged MTA is the value in that descriptor.
+// It returns -1 if none of the descriptors defines the key.
+func getSupplyingDescriptor(descriptors []*MergedDescriptor, path []entityStep, mapField string, key string) (int, *yaml.Node, *yaml.Node) {
+	for i := len(descriptors) - 1; i >= 0; i-- {
+		mapNode := getPropValueByName(getEntityNode(descriptors[i].Root, path), mapField)
+		if keyNode := getPropByName(mapNode, key); keyNode != nil {
+			return i, keyNode, getPropValueByName(mapNode, key)
+		}
+	}
+	return -1, nil, nil
+}
+
+// mergedMap is a map of parameters or properties in the merged MTA, with its metadata and the path of its entity
+type mergedMap struct {
+	path     []entityStep
+	mapType  int
+	values   map[string]interface{}
+	metadata map[string]mta.MetaData
+}
+
+// getMergedMaps returns the maps of parameters and properties in the MTA which have metadata
+func getMergedMaps(mtaObj *mta.MTA) []mergedMap {
+	var maps []mergedMap
+	add := func(path []entityStep, mapType int, values map[string]interface{}, metadata map[string]mta.MetaData) {
+		if len(metadata) > 0 {
+			maps = append(maps, mergedMap{path, mapType, values, metadata})
+		}
+	}
+	addRequires := func(path []entityStep, requiresList []mta.Requires) {
+		for _, requires := range requiresList {
+			requiresPath := append(path[:len(path):len(path)], entityStep{requiresYamlField, requires.Name})
+			add(requiresPath, mapTypeParameters, requires.Parameters, requires.ParametersMetaData)
+			add(requiresPath, mapTypeProperties, requires.Properties, requires.PropertiesMetaData)
+		}
+	}
+
+	add(nil, mapTypeParameters, mtaObj.Parameters, mtaObj.ParametersMetaData)
+	for _, module := range mtaObj.Modules {
+		modulePath := []entityStep{{modulesYamlField, module.Name}}
+		add(modulePath, mapTypeParameters, module.Parameters, module.ParametersMetaData)
+		add(modulePath, mapTypeProperties, module.Properties, module.PropertiesMetaData)
+		for _, provides := range module.Provides {
+			add(append(modulePath[:1:1], entityStep{providesYamlField, provides.Name}), mapTypeProperties, provides.Properties, provides.PropertiesMetaData)
+		}
+		addRequires(modulePath, module.Requires)
+		for _, hook := range module.Hooks {
+			hookPath := append(modulePath[:1:1], entityStep{hooksYamlField, hook.Name})
+			add(hookPath, mapTypeParameters, hook.Parameters, hook.ParametersMetaData)
+			addRequires(hookPath, hook.Requires)
+		}
+	}
+	for _, resource := range mtaObj.Resources {
+		resourcePath := []entityStep{{resourcesYamlField, resource.Name}}
+		add(resourcePath, mapTypeParameters, resource.Parameters, resource.ParametersMetaData)
+		add(resourcePath, mapTypeProperties, resource.Properties, resource.PropertiesMetaData)
+		addRequires(resourcePath, resource.Requires)
+	}
+	return maps
+}
@@ -17,11 +17,14 @@ import (
 const (
 	SeverityError   = "error"
 	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	// SeverityOff disables a rule in the lint configuration
+	SeverityOff = "off"
 )
 
 type ValidationResult map[string][]FileValidationIssue
 type FileValidationIssue struct {
-	// Severity - the severity of the message. Possible values: "error", "warning".
+	// Severity - the severity of the message. Possible values: "error", "warning", "info".
 	Severity string `json:"severity"`
 	// Message - the validation message
 	Message string `json:"message"`
@@ -29,48 +32,93 @@ type FileValidationIssue struct {
 	Line int `json:"line"`
 	// Column - the column number of the issue
 	Column int `json:"column"`
+	// Rule - the ID of the semantic validation rule which reported the issue; empty for schema and parsing issues
+	Rule string `json:"rule,omitempty"`
+	// Fixes - the quick fixes of the issue; their edits change the file of the issue
+	Fixes []QuickFix `json:"fixes,omitempty"`
+	// Range - the range of the text in which the issue is found; it is not set if the issue is not on a specific line
+	Range *IssueRange `json:"range,omitempty"`
 }
 
 // Validate validates an mta.yaml file and a list of mta extension files, and returns the issues for each file.
+// The rules are configured by the lint configuration file in the folder of the mta.yaml file, if it exists;
+// the opt-in rules run only if the configuration enables them.
 func Validate(mtaPath string, extensions []string) ValidationResult {
 	allIssues := make(ValidationResult)
 	// Assuming the project path is the folder of the mta.yaml
 	projectPath := filepath.Dir(mtaPath)
 	mtaYamlFileName := filepath.Base(mtaPath)
-	// Paths validation is excluded because it doesn't prevent building the MTA (the paths can be created during the build)
-	errorIssues, warningIssues, e := validateMtaYaml(projectPath, mtaYamlFileName, true, true, true, pathsValidation)
+	config, e := LoadLintConfig(projectPath)
+	if e != nil {
+		allIssues[filepath.Join(projectPath, LintConfigFileName)] = getLintConfigIssues(e)
+	}
+	// Paths validation is disabled by default because it doesn't prevent building the MTA (the paths can be created during the build)
+	config = config.withDefaultRules(withOptInRulesOff(RulesConfig{pathsValidation: {Severity: SeverityOff}}))
+	errorIssues, warningIssues, infoIssues, e := validateMtaYaml(projectPath, mtaYamlFileName, true, true, true, "", config)
 	if e != nil {
 		errorIssues = appendIssue(errorIssues, e.Error(), 0, 0)
 	}
-	allIssues[mtaPath] = createFileIssues(warningIssues, errorIssues)
+	issues := map[string]*fileIssues{mtaPath: {errorIssues, warningIssues, infoIssues}}
 
 	for _, extPath := range extensions {
-		errorIssues, warningIssues, e = validateMtaext(projectPath, extPath, true, true, true, "")
+		errorIssues, warningIssues, infoIssues, e = validateMtaext(projectPath, extPath, true, true, true, "", config)
 		if e != nil {
 			errorIssues = appendIssue(errorIssues, e.Error(), 0, 0)
 		}
-		allIssues[extPath] = createFileIssues(warningIssues, errorIssues)
+		issues[extPath] = &fileIssues{errorIssues, warningIssues, infoIssues}
+	}
+
+	mergedMta, _, e := mta.GetMtaFromFile(mtaPath, extensions, true)
+	if mergedMta != nil {
+		// The issues in the security descriptors and the syntax errors in the referenced files are reported on their files too
+		for path, descriptorIssues := range validateSecurityDescriptors(mergedMta, projectPath, config) {
+			issues[path] = descriptorIssues
+		}
+		for path, referencedIssues := range validateReferencedFiles(mergedMta, projectPath, config) {
+			issues[path] = referencedIssues
+		}
+	}
+	if e == nil && len(extensions) > 0 {
+		// The inheritance errors are reported in the validation of the MTA descriptor
+		if effectiveMta, err := mergedMta.Effective(); err == nil {
+			mergedMta = effectiveMta
+		}
+		// The issues of the merged MTA are reported on the descriptors which supply the values
+		for path, merged := range validateMergedMta(mergedMta, mtaPath, extensions, config) {
+			fileIssues := issues[path]
+			fileIssues.errs = append(fileIssues.errs, merged.errs...)
+			fileIssues.warns = append(fileIssues.warns, merged.warns...)
+			fileIssues.infos = append(fileIssues.infos, merged.infos...)
+			fileIssues.errs.Sort()
+			fileIssues.warns.Sort()
+			fileIssues.infos.Sort()
+		}
+	}
+	for path, fileIssues := range issues {
+		allIssues[path] = createFileIssues(fileIssues.warns, fileIssues.errs, fileIssues.infos)
 	}
 
-	_, _, e = mta.GetMtaFromFile(mtaPath, extensions, true)
 	if e != nil {
 		// Ignore errors which are not on a specific extension (if they are on the mta.yaml we already got them earlier)
 		// and parse errors from extensions (we already got them earlier too)
 		if extErr, ok := e.(*mta.ExtensionError); ok && !extErr.IsParseError {
-			allIssues[extErr.FileName] = append(allIssues[extErr.FileName], FileValidationIssue{SeverityError, e.Error(), 0, 0})
+			allIssues[extErr.FileName] = append(allIssues[extErr.FileName], FileValidationIssue{SeverityError, e.Error(), 0, 0, "", nil, nil})
 		}
 	}
 
 	return allIssues
 }
 
-func createFileIssues(warningIssues YamlValidationIssues, errorIssues YamlValidationIssues) []FileValidationIssue {
+func createFileIssues(warningIssues YamlValidationIssues, errorIssues YamlValidationIssues, infoIssues YamlValidationIssues) []FileValidationIssue {
 	allIssues := make([]FileValidationIssue, 0)
 	for _, issue := range errorIssues {
-		allIssues = append(allIssues, FileValidationIssue{SeverityError, issue.Msg, issue.Line, issue.Column})
+		allIssues = append(allIssues, FileValidationIssue{SeverityError, issue.Msg, issue.Line, issue.Column, issue.Rule, issue.Fixes, issue.Range})
 	}
 	for _, issue := range warningIssues {
-		allIssues = append(allIssues, FileValidationIssue{SeverityWarning, issue.Msg, issue.Line, issue.Column})
+		allIssues = append(allIssues, FileValidationIssue{SeverityWarning, issue.Msg, issue.Line, issue.Column, issue.Rule, issue.Fixes, issue.Range})
+	}
+	for _, issue := range infoIssues {
+		allIssues = append(allIssues, FileValidationIssue{SeverityInfo, issue.Msg, issue.Line, issue.Column, issue.Rule, issue.Fixes, issue.Range})
 	}
 
 	return allIssues
@@ -92,12 +140,20 @@ func GetValidationMode(validationFlag string) (bool, bool, error) {
 }
 
 // MtaYaml validates an MTA.yaml file.
+// The rules are configured by the lint configuration file in the project folder, if it exists; the excluded rules don't run
+// regardless of the configuration, and the opt-in rules run only if the configuration enables them.
+// If the configuration file is not valid, its issues are returned with the warnings and the default configuration is used.
+// The issues with an info severity are returned with the warnings.
 func MtaYaml(projectPath, mtaFilename string,
 	validateSchema, validateSemantic, strict bool, exclude string) (warning string, err error) {
-	errIssues, warnIssues, err := validateMtaYaml(projectPath, mtaFilename, validateSchema, validateSemantic, strict, exclude)
+	config, configIssues := loadLintConfigWithIssues(projectPath)
+	config = config.withDefaultRules(withOptInRulesOff(RulesConfig{}))
+	errIssues, warnIssues, infoIssues, err := validateMtaYaml(projectPath, mtaFilename, validateSchema, validateSemantic, strict, exclude, config)
 	if err != nil {
 		return "", err
 	}
+	warnIssues = append(append(configIssues, warnIssues...), infoIssues...)
+	warnIssues.Sort()
 	if len(errIssues) > 0 {
 		return warnIssues.String(), errors.Errorf(`the %q file is not valid: `+"\n%v",
 			filepath.Join(projectPath, mtaFilename), errIssues.String())
@@ -106,28 +162,36 @@ func MtaYaml(projectPath, mtaFilename string,
 }
 
 func validateMtaYaml(projectPath, mtaFilename string, validateSchema, validateSemantic, strict bool,
-	exclude string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues, err error) {
+	exclude string, config *LintConfig) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues, infoIssues YamlValidationIssues, err error) {
 	if validateSemantic || validateSchema {
 		mtaPath := filepath.Join(projectPath, mtaFilename)
 		// ParseFile contains MTA yaml content.
 		yamlContent, e := fs.ReadFile(mtaPath)
 		if e != nil {
-			return nil, nil, errors.Wrapf(e, `could not read the %q file; the validation failed`, mtaPath)
+			return nil, nil, nil, errors.Wrapf(e, `could not read the %q file; the validation failed`, mtaPath)
 		}
 
 		// Validates MTA content.
-		errIssues, warnIssues := validate(yamlContent, projectPath, validateSchema, validateSemantic, strict, exclude)
+		exclude = config.getExcludedRules(exclude)
+		errIssues, warnIssues := validate(yamlContent, projectPath, validateSchema, validateSemantic, strict, exclude, config)
+		if validateSemantic {
+			errIssues, warnIssues = applySuppressions(yamlContent, getRanRules(exclude, false), errIssues, warnIssues)
+		}
+		mtaNode, _ := getContentNode(yamlContent)
+		errIssues, warnIssues, infoIssues = config.applyLintConfig(mtaFilename, mtaNode, errIssues, warnIssues)
+		setIssueRanges(yamlContent, mtaNode, &errIssues, &warnIssues, &infoIssues)
 		errIssues.Sort()
 		warnIssues.Sort()
-		return errIssues, warnIssues, nil
+		infoIssues.Sort()
+		return errIssues, warnIssues, infoIssues, nil
 	}
 
-	return nil, nil, nil
+	return nil, nil, nil, nil
 }
 
 // validate - validates the MTA descriptor
 func validate(yamlContent []byte, projectPath string,
-	validateSchema, validateSemantic, strict bool, exclude string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
+	validateSchema, validateSemantic, strict bool, exclude string, config *LintConfig) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
 
 	mtaStr, err := mta.Unmarshal(yamlContent)
 
@@ -166,7 +230,7 @@ func validate(yamlContent []byte, projectPath string,
 	}
 
 	if validateSemantic {
-		errs, warns := runSemanticValidations(mtaStr, mtaNode, projectPath, exclude, strict)
+		errs, warns := runSemanticValidations(mtaStr, mtaNode, projectPath, exclude, strict, config)
 		errIssues = append(errIssues, errs...)
 		warnIssues = append(warnIssues, warns...)
 	}
@@ -106,6 +106,9 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring(fs.PathNotFoundMsg, mtaYamlPath),
 						"Line":     Equal(0),
 						"Column":   Equal(0),
+						"Rule":     Equal(""),
+						"Fixes":    BeEmpty(),
+						"Range":    BeNil(),
 					})),
 				}))
 			})
@@ -121,6 +124,9 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring(fs.PathNotFoundMsg, mtaExtPath),
 						"Line":     Equal(0),
 						"Column":   Equal(0),
+						"Rule":     Equal(""),
+						"Fixes":    BeEmpty(),
+						"Range":    BeNil(),
 					})),
 				}))
 			})
@@ -136,18 +142,27 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("path"),
 							"Line":     Equal(17),
 							"Column":   Equal(4),
+							"Rule":     Equal(emptyPathValidation),
+							"Fixes":    BeEmpty(),
+							"Range":    Not(BeNil()),
 						}),
 						MatchAllFields(Fields{
 							"Severity": Equal("error"),
 							"Message":  ContainSubstring("type1"),
 							"Line":     Equal(30),
 							"Column":   Equal(0), // Unmarhshal errors return without a column
+							"Rule":     Equal(""),
+							"Fixes":    BeEmpty(),
+							"Range":    Not(BeNil()),
 						}),
 						MatchAllFields(Fields{
 							"Severity": Equal("error"),
 							"Message":  ContainSubstring("abc"),
 							"Line":     Equal(28),
 							"Column":   Equal(0), // Unmarhshal errors return without a column
+							"Rule":     Equal(""),
+							"Fixes":    BeEmpty(),
+							"Range":    Not(BeNil()),
 						}),
 					),
 					mtaExtPath: ConsistOf(MatchAllFields(Fields{
@@ -155,6 +170,9 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring("type"),
 						"Line":     Equal(16),
 						"Column":   Equal(0),
+						"Rule":     Equal(""),
+						"Fixes":    BeEmpty(),
+						"Range":    Not(BeNil()),
 					})),
 				}))
 			})
@@ -170,6 +188,9 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring("ui5app3"),
 						"Line":     Equal(0), // We don't return the location for merge errors
 						"Column":   Equal(0),
+						"Rule":     Equal(""),
+						"Fixes":    BeEmpty(),
+						"Range":    BeNil(),
 					})),
 				}))
 			})
@@ -188,6 +209,9 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("dest_mtahtml5"),
 							"Line":     Equal(14),
 							"Column":   Equal(13),
+							"Rule":     Equal(requiredValidation),
+							"Fixes":    BeEmpty(),
+							"Range":    Not(BeNil()),
 						}),
 						// Duplicated resource name
 						MatchAllFields(Fields{
@@ -195,6 +219,9 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("uaa_mtahtml5"),
 							"Line":     Equal(18),
 							"Column":   Equal(10),
+							"Rule":     Equal(namesValidation),
+							"Fixes":    BeEmpty(),
+							"Range":    Not(BeNil()),
 						}),
 					),
 					mtaExtPath1: ConsistOf(
@@ -205,6 +232,9 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("ui5app"),
 							"Line":     Equal(12),
 							"Column":   Equal(10),
+							"Rule":     Equal(namesValidation),
+							"Fixes":    BeEmpty(),
+							"Range":    Not(BeNil()),
 						}),
 					),
 					mtaExtPath2: ConsistOf(
@@ -213,6 +243,9 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("mtahtml5_unknown"),
 							"Line":     Equal(0),
 							"Column":   Equal(0),
+							"Rule":     Equal(""),
+							"Fixes":    BeEmpty(),
+							"Range":    BeNil(),
 						}),
 					),
 				}))
@@ -272,7 +305,7 @@ var _ = Describe("MTA tests", func() {
 		var _ = Describe("validate - unmarshalling fails", func() {
 			It("Sanity", func() {
 				err, warn := validate([]byte("bad Yaml"), getTestPath("mtahtml5"),
-					true, false, true, "")
+					true, false, true, "", nil)
 				Ω(warn).Should(BeNil())
 				Ω(err).ShouldNot(BeNil())
 				Ω(len(err)).Should(Equal(5))
@@ -281,7 +314,7 @@ var _ = Describe("MTA tests", func() {
 
 			It("Empty mta content", func() {
 				err, warn := validate([]byte(""), getTestPath("mtahtml5"),
-					true, false, true, "")
+					true, false, true, "", nil)
 				Ω(warn).Should(BeNil())
 				Ω(err).ShouldNot(BeNil())
 				Ω(err[0].Msg).Should(Equal("EOF"))
@@ -332,15 +365,15 @@ modules:
     b:
       datatype: float
 `), getTestPath("mtahtml5"),
-					true, false, true, "")
+					true, false, true, "", nil)
 				Ω(warn).Should(BeNil())
-				Ω(err).Should(ConsistOf(
-					YamlValidationIssue{"cannot unmarshal !!str `abc` into bool", 10, 0},
-					YamlValidationIssue{`the "parameters-metadata.param1.overwritable" property must be a boolean`, 10, 19},
-					YamlValidationIssue{"cannot unmarshal !!int `12` into bool", 19, 0},
-					YamlValidationIssue{`the "modules[0].parameters-metadata.memory.optional" property must be a boolean`, 19, 17},
-					YamlValidationIssue{"cannot unmarshal !!str `is it?` into bool", 25, 0},
-					YamlValidationIssue{`the "some_type" value of the "modules[0].properties-metadata.a.datatype" enum property is invalid; expected one of the following: str,int,float,bool`, 26, 17},
+				Ω(withoutNodes(err)).Should(ConsistOf(
+					YamlValidationIssue{Msg: "cannot unmarshal !!str `abc` into bool", Line: 10, Column: 0},
+					YamlValidationIssue{Msg: `the "parameters-metadata.param1.overwritable" property must be a boolean`, Line: 10, Column: 19},
+					YamlValidationIssue{Msg: "cannot unmarshal !!int `12` into bool", Line: 19, Column: 0},
+					YamlValidationIssue{Msg: `the "modules[0].parameters-metadata.memory.optional" property must be a boolean`, Line: 19, Column: 17},
+					YamlValidationIssue{Msg: "cannot unmarshal !!str `is it?` into bool", Line: 25, Column: 0},
+					YamlValidationIssue{Msg: `the "some_type" value of the "modules[0].properties-metadata.a.datatype" enum property is invalid; expected one of the following: str,int,float,bool`, Line: 26, Column: 17},
 				))
 			})
 
@@ -369,7 +402,7 @@ modules:
     b:
       datatype: bool
 `), getTestPath("mtahtml5"),
-					true, false, true, "")
+					true, false, true, "", nil)
 				Ω(warn).Should(BeNil())
 				Ω(err).Should(BeNil())
 			})
@@ -409,14 +442,14 @@ resources:
       overwritable: false
       datatype: float
 `), getTestPath("mtahtml5"),
-					true, false, true, "")
+					true, false, true, "", nil)
 				Ω(warn).Should(BeNil())
 
 				datatypeNotAllowedForParametersMetadata := fmt.Sprintf(propertyExistsErrorMsg, datatypeYamlField, parametersMetadataField)
-				Ω(err).Should(ConsistOf(
-					YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 10, 5},
-					YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 22, 7},
-					YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 33, 7},
+				Ω(withoutNodes(err)).Should(ConsistOf(
+					YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 10, Column: 5},
+					YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 22, Column: 7},
+					YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 33, Column: 7},
 				))
 			})
 
@@ -0,0 +1,316 @@
+package validate
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// QuickFixKind is the kind of the quick fixes, as the kind of a code action in the Language Server Protocol
+	QuickFixKind = "quickfix"
+
+	applyFixesErrorMsg = `could not apply the fixes to the "%s" file`
+)
+
+// Position is a zero-based position in a text document, as in the Language Server Protocol
+type Position struct {
+	// Line - the zero-based line number
+	Line int `json:"line"`
+	// Character - the zero-based offset of the character in the line
+	Character int `json:"character"`
+}
+
+// Range is a range in a text document. The end position is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// TextEdit is a change of a text document: the text in the range is replaced with the new text.
+// An empty range inserts the new text.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// QuickFix is a fix of a validation issue. It has the fields of a code action in the Language Server Protocol,
+// except for the edits, which are changes of the file in which the issue is found.
+type QuickFix struct {
+	Title string `json:"title"`
+	Kind  string `json:"kind"`
+	// IsPreferred - true if the fix is safe to apply without a review; the preferred fixes are applied by ApplyFixes
+	IsPreferred bool       `json:"isPreferred,omitempty"`
+	Edits       []TextEdit `json:"edits"`
+}
+
+func newQuickFix(title string, preferred bool, edits ...TextEdit) []QuickFix {
+	return []QuickFix{{Title: title, Kind: QuickFixKind, IsPreferred: preferred, Edits: edits}}
+}
+
+// getBlockEndLine returns the last line of the node and its content in the document of the root node.
+// The values which can span several lines end on the line before the next node in the document and its head comment,
+// because the nodes don't have end positions. It returns false if such a value is at the end of the document.
+func getBlockEndLine(root *yaml.Node, node *yaml.Node) (int, bool) {
+	last := node
+	for last.Kind != yaml.ScalarNode && last.Kind != yaml.AliasNode && last.Style&yaml.FlowStyle == 0 && len(last.Content) > 0 {
+		last = last.Content[len(last.Content)-1]
+	}
+	if isSingleLine(last) {
+		return last.Line, true
+	}
+	nextLine := getNextNodeLine(root, last)
+	if nextLine == 0 {
+		return 0, false
+	}
+	if nextLine-1 < last.Line {
+		return last.Line, true
+	}
+	return nextLine - 1, true
+}
+
+// isSingleLine returns true if the node certainly ends on its line. A plain or quoted scalar can span several lines,
+// and its line breaks are folded into spaces, so only the scalars without whitespaces certainly end on their lines.
+// A flow collection is assumed to end on its line if its content is on that line.
+func isSingleLine(node *yaml.Node) bool {
+	switch {
+	case node.Style&yaml.FlowStyle != 0:
+		return getLastLine(node) == node.Line
+	case node.Kind == yaml.ScalarNode:
+		return node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 && !strings.ContainsAny(node.Value, " \t\n\r")
+	}
+	return true
+}
+
+// getNextNodeLine returns the line of the node which follows the node and its content in the document, or the line
+// of its head comment; it returns 0 if the node is at the end of the document
+func getNextNodeLine(root *yaml.Node, node *yaml.Node) int {
+	var next *yaml.Node
+	found := false
+	var visit func(n *yaml.Node) bool
+	visit = func(n *yaml.Node) bool {
+		if found {
+			next = n
+			return true
+		}
+		if n == node {
+			found = true
+			return false
+		}
+		for _, child := range n.Content {
+			if visit(child) {
+				return true
+			}
+		}
+		return false
+	}
+	visit(root)
+	if next == nil {
+		return 0
+	}
+	if next.HeadComment != "" {
+		return next.Line - strings.Count(next.HeadComment, "\n") - 1
+	}
+	return next.Line
+}
+
+// getAddEntryEdit returns an edit which adds the lines at the end of the block mapping, with the indentation of its keys.
+// The lines of the new text are indented relative to the keys. The root is the content node of the document of the mapping.
+func getAddEntryEdit(root *yaml.Node, mapNode *yaml.Node, lines ...string) (TextEdit, bool) {
+	if mapNode == nil || mapNode.Kind != yaml.MappingNode || mapNode.Style&yaml.FlowStyle != 0 || len(mapNode.Content) == 0 {
+		return TextEdit{}, false
+	}
+	endLine, ok := getBlockEndLine(root, mapNode)
+	if !ok {
+		return TextEdit{}, false
+	}
+	indent := strings.Repeat(" ", mapNode.Content[0].Column-1)
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(indent + line + "\n")
+	}
+	// The zero-based line after the end line
+	position := Position{Line: endLine, Character: 0}
+	return TextEdit{Range: Range{position, position}, NewText: sb.String()}, true
+}
+
+// getRemoveEntryEdit returns an edit which removes the lines of the key and its value from the block mapping.
+// The mapping must be the value of another key, so its first key is not on the line of a sequence item.
+// The root is the content node of the document of the mapping.
+func getRemoveEntryEdit(root *yaml.Node, mapNode *yaml.Node, key string) (TextEdit, bool) {
+	if mapNode == nil || mapNode.Kind != yaml.MappingNode || mapNode.Style&yaml.FlowStyle != 0 {
+		return TextEdit{}, false
+	}
+	for i := 0; i+1 < len(mapNode.Content); i += 2 {
+		keyNode := mapNode.Content[i]
+		if keyNode.Value != key {
+			continue
+		}
+		endLine, ok := getBlockEndLine(root, mapNode.Content[i+1])
+		if !ok || (i > 0 && mapNode.Content[i-1].Line >= keyNode.Line) {
+			return TextEdit{}, false
+		}
+		return TextEdit{Range: Range{Position{keyNode.Line - 1, 0}, Position{endLine, 0}}, NewText: ""}, true
+	}
+	return TextEdit{}, false
+}
+
+// getReplaceQuotedScalarEdit returns an edit which replaces the quoted scalar with the new text
+func getReplaceQuotedScalarEdit(node *yaml.Node, newText string) (TextEdit, bool) {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) == 0 ||
+		strings.ContainsAny(node.Value, "'\"\\\n\r") {
+		return TextEdit{}, false
+	}
+	start := Position{node.Line - 1, node.Column - 1}
+	end := Position{node.Line - 1, node.Column - 1 + len([]rune(node.Value)) + 2}
+	return TextEdit{Range: Range{start, end}, NewText: newText}, true
+}
+
+// ApplyFixes applies the preferred fixes of the issues in the validation result to the files, and returns the number
+// of the applied fixes. A fix is not applied if one of its edits overlaps an edit of a fix which is applied before it.
+// A fix is not applied either if the content of the file is not valid YAML after it is applied.
+func ApplyFixes(result ValidationResult) (int, error) {
+	count := 0
+	files, issues := result.getReportIssues()
+	for _, file := range files {
+		var fixes []QuickFix
+		for _, issue := range issues[file] {
+			for _, fix := range issue.Fixes {
+				if fix.IsPreferred {
+					fixes = append(fixes, fix)
+				}
+			}
+		}
+		if len(fixes) == 0 {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return count, errors.Wrapf(err, applyFixesErrorMsg, file)
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return count, errors.Wrapf(err, applyFixesErrorMsg, file)
+		}
+		fixedContent, fixesCount := applyValidFixes(content, fixes)
+		if fixesCount == 0 {
+			continue
+		}
+		if err = ioutil.WriteFile(file, fixedContent, info.Mode()); err != nil {
+			return count, errors.Wrapf(err, applyFixesErrorMsg, file)
+		}
+		count += fixesCount
+	}
+	return count, nil
+}
+
+// applyValidFixes applies the fixes to the content, except the fixes after which the content is not valid YAML,
+// and returns the changed content and the number of the applied fixes
+func applyValidFixes(content []byte, fixes []QuickFix) ([]byte, int) {
+	var validFixes []QuickFix
+	for _, fix := range fixes {
+		candidates := append(validFixes[:len(validFixes):len(validFixes)], fix)
+		fixedContent, _ := applyFixesToContent(content, candidates)
+		var node yaml.Node
+		if yaml.Unmarshal(fixedContent, &node) == nil {
+			validFixes = candidates
+		}
+	}
+	return applyFixesToContent(content, validFixes)
+}
+
+// textEditOffsets is a text edit with the byte offsets of its range
+type textEditOffsets struct {
+	start   int
+	end     int
+	newText string
+}
+
+// applyFixesToContent applies the fixes to the content, and returns the changed content and the number of the applied fixes.
+// The content outside of the edits, including the line breaks, is kept.
+func applyFixesToContent(content []byte, fixes []QuickFix) ([]byte, int) {
+	lineBreak := "\n"
+	if bytes.Contains(content, []byte("\r\n")) {
+		lineBreak = "\r\n"
+	}
+	lineStarts := getLineStarts(content)
+
+	var applied []textEditOffsets
+	count := 0
+	for _, fix := range fixes {
+		var edits []textEditOffsets
+		overlaps := false
+		for _, edit := range fix.Edits {
+			e := textEditOffsets{
+				getOffset(content, lineStarts, edit.Range.Start),
+				getOffset(content, lineStarts, edit.Range.End),
+				strings.Replace(edit.NewText, "\n", lineBreak, -1),
+			}
+			for _, other := range applied {
+				if e.start < other.end && other.start < e.end {
+					overlaps = true
+				}
+			}
+			edits = append(edits, e)
+		}
+		if !overlaps {
+			applied = append(applied, edits...)
+			count++
+		}
+	}
+
+	// The texts which are inserted at the same offset are kept in the order of the fixes,
+	// before the text which is replaced at that offset
+	sort.SliceStable(applied, func(i, j int) bool {
+		return applied[i].start < applied[j].start || applied[i].start == applied[j].start && applied[i].end < applied[j].end
+	})
+	var result bytes.Buffer
+	offset := 0
+	for _, e := range applied {
+		result.Write(content[offset:e.start])
+		if e.start == len(content) && e.newText != "" && result.Len() > 0 && !bytes.HasSuffix(result.Bytes(), []byte(lineBreak)) {
+			// The text is added after the last line, which doesn't end with a line break
+			result.WriteString(lineBreak)
+		}
+		result.WriteString(e.newText)
+		offset = e.end
+	}
+	result.Write(content[offset:])
+	return result.Bytes(), count
+}
+
+// getLineStarts returns the byte offsets of the lines in the content
+func getLineStarts(content []byte) []int {
+	starts := []int{0}
+	for i, b := range content {
+		if b == '\n' || b == '\r' && (i+1 == len(content) || content[i+1] != '\n') {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// getOffset returns the byte offset of the position in the content. The positions after the end of a line
+// or after the end of the content are moved to the end.
+func getOffset(content []byte, lineStarts []int, position Position) int {
+	if position.Line >= len(lineStarts) {
+		return len(content)
+	}
+	offset := lineStarts[position.Line]
+	lineEnd := len(content)
+	if position.Line+1 < len(lineStarts) {
+		lineEnd = lineStarts[position.Line+1]
+	}
+	for i := 0; i < position.Character && offset < lineEnd; i++ {
+		_, size := utf8.DecodeRune(content[offset:lineEnd])
+		offset += size
+	}
+	return offset
+}
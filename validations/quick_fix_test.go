@@ -0,0 +1,171 @@
+package validate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuickFix", func() {
+	var _ = DescribeTable("applyFixesToContent", func(content string, fixes []QuickFix, expectedContent string, expectedCount int) {
+		result, count := applyFixesToContent([]byte(content), fixes)
+		Ω(string(result)).Should(Equal(expectedContent))
+		Ω(count).Should(Equal(expectedCount))
+	},
+		Entry("inserts, removes and replaces text",
+			"a:\n  b: 1\n  c: 'x'\nd: 2\n",
+			[]QuickFix{
+				newQuickFix("insert", true, TextEdit{Range{Position{3, 0}, Position{3, 0}}, "  e: 3\n"})[0],
+				newQuickFix("remove", true, TextEdit{Range{Position{1, 0}, Position{2, 0}}, ""})[0],
+				newQuickFix("replace", true, TextEdit{Range{Position{2, 5}, Position{2, 8}}, "y"})[0],
+			},
+			"a:\n  c: y\n  e: 3\nd: 2\n", 3),
+		Entry("keeps the order of the texts inserted at the same position",
+			"a:\n  b: 1\n",
+			[]QuickFix{
+				newQuickFix("first", true, TextEdit{Range{Position{2, 0}, Position{2, 0}}, "  c: 2\n"})[0],
+				newQuickFix("second", true, TextEdit{Range{Position{2, 0}, Position{2, 0}}, "  d: 3\n"})[0],
+			},
+			"a:\n  b: 1\n  c: 2\n  d: 3\n", 2),
+		Entry("doesn't apply a fix which overlaps a previous fix",
+			"a: 1\nb: 2\nc: 3\n",
+			[]QuickFix{
+				newQuickFix("remove", true, TextEdit{Range{Position{0, 0}, Position{2, 0}}, ""})[0],
+				newQuickFix("overlapping", true,
+					TextEdit{Range{Position{2, 0}, Position{2, 0}}, "d: 4\n"},
+					TextEdit{Range{Position{1, 3}, Position{1, 4}}, "5"})[0],
+			},
+			"c: 3\n", 1),
+		Entry("adds a line break before the text inserted after the last line",
+			"a:\n  b: 1",
+			[]QuickFix{newQuickFix("insert", true, TextEdit{Range{Position{2, 0}, Position{2, 0}}, "  c: 2\n"})[0]},
+			"a:\n  b: 1\n  c: 2\n", 1),
+		Entry("keeps the Windows line breaks",
+			"a:\r\n  b: 1\r\n",
+			[]QuickFix{newQuickFix("insert", true, TextEdit{Range{Position{2, 0}, Position{2, 0}}, "  c: 2\n"})[0]},
+			"a:\r\n  b: 1\r\n  c: 2\r\n", 1),
+	)
+
+	It("doesn't return edits in flow style mappings and multi-line scalars", func() {
+		root, err := getContentNode([]byte("a: {b: 1}\nc:\n  d: |\n    text\n"))
+		Ω(err).Should(Succeed())
+		_, ok := getAddEntryEdit(root, getPropValueByName(root, "a"), "e: 2")
+		Ω(ok).Should(BeFalse())
+		_, ok = getRemoveEntryEdit(root, getPropValueByName(root, "a"), "b")
+		Ω(ok).Should(BeFalse())
+		_, ok = getAddEntryEdit(root, getPropValueByName(root, "c"), "e: 2")
+		Ω(ok).Should(BeFalse())
+		_, ok = getRemoveEntryEdit(root, getPropValueByName(root, "c"), "d")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("returns edits after the multi-line scalars which are followed by other nodes", func() {
+		root, err := getContentNode([]byte("a:\n  b: first\n    second\n  c: 'first\n    second'\n# comment\nd:\n  e: |\n    text\nf: 1\n"))
+		Ω(err).Should(Succeed())
+		edit, ok := getAddEntryEdit(root, getPropValueByName(root, "a"), "g: 2")
+		Ω(ok).Should(BeTrue())
+		Ω(edit).Should(Equal(TextEdit{Range{Position{5, 0}, Position{5, 0}}, "  g: 2\n"}))
+		edit, ok = getRemoveEntryEdit(root, getPropValueByName(root, "a"), "b")
+		Ω(ok).Should(BeTrue())
+		Ω(edit).Should(Equal(TextEdit{Range{Position{1, 0}, Position{3, 0}}, ""}))
+		edit, ok = getAddEntryEdit(root, getPropValueByName(root, "d"), "g: 2")
+		Ω(ok).Should(BeTrue())
+		Ω(edit).Should(Equal(TextEdit{Range{Position{9, 0}, Position{9, 0}}, "  g: 2\n"}))
+	})
+
+	Describe("ApplyFixes", func() {
+		BeforeEach(func() {
+			Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Ω(os.RemoveAll(getTestPath("result"))).Should(Succeed())
+		})
+
+		It("applies the preferred fixes to the files and keeps their formatting", func() {
+			content, err := ioutil.ReadFile(getTestPath("fixProject", "mta.yaml"))
+			Ω(err).Should(Succeed())
+			mtaPath := getTestPath("result", "mta.yaml")
+			Ω(ioutil.WriteFile(mtaPath, content, os.ModePerm)).Should(Succeed())
+
+			count, err := ApplyFixes(Validate(mtaPath, nil))
+			Ω(err).Should(Succeed())
+			Ω(count).Should(Equal(3))
+			fixedContent, err := ioutil.ReadFile(mtaPath)
+			Ω(err).Should(Succeed())
+			Ω(string(fixedContent)).Should(Equal(`ID: fixproject
+_schema-version: '3.1'
+version: 0.0.1
+
+parameters:
+  deploy_mode: html5-repo
+parameters-metadata:
+  deploy_mode:
+    overwritable: false
+  memory:
+    optional: true
+
+modules:
+- name: ui5app
+  type: html5
+  path: ui5app
+  build-parameters:
+    builder: custom
+    no-source: false
+    supported-platforms: []
+
+- name: ui5app2
+  type: html5
+  path: ui5app2
+  build-parameters: {builder: npm, npm-opts: abc}
+`))
+
+			// The issues without preferred fixes remain
+			result := Validate(mtaPath, nil)
+			_, issues := result.getReportIssues()
+			Ω(issues[mtaPath]).Should(HaveLen(5))
+			Ω(issues[mtaPath][0].Fixes).Should(Equal(getRemoveMetadataFix("memory", parameterEntityKind, 10, 11)))
+			Ω(issues[mtaPath][1].Message).Should(Equal(fmt.Sprintf(missingConfigMsg, "ui5app", buildResultYamlField, missingConfigDocLink)))
+			Ω(issues[mtaPath][1].Fixes).Should(Equal(newQuickFix(addBuildResultFixTitle, false,
+				TextEdit{Range{Position{20, 0}, Position{20, 0}}, "    build-result: dist\n"}),
+			))
+			Ω(issues[mtaPath][2].Fixes).Should(Equal(newQuickFix(addCommandsFixTitle, false,
+				TextEdit{Range{Position{20, 0}, Position{20, 0}}, "    commands: []\n"}),
+			))
+			count, err = ApplyFixes(result)
+			Ω(err).Should(Succeed())
+			Ω(count).Should(Equal(0))
+		})
+
+		It("doesn't apply the fixes after which the file is not valid", func() {
+			mtaPath := getTestPath("result", "mta.yaml")
+			content := []byte("a: 1\nb: 2\n")
+			Ω(ioutil.WriteFile(mtaPath, content, os.ModePerm)).Should(Succeed())
+			result := ValidationResult{mtaPath: {
+				{Severity: SeverityError, Fixes: newQuickFix("wrong", true, TextEdit{Range{Position{0, 3}, Position{0, 3}}, "["})},
+				{Severity: SeverityError, Fixes: newQuickFix("right", true, TextEdit{Range{Position{1, 3}, Position{1, 4}}, "3"})},
+			}}
+			count, err := ApplyFixes(result)
+			Ω(err).Should(Succeed())
+			Ω(count).Should(Equal(1))
+			Ω(ioutil.ReadFile(mtaPath)).Should(Equal([]byte("a: 1\nb: 3\n")))
+
+			count, err = ApplyFixes(ValidationResult{mtaPath: result[mtaPath][:1]})
+			Ω(err).Should(Succeed())
+			Ω(count).Should(Equal(0))
+			Ω(ioutil.ReadFile(mtaPath)).Should(Equal([]byte("a: 1\nb: 3\n")))
+		})
+
+		It("fails when the file doesn't exist", func() {
+			mtaPath := getTestPath("result", "notExisting.yaml")
+			result := ValidationResult{mtaPath: {{Severity: SeverityError, Fixes: newQuickFix("fix", true)}}}
+			_, err := ApplyFixes(result)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(applyFixesErrorMsg, mtaPath)))
+		})
+	})
+})
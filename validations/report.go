@@ -0,0 +1,348 @@
+package validate
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/internal/version"
+)
+
+// The formats of the validation report
+const (
+	ReportFormatSarif      = "sarif"
+	ReportFormatJUnit      = "junit"
+	ReportFormatCheckstyle = "checkstyle"
+	ReportFormatText       = "text"
+)
+
+const (
+	wrongReportFormatMsg = `the "%s" report format is incorrect; expected one of the following: sarif, junit, checkstyle, text`
+	wrongSeverityMsg     = `the "%s" severity is incorrect; expected one of the following: error, warning, info`
+
+	toolName           = "mta"
+	toolInformationURI = "https://github.com/SAP/cloud-mta"
+	sarifSchema        = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion       = "2.1.0"
+	checkstyleVersion  = "4.3"
+	// schemaIssueName is the name of the issues which are not reported by a rule, in the reports which require a name
+	schemaIssueName = "schema"
+)
+
+// severityLevels orders the severities
+var severityLevels = map[string]int{SeverityInfo: 1, SeverityWarning: 2, SeverityError: 3}
+
+// getReportIssues returns the issues of the validation result ordered by file, line and column
+func (result ValidationResult) getReportIssues() (files []string, issues map[string][]FileValidationIssue) {
+	issues = make(map[string][]FileValidationIssue)
+	for file, fileIssues := range result {
+		files = append(files, file)
+		sorted := append([]FileValidationIssue{}, fileIssues...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Line < sorted[j].Line || (sorted[i].Line == sorted[j].Line && sorted[i].Column < sorted[j].Column)
+		})
+		issues[file] = sorted
+	}
+	sort.Strings(files)
+	return files, issues
+}
+
+// CountIssues returns the number of issues with the severity or a higher severity (errors are higher than warnings,
+// and warnings are higher than infos)
+func (result ValidationResult) CountIssues(minSeverity string) (int, error) {
+	minLevel, ok := severityLevels[minSeverity]
+	if !ok {
+		return 0, errors.Errorf(wrongSeverityMsg, minSeverity)
+	}
+	count := 0
+	for _, fileIssues := range result {
+		for _, issue := range fileIssues {
+			if severityLevels[issue.Severity] >= minLevel {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// WriteReport writes the validation result in the report format: SARIF 2.1.0, JUnit XML, checkstyle XML or text
+func WriteReport(w io.Writer, result ValidationResult, format string) error {
+	switch format {
+	case ReportFormatSarif:
+		return writeSarifReport(w, result)
+	case ReportFormatJUnit:
+		return writeJUnitReport(w, result)
+	case ReportFormatCheckstyle:
+		return writeCheckstyleReport(w, result)
+	case ReportFormatText:
+		return writeTextReport(w, result)
+	}
+	return errors.Errorf(wrongReportFormatMsg, format)
+}
+
+// writeTextReport writes the issues in the "<file>:<line>:<column>: <severity>: <message> [<rule>]" format, and a summary
+func writeTextReport(w io.Writer, result ValidationResult) error {
+	files, issues := result.getReportIssues()
+	counts := make(map[string]int)
+	var sb strings.Builder
+	for _, file := range files {
+		for _, issue := range issues[file] {
+			counts[issue.Severity]++
+			sb.WriteString(fmt.Sprintf("%s:%d:%d: %s: %s", file, issue.Line, issue.Column, issue.Severity, issue.Message))
+			if issue.Rule != "" {
+				sb.WriteString(fmt.Sprintf(" [%s]", issue.Rule))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%d errors, %d warnings, %d infos\n", counts[SeverityError], counts[SeverityWarning], counts[SeverityInfo]))
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration *sarifRuleDefaults `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// getSarifLevel returns the SARIF level of the severity
+func getSarifLevel(severity string) string {
+	if severity == SeverityInfo {
+		return "note"
+	}
+	return severity
+}
+
+// getFileURI returns the URI of the file path: a relative reference for a relative path and a file URI for an absolute path
+func getFileURI(path string) string {
+	uri := filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		if !strings.HasPrefix(uri, "/") {
+			uri = "/" + uri
+		}
+		return "file://" + uri
+	}
+	return uri
+}
+
+func writeSarifReport(w io.Writer, result ValidationResult) error {
+	v, _ := version.GetVersion()
+	driver := sarifDriver{Name: toolName, InformationURI: toolInformationURI, Version: v.CliVersion, Rules: []sarifRule{}}
+	run := sarifRun{Results: []sarifResult{}}
+	ruleAdded := make(map[string]bool)
+
+	files, issues := result.getReportIssues()
+	for _, file := range files {
+		for _, issue := range issues[file] {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: getFileURI(file)}}}
+			// SARIF lines and columns start from 1; 0 means that the location is unknown
+			if issue.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+				if issue.Range != nil {
+					location.PhysicalLocation.Region.EndLine = issue.Range.End.Line
+					location.PhysicalLocation.Region.EndColumn = issue.Range.End.Column
+				}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    issue.Rule,
+				Level:     getSarifLevel(issue.Severity),
+				Message:   sarifMessage{issue.Message},
+				Locations: []sarifLocation{location},
+			})
+
+			if issue.Rule != "" && !ruleAdded[issue.Rule] {
+				ruleAdded[issue.Rule] = true
+				sRule := sarifRule{ID: issue.Rule}
+				if rule, ok := GetRule(issue.Rule); ok {
+					sRule.ShortDescription = &sarifMessage{getRuleDescription(rule)}
+					sRule.HelpURI = rule.DocLink()
+					sRule.DefaultConfiguration = &sarifRuleDefaults{getSarifLevel(rule.DefaultSeverity())}
+				}
+				driver.Rules = append(driver.Rules, sRule)
+			}
+		}
+	}
+	run.Tool = sarifTool{driver}
+
+	bytes, err := json.MarshalIndent(sarifLog{sarifSchema, sarifVersion, []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(bytes))
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes a test suite for each file, with a test case for each issue.
+// The errors and warnings are failures; the infos are reported in the output of passed test cases.
+// A file without issues has a single passed test case.
+func writeJUnitReport(w io.Writer, result ValidationResult) error {
+	suites := junitTestSuites{Name: toolName + " validate"}
+	files, issues := result.getReportIssues()
+	for _, file := range files {
+		suite := junitTestSuite{Name: file}
+		for _, issue := range issues[file] {
+			name := issue.Rule
+			if name == "" {
+				name = schemaIssueName
+			}
+			location := fmt.Sprintf("%s:%d:%d", file, issue.Line, issue.Column)
+			testCase := junitTestCase{Name: fmt.Sprintf("%s (%s)", name, location), ClassName: file}
+			if issue.Severity == SeverityInfo {
+				testCase.SystemOut = fmt.Sprintf("%s: %s", location, issue.Message)
+			} else {
+				testCase.Failure = &junitFailure{issue.Message, issue.Severity, fmt.Sprintf("%s: %s: %s", location, issue.Severity, issue.Message)}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: file, ClassName: file})
+		}
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return writeXML(w, suites)
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func writeCheckstyleReport(w io.Writer, result ValidationResult) error {
+	report := checkstyleReport{Version: checkstyleVersion}
+	files, issues := result.getReportIssues()
+	for _, file := range files {
+		checkstyleFile := checkstyleFile{Name: file}
+		for _, issue := range issues[file] {
+			name := issue.Rule
+			if name == "" {
+				name = schemaIssueName
+			}
+			checkstyleFile.Errors = append(checkstyleFile.Errors, checkstyleError{
+				issue.Line, issue.Column, issue.Severity, issue.Message, toolName + "." + name,
+			})
+		}
+		report.Files = append(report.Files, checkstyleFile)
+	}
+	return writeXML(w, report)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	bytes, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, bytes)
+	return err
+}
@@ -0,0 +1,140 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Report", func() {
+	result := ValidationResult{
+		"mta.yaml": {
+			{SeverityWarning, `the "~{url" expression is not terminated`, 12, 11, expressionsValidation, nil, nil},
+			{SeverityError, `the "ui5app" path of the "ui5app" module does not exist`, 10, 10, pathsValidation, nil, nil},
+			{SeverityInfo, "the deprecated option is used", 10, 4, deprecatedOptsValidation, nil,
+				&IssueRange{IssuePosition{10, 4, 120}, IssuePosition{10, 12, 128}}},
+		},
+		"dev.mtaext": {
+			{SeverityError, "field abc not found in type mta.Module", 3, 0, "", nil, nil},
+			{SeverityError, "could not merge the extension", 0, 0, "", nil, nil},
+		},
+		"other.mtaext": {},
+	}
+
+	var _ = DescribeTable("CountIssues", func(severity string, expectedCount int) {
+		count, err := result.CountIssues(severity)
+		Ω(err).Should(Succeed())
+		Ω(count).Should(Equal(expectedCount))
+	},
+		Entry("errors", SeverityError, 3),
+		Entry("warnings and errors", SeverityWarning, 4),
+		Entry("all the issues", SeverityInfo, 5),
+	)
+
+	It("fails to count the issues of an unknown severity", func() {
+		_, err := result.CountIssues("fatal")
+		Ω(err).Should(MatchError(fmt.Sprintf(wrongSeverityMsg, "fatal")))
+	})
+
+	It("fails on an unknown report format", func() {
+		err := WriteReport(&bytes.Buffer{}, result, "html")
+		Ω(err).Should(MatchError(fmt.Sprintf(wrongReportFormatMsg, "html")))
+	})
+
+	It("writes a text report", func() {
+		var buf bytes.Buffer
+		Ω(WriteReport(&buf, result, ReportFormatText)).Should(Succeed())
+		Ω(buf.String()).Should(Equal(`dev.mtaext:0:0: error: could not merge the extension
+dev.mtaext:3:0: error: field abc not found in type mta.Module
+mta.yaml:10:4: info: the deprecated option is used [deprecatedOpts]
+mta.yaml:10:10: error: the "ui5app" path of the "ui5app" module does not exist [paths]
+mta.yaml:12:11: warning: the "~{url" expression is not terminated [expressions]
+3 errors, 1 warnings, 1 infos
+`))
+	})
+
+	It("writes a SARIF report", func() {
+		var buf bytes.Buffer
+		Ω(WriteReport(&buf, result, ReportFormatSarif)).Should(Succeed())
+		var log sarifLog
+		Ω(json.Unmarshal(buf.Bytes(), &log)).Should(Succeed())
+		Ω(log.Version).Should(Equal(sarifVersion))
+		Ω(log.Runs).Should(HaveLen(1))
+		run := log.Runs[0]
+		Ω(run.Tool.Driver.Name).Should(Equal(toolName))
+		Ω(run.Tool.Driver.Rules).Should(Equal([]sarifRule{
+			{deprecatedOptsValidation, &sarifMessage{"The deprecated builder options are not used"}, customBuilderDocLink, &sarifRuleDefaults{SeverityError}},
+			{pathsValidation, &sarifMessage{"The module paths exist in the project folder"}, "", &sarifRuleDefaults{SeverityError}},
+			{expressionsValidation, &sarifMessage{"The variables and placeholders in the values are well-formed"}, "", &sarifRuleDefaults{SeverityWarning}},
+		}))
+		Ω(run.Results).Should(HaveLen(5))
+		Ω(run.Results[0]).Should(Equal(sarifResult{
+			Level:     SeverityError,
+			Message:   sarifMessage{"could not merge the extension"},
+			Locations: []sarifLocation{{sarifPhysicalLocation{sarifArtifactLocation{"dev.mtaext"}, nil}}},
+		}))
+		Ω(run.Results[2]).Should(Equal(sarifResult{
+			RuleID:    deprecatedOptsValidation,
+			Level:     "note",
+			Message:   sarifMessage{"the deprecated option is used"},
+			Locations: []sarifLocation{{sarifPhysicalLocation{sarifArtifactLocation{"mta.yaml"}, &sarifRegion{10, 4, 10, 12}}}},
+		}))
+	})
+
+	It("writes a file URI for an absolute path in a SARIF report", func() {
+		path := getTestPath("mta.yaml")
+		var buf bytes.Buffer
+		Ω(WriteReport(&buf, ValidationResult{path: {{SeverityError, "error", 1, 1, "", nil, nil}}}, ReportFormatSarif)).Should(Succeed())
+		Ω(buf.String()).Should(ContainSubstring(`"uri": "file://`))
+	})
+
+	It("writes a JUnit report", func() {
+		var buf bytes.Buffer
+		Ω(WriteReport(&buf, result, ReportFormatJUnit)).Should(Succeed())
+		Ω(buf.String()).Should(HavePrefix(xml.Header))
+		var suites junitTestSuites
+		Ω(xml.Unmarshal(buf.Bytes(), &suites)).Should(Succeed())
+		Ω(suites.Tests).Should(Equal(6))
+		Ω(suites.Failures).Should(Equal(4))
+		Ω(suites.Suites).Should(HaveLen(3))
+		Ω(suites.Suites[0].Name).Should(Equal("dev.mtaext"))
+		Ω(suites.Suites[0].TestCases[1]).Should(Equal(junitTestCase{
+			Name:      "schema (dev.mtaext:3:0)",
+			ClassName: "dev.mtaext",
+			Failure:   &junitFailure{"field abc not found in type mta.Module", SeverityError, "dev.mtaext:3:0: error: field abc not found in type mta.Module"},
+		}))
+		Ω(suites.Suites[1].TestCases[0]).Should(Equal(junitTestCase{
+			Name:      "deprecatedOpts (mta.yaml:10:4)",
+			ClassName: "mta.yaml",
+			SystemOut: "mta.yaml:10:4: the deprecated option is used",
+		}))
+		Ω(suites.Suites[2]).Should(Equal(junitTestSuite{
+			Name:      "other.mtaext",
+			Tests:     1,
+			TestCases: []junitTestCase{{Name: "other.mtaext", ClassName: "other.mtaext"}},
+		}))
+	})
+
+	It("writes a checkstyle report", func() {
+		var buf bytes.Buffer
+		Ω(WriteReport(&buf, result, ReportFormatCheckstyle)).Should(Succeed())
+		Ω(buf.String()).Should(Equal(xml.Header + `<checkstyle version="4.3">
+  <file name="dev.mtaext">
+    <error line="0" severity="error" message="could not merge the extension" source="mta.schema"></error>
+    <error line="3" severity="error" message="field abc not found in type mta.Module" source="mta.schema"></error>
+  </file>
+  <file name="mta.yaml">
+    <error line="10" column="4" severity="info" message="the deprecated option is used" source="mta.deprecatedOpts"></error>
+    <error line="10" column="10" severity="error" message="the &#34;ui5app&#34; path of the &#34;ui5app&#34; module does not exist" source="mta.paths"></error>
+    <error line="12" column="11" severity="warning" message="the &#34;~{url&#34; expression is not terminated" source="mta.expressions"></error>
+  </file>
+  <file name="other.mtaext"></file>
+</checkstyle>
+`))
+	})
+})
@@ -0,0 +1,330 @@
+package validate
+
+import (
+	"gopkg.in/yaml.v3"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	emptyRuleIDMsg       = `the rule ID is empty`
+	ruleAlreadyExistsMsg = `the "%s" rule is already registered`
+	wrongRuleSeverityMsg = `the "%s" severity of the "%s" rule is incorrect; expected one of the following: error, warning`
+	ruleWithoutCheckMsg  = `the "%s" rule does not validate MTA descriptors, MTA extension descriptors or merged MTAs`
+	ruleIDSeparator      = ","
+	optInRuleNote        = "the rule runs only if it is enabled in the lint configuration"
+)
+
+// Rule is a semantic validation rule. A rule validates MTA descriptors if it implements the MtaRule interface,
+// MTA extension descriptors if it implements the ExtRule interface and the MTA after the MTA extension descriptors
+// are merged into it if it implements the MergedRule interface; it can implement several of them.
+type Rule interface {
+	// ID returns the stable identifier of the rule. The issues reported by the rule carry it,
+	// and it is used to exclude the rule from the validation.
+	ID() string
+	// DefaultSeverity returns the severity of the issues reported by the rule in strict mode: SeverityError or SeverityWarning
+	DefaultSeverity() string
+	// Description returns a short description of what the rule checks
+	Description() string
+	// DocLink returns a link to the documentation of the rule, or an empty string if there is no documentation
+	DocLink() string
+}
+
+// MtaRule is a rule which validates MTA descriptors
+type MtaRule interface {
+	Rule
+	// Check validates the MTA descriptor. The root is the content node of the descriptor and the source is the project folder.
+	// The MTA is the effective MTA, so the modules and resources can have values which they inherit from their declared
+	// types and which are not in the descriptor nodes of the modules and resources.
+	Check(mta *mta.MTA, root *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue)
+}
+
+// ExtRule is a rule which validates MTA extension descriptors
+type ExtRule interface {
+	Rule
+	// CheckExt validates the MTA extension descriptor. The root is the content node of the descriptor and the source is the project folder.
+	CheckExt(mtaExt *mta.EXT, root *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue)
+}
+
+// MergedRule is a rule which validates the MTA after the MTA extension descriptors are merged into it
+type MergedRule interface {
+	Rule
+	// CheckMerged validates the effective merged MTA. The descriptors are the MTA descriptor and the MTA extension descriptors
+	// in the order in which they are merged. The issues are returned by the paths of the descriptors in which they are found.
+	CheckMerged(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (errors map[string][]YamlValidationIssue, warnings map[string][]YamlValidationIssue)
+}
+
+// MergedDescriptor is a descriptor which is merged into the MTA: the MTA descriptor or an MTA extension descriptor
+type MergedDescriptor struct {
+	// Path - the path of the descriptor file
+	Path string
+	// Root - the content node of the descriptor
+	Root *yaml.Node
+}
+
+type ruleInfo struct {
+	id          string
+	severity    string
+	description string
+	docLink     string
+}
+
+func (r *ruleInfo) ID() string {
+	return r.id
+}
+
+func (r *ruleInfo) DefaultSeverity() string {
+	return r.severity
+}
+
+func (r *ruleInfo) Description() string {
+	return r.description
+}
+
+func (r *ruleInfo) DocLink() string {
+	return r.docLink
+}
+
+// semanticRule is a built-in rule which validates MTA descriptors
+type semanticRule struct {
+	ruleInfo
+	check checkSemantic
+}
+
+func (r *semanticRule) Check(mta *mta.MTA, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return r.check(mta, root, source, strict)
+}
+
+// semanticAndExtRule is a built-in rule which validates both MTA descriptors and MTA extension descriptors
+type semanticAndExtRule struct {
+	semanticRule
+	checkExt checkExtSemantic
+}
+
+func (r *semanticAndExtRule) CheckExt(mtaExt *mta.EXT, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return r.checkExt(mtaExt, root, source, strict)
+}
+
+// semanticAndMergedRule is a built-in rule which validates both MTA descriptors and merged MTAs
+type semanticAndMergedRule struct {
+	semanticRule
+	checkMerged checkMergedSemantic
+}
+
+func (r *semanticAndMergedRule) CheckMerged(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (map[string][]YamlValidationIssue, map[string][]YamlValidationIssue) {
+	return r.checkMerged(mta, descriptors, strict)
+}
+
+// mergedRule is a built-in rule which validates only merged MTAs
+type mergedRule struct {
+	ruleInfo
+	checkMerged checkMergedSemantic
+}
+
+func (r *mergedRule) CheckMerged(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (map[string][]YamlValidationIssue, map[string][]YamlValidationIssue) {
+	return r.checkMerged(mta, descriptors, strict)
+}
+
+func newSemanticRule(id, severity, description, docLink string, check checkSemantic) *semanticRule {
+	return &semanticRule{ruleInfo{id, severity, description, docLink}, check}
+}
+
+func newSemanticAndExtRule(id, severity, description, docLink string, check checkSemantic, checkExt checkExtSemantic) *semanticAndExtRule {
+	return &semanticAndExtRule{*newSemanticRule(id, severity, description, docLink, check), checkExt}
+}
+
+func newSemanticAndMergedRule(id, severity, description, docLink string, check checkSemantic, checkMerged checkMergedSemantic) *semanticAndMergedRule {
+	return &semanticAndMergedRule{*newSemanticRule(id, severity, description, docLink, check), checkMerged}
+}
+
+func newMergedRule(id, severity, description, docLink string, checkMerged checkMergedSemantic) *mergedRule {
+	return &mergedRule{ruleInfo{id, severity, description, docLink}, checkMerged}
+}
+
+var rulesMutex sync.RWMutex
+
+// configurableRule is a built-in rule whose checks depend on the lint configuration of the validation
+type configurableRule interface {
+	// withLintConfig returns the rule which checks the descriptors with the configuration
+	withLintConfig(config *LintConfig) Rule
+}
+
+// configureRule returns the rule with the lint configuration of the validation, if its checks depend on it
+func configureRule(rule Rule, config *LintConfig) Rule {
+	if configurable, ok := rule.(configurableRule); ok {
+		return configurable.withLintConfig(config)
+	}
+	return rule
+}
+
+// optInRules are the built-in rules which run only if they are enabled in the lint configuration
+var optInRules = append(append(append([]string{}, sandboxRules...), unusedRules...), secretsRules...)
+
+// getRuleDescription returns the description of the rule, with a note if it is an opt-in rule
+func getRuleDescription(rule Rule) string {
+	for _, id := range optInRules {
+		if id == rule.ID() {
+			return rule.Description() + "; " + optInRuleNote
+		}
+	}
+	return rule.Description()
+}
+
+// withOptInRulesOff returns the default rules configuration in which the opt-in rules are disabled
+func withOptInRulesOff(defaults RulesConfig) RulesConfig {
+	for _, id := range optInRules {
+		defaults[id] = &RuleConfig{Severity: SeverityOff}
+	}
+	return defaults
+}
+
+// rules are the registered rules, in the order in which they run
+var rules = []Rule{
+	newSemanticRule(pathsValidation, SeverityError,
+		"The module paths exist in the project folder", "", ifModulePathExists),
+	newSemanticRule(emptyPathValidation, SeverityError,
+		"The module paths are not empty folders", "", ifModulePathEmpty),
+	newSemanticAndExtRule(namesValidation, SeverityError,
+		"The names of the modules, resources and provided property sets are unique, and are extended only once in an MTA extension",
+		"", isNameUnique, checkSingleExtendNames),
+	newSemanticAndMergedRule(requiredValidation, SeverityError,
+		"The variables reference property sets and properties which are provided in the MTA, and the required resources are active "+
+			"unless they are optional, also after the extensions are merged", "", ifRequiredDefined, checkMergedInactiveRequires),
+	newSemanticRule(buildersValidation, SeverityError,
+		"The commands of the custom builders are defined only for the custom builder", customBuilderDocLink, checkBuildersSemantic),
+	newSemanticAndExtRule(deprecatedOptsValidation, SeverityError,
+		"The deprecated builder options are not used", customBuilderDocLink, checkDeprecatedOpts, checkExtDeprecatedOpts),
+	newSemanticRule(deployerConstrValidation, SeverityError,
+		"The modules contain the build configurations which the deployer requires", missingConfigDocLink, checkDeployerConstraints),
+	newSemanticAndMergedRule(metadataValidation, SeverityError,
+		"The properties and parameters metadata describe defined values, and the required values are not empty, also after the extensions are merged", "",
+		checkParamsAndPropertiesMetadata, checkMergedRequiredFields),
+	newSemanticRule(ifNoSourceParamBoolValidation, SeverityError,
+		`The "no-source" build parameter is a boolean`, "", ifNoSourceParamBool),
+	newSemanticAndExtRule(expressionsValidation, SeverityWarning,
+		"The variables and placeholders in the values are well-formed", "", checkExpressions, checkExtExpressions),
+	newSemanticAndMergedRule(datatypesValidation, SeverityError,
+		"The property values match the datatypes declared in the properties metadata, also after the extensions are merged", "",
+		checkDatatypes, checkMergedDatatypes),
+	newSemanticRule(typesValidation, SeverityError,
+		"The parameters of the modules and resources match the types catalog, also for the types declared in the MTA", "", checkTypes),
+	newSemanticRule(securityDescriptorValidation, SeverityError,
+		"The security descriptors of the xsuaa resources are well-structured, their scopes and role templates are unique, "+
+			"the role templates reference defined scopes and the config parameter doesn't override their xsappname", "", checkSecurityDescriptors),
+	newSemanticRule(fileReferencesValidation, SeverityError,
+		"The files in the path parameters of the resources and in the includes exist "+
+			"and are valid JSON or YAML files which contain a map", "", checkFileReferences),
+	newSemanticRule(modulePathSandboxValidation, SeverityError,
+		"The paths of the modules are relative paths in the project folder, also after their symbolic links are resolved", "", checkModulePathSandbox),
+	newSemanticRule(includePathSandboxValidation, SeverityError,
+		"The paths of the includes are relative paths in the project folder, also after their symbolic links are resolved", "", checkIncludePathSandbox),
+	newSemanticRule(resourcePathSandboxValidation, SeverityError,
+		"The path parameters of the resources are relative paths in the project folder, also after their symbolic links are resolved", "", checkResourcePathSandbox),
+	newSemanticRule(unusedResourcesValidation, SeverityWarning,
+		"The active resources are required by modules, resources or hooks", "", checkUnusedResources),
+	newSemanticRule(unconsumedProvidesValidation, SeverityWarning,
+		"The property sets provided by the modules are required by modules, resources or hooks, unless they are public", "", checkUnconsumedProvides),
+	newSemanticRule(unreferencedPropertiesValidation, SeverityWarning,
+		"The properties of the consumed provided property sets are referenced by variables", "", checkUnreferencedProperties),
+	newMergedRule(unreadOverridesValidation, SeverityWarning,
+		"The MTA extension descriptors don't set values which nothing reads in the merged MTA: the properties of unconsumed "+
+			"property sets and unused resources, the unreferenced provided properties and the values of inactive resources", "", checkUnreadOverrides),
+	newSecretsAndExtRule(secretKeysValidation, SeverityWarning,
+		"The parameters and properties whose names are the names of secrets, e.g. passwords and tokens, don't have literal values", "",
+		func(allowlist *secretsAllowlist) (checkSemantic, checkExtSemantic) {
+			return allowlist.checkSecretKeys, allowlist.checkExtSecretKeys
+		}),
+	newSecretsAndExtRule(highEntropyValuesValidation, SeverityWarning,
+		"The parameters and properties don't have literal values which are random strings, e.g. generated keys and tokens", "",
+		func(allowlist *secretsAllowlist) (checkSemantic, checkExtSemantic) {
+			return allowlist.checkHighEntropyValues, allowlist.checkExtHighEntropyValues
+		}),
+	newSecretsAndMergedRule(sensitiveLiteralsValidation, SeverityError,
+		"The parameters and properties which are sensitive in their metadata don't have literal values, also in the extensions", "",
+		func(allowlist *secretsAllowlist) (checkSemantic, checkMergedSemantic) {
+			return allowlist.checkSensitiveLiterals, allowlist.checkMergedSensitiveLiterals
+		}),
+}
+
+// RegisterRule registers a custom rule. The rule runs after the rules registered before it,
+// in all the validations of MTA descriptors, MTA extension descriptors or merged MTAs which don't exclude it.
+func RegisterRule(rule Rule) error {
+	if rule.ID() == "" {
+		return errors.New(emptyRuleIDMsg)
+	}
+	if rule.DefaultSeverity() != SeverityError && rule.DefaultSeverity() != SeverityWarning {
+		return errors.Errorf(wrongRuleSeverityMsg, rule.DefaultSeverity(), rule.ID())
+	}
+	_, isMtaRule := rule.(MtaRule)
+	_, isExtRule := rule.(ExtRule)
+	_, isMergedRule := rule.(MergedRule)
+	if !isMtaRule && !isExtRule && !isMergedRule {
+		return errors.Errorf(ruleWithoutCheckMsg, rule.ID())
+	}
+
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+	for _, existing := range rules {
+		if existing.ID() == rule.ID() {
+			return errors.Errorf(ruleAlreadyExistsMsg, rule.ID())
+		}
+	}
+	rules = append(rules, rule)
+	return nil
+}
+
+// UnregisterRule removes the rule with the ID from the registered rules. It returns false if there is no such rule.
+func UnregisterRule(id string) bool {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+	for i, rule := range rules {
+		if rule.ID() == id {
+			rules = append(rules[:i:i], rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetRules returns the registered rules, including the built-in rules, in the order in which they run
+func GetRules() []Rule {
+	rulesMutex.RLock()
+	defer rulesMutex.RUnlock()
+	result := make([]Rule, len(rules))
+	copy(result, rules)
+	return result
+}
+
+// GetRule returns the registered rule with the ID
+func GetRule(id string) (Rule, bool) {
+	for _, rule := range GetRules() {
+		if rule.ID() == id {
+			return rule, true
+		}
+	}
+	return nil, false
+}
+
+// parseExcludedRules returns the set of rule IDs in the comma-separated list of excluded rules
+func parseExcludedRules(exclude string) map[string]bool {
+	excluded := make(map[string]bool)
+	for _, id := range strings.Split(exclude, ruleIDSeparator) {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			excluded[id] = true
+		}
+	}
+	return excluded
+}
+
+// setIssuesRule sets the rule ID on the issues reported by the rule
+func setIssuesRule(issues []YamlValidationIssue, ruleID string) []YamlValidationIssue {
+	for i := range issues {
+		issues[i].Rule = ruleID
+	}
+	return issues
+}
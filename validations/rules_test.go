@@ -0,0 +1,266 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const customRuleID = "customModuleType"
+
+// customRule reports a warning on each module of the "custom" type
+type customRule struct {
+	id       string
+	severity string
+}
+
+func (r *customRule) ID() string {
+	return r.id
+}
+
+func (r *customRule) DefaultSeverity() string {
+	return r.severity
+}
+
+func (r *customRule) Description() string {
+	return `The modules are not of the "custom" type`
+}
+
+func (r *customRule) DocLink() string {
+	return ""
+}
+
+func (r *customRule) Check(mta *mta.MTA, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var issues []YamlValidationIssue
+	modulesNode := getPropContent(root, modulesYamlField)
+	for i, module := range mta.Modules {
+		if module.Type == "custom" {
+			typeNode := getPropValueByName(modulesNode[i], "type")
+			issues = appendIssue(issues, fmt.Sprintf(`the "%s" module is of the "custom" type`, module.Name), typeNode.Line, typeNode.Column)
+		}
+	}
+	return nil, issues
+}
+
+// customExtRule reports a warning on each MTA extension which extends the "custom" MTA
+type customExtRule struct {
+	customRule
+}
+
+func (r *customExtRule) CheckExt(mtaExt *mta.EXT, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	if mtaExt.Extends == "custom" {
+		return nil, []YamlValidationIssue{{Msg: `the "custom" MTA is extended`, Line: 1, Column: 1}}
+	}
+	return nil, nil
+}
+
+// customMergedRule reports a warning on the "custom" type of each module in the descriptor which sets it last
+type customMergedRule struct {
+	customRule
+}
+
+func (r *customMergedRule) CheckMerged(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (map[string][]YamlValidationIssue, map[string][]YamlValidationIssue) {
+	warnings := make(map[string][]YamlValidationIssue)
+	for _, module := range mta.Modules {
+		if module.Properties["type"] != "custom" {
+			continue
+		}
+		index, keyNode, _ := getSupplyingDescriptor(descriptors, []entityStep{{modulesYamlField, module.Name}}, propertiesYamlField, "type")
+		path := descriptors[index].Path
+		warnings[path] = append(warnings[path], YamlValidationIssue{Msg: `the "type" property is "custom"`, Line: keyNode.Line, Column: keyNode.Column})
+	}
+	return nil, warnings
+}
+
+// unconfigurableRule is a configurable rule which returns a rule without checks with the lint configuration
+type unconfigurableRule struct {
+	customMergedRule
+}
+
+func (r *unconfigurableRule) CheckExt(mtaExt *mta.EXT, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return (&customExtRule{r.customRule}).CheckExt(mtaExt, root, source, strict)
+}
+
+func (r *unconfigurableRule) withLintConfig(config *LintConfig) Rule {
+	return &ruleInfo{r.id, r.severity, r.Description(), r.DocLink()}
+}
+
+var _ = Describe("Rules", func() {
+	AfterEach(func() {
+		UnregisterRule(customRuleID)
+	})
+
+	It("returns the built-in rules", func() {
+		var ids []string
+		for _, rule := range GetRules() {
+			ids = append(ids, rule.ID())
+			Ω(rule.Description()).ShouldNot(BeEmpty())
+			Ω(rule.Description()).ShouldNot(ContainSubstring(optInRuleNote))
+			Ω(rule.DefaultSeverity()).Should(Or(Equal(SeverityError), Equal(SeverityWarning)))
+		}
+		Ω(ids).Should(Equal([]string{pathsValidation, emptyPathValidation, namesValidation, requiredValidation,
+			buildersValidation, deprecatedOptsValidation, deployerConstrValidation, metadataValidation,
+			ifNoSourceParamBoolValidation, expressionsValidation, datatypesValidation, typesValidation, securityDescriptorValidation, fileReferencesValidation,
+			modulePathSandboxValidation, includePathSandboxValidation, resourcePathSandboxValidation, unusedResourcesValidation,
+			unconsumedProvidesValidation, unreferencedPropertiesValidation, unreadOverridesValidation, secretKeysValidation,
+			highEntropyValuesValidation, sensitiveLiteralsValidation}))
+	})
+
+	It("returns a built-in rule by its ID", func() {
+		rule, ok := GetRule(deployerConstrValidation)
+		Ω(ok).Should(BeTrue())
+		Ω(rule.DocLink()).Should(Equal(missingConfigDocLink))
+		_, isMtaRule := rule.(MtaRule)
+		Ω(isMtaRule).Should(BeTrue())
+		_, isExtRule := rule.(ExtRule)
+		Ω(isExtRule).Should(BeFalse())
+	})
+
+	It("returns the description of a rule with a note if it is an opt-in rule", func() {
+		rule, _ := GetRule(modulePathSandboxValidation)
+		Ω(getRuleDescription(rule)).Should(Equal(rule.Description() + "; " + optInRuleNote))
+		rule, _ = GetRule(namesValidation)
+		Ω(getRuleDescription(rule)).Should(Equal(rule.Description()))
+	})
+
+	It("doesn't return an unknown rule", func() {
+		_, ok := GetRule("unknown")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("runs a registered custom rule on the MTA and sets its ID on the issues", func() {
+		Ω(RegisterRule(&customRule{customRuleID, SeverityWarning})).Should(Succeed())
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '3.1'
+version: 0.0.1
+
+modules:
+ - name: m1
+   type: custom
+`)
+		mtaStr, err := mta.Unmarshal(mtaContent)
+		Ω(err).Should(Succeed())
+		root, _ := getContentNode(mtaContent)
+		errors, warnings := runSemanticValidations(mtaStr, root, getTestPath("testproject"), "paths,emptyPath", true, nil)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(ConsistOf(YamlValidationIssue{Msg: `the "m1" module is of the "custom" type`, Line: 8, Column: 10, Rule: customRuleID}))
+
+		_, warnings = runSemanticValidations(mtaStr, root, getTestPath("testproject"), "paths,emptyPath,"+customRuleID, true, nil)
+		Ω(warnings).Should(BeEmpty())
+	})
+
+	It("runs a registered custom rule on the MTA extension", func() {
+		Ω(RegisterRule(&customExtRule{customRule{customRuleID, SeverityWarning}})).Should(Succeed())
+		extContent := []byte(`
+ID: mtahtml5ext
+_schema-version: '3.1'
+extends: custom
+`)
+		mtaExt, err := mta.UnmarshalExt(extContent)
+		Ω(err).Should(Succeed())
+		root, _ := getContentNode(extContent)
+		_, warnings := runExtSemanticValidations(mtaExt, root, getTestPath("testproject"), "", true, nil)
+		Ω(warnings).Should(ConsistOf(YamlValidationIssue{Msg: `the "custom" MTA is extended`, Line: 1, Column: 1, Rule: customRuleID}))
+	})
+
+	It("returns the ID of a custom rule in the validation result", func() {
+		Ω(RegisterRule(&customRule{customRuleID, SeverityWarning})).Should(Succeed())
+		mtaYamlPath := getTestPath("validateProject", "custom_mta.yaml")
+		result := Validate(mtaYamlPath, nil)
+		Ω(result).Should(MatchAllKeys(Keys{
+			mtaYamlPath: ConsistOf(MatchAllFields(Fields{
+				"Severity": Equal(SeverityWarning),
+				"Message":  Equal(`the "ui5app" module is of the "custom" type`),
+				"Line":     Equal(7),
+				"Column":   Equal(10),
+				"Rule":     Equal(customRuleID),
+				"Fixes":    BeEmpty(),
+				"Range":    Not(BeNil()),
+			})),
+		}))
+	})
+
+	It("runs a registered custom rule on the merged MTA and returns its issues on the supplying descriptor", func() {
+		Ω(RegisterRule(&customMergedRule{customRule{customRuleID, SeverityWarning}})).Should(Succeed())
+		mtaYamlPath := getTestPath("validateProject", "valid_mta.yaml")
+		mtaExtPath := getTestPath("validateProject", "custom.mtaext")
+		result := Validate(mtaYamlPath, []string{mtaExtPath})
+		Ω(result).Should(MatchAllKeys(Keys{
+			mtaYamlPath: BeEmpty(),
+			mtaExtPath: ConsistOf(MatchAllFields(Fields{
+				"Severity": Equal(SeverityWarning),
+				"Message":  Equal(`the "type" property is "custom"`),
+				"Line":     Equal(9),
+				"Column":   Equal(7),
+				"Rule":     Equal(customRuleID),
+				"Fixes":    BeEmpty(),
+				"Range":    Not(BeNil()),
+			})),
+		}))
+	})
+
+	It("runs a configurable rule as it is registered when it is configured without checks", func() {
+		Ω(RegisterRule(&unconfigurableRule{customMergedRule{customRule{customRuleID, SeverityWarning}}})).Should(Succeed())
+		mtaYamlPath := getTestPath("validateProject", "valid_mta.yaml")
+		mtaExtPath := getTestPath("validateProject", "custom.mtaext")
+		result := Validate(mtaYamlPath, []string{mtaExtPath})
+		Ω(result[mtaExtPath]).Should(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Message": Equal(`the "type" property is "custom"`),
+			"Rule":    Equal(customRuleID),
+		})))
+
+		extContent := []byte("ID: mtahtml5ext\n_schema-version: '3.1'\nextends: custom\n")
+		mtaExt, err := mta.UnmarshalExt(extContent)
+		Ω(err).Should(Succeed())
+		root, _ := getContentNode(extContent)
+		_, warnings := runExtSemanticValidations(mtaExt, root, getTestPath("testproject"), "", true, &LintConfig{})
+		Ω(warnings).Should(ConsistOf(YamlValidationIssue{Msg: `the "custom" MTA is extended`, Line: 1, Column: 1, Rule: customRuleID}))
+
+		mtaContent := []byte("ID: mtahtml5\n_schema-version: '3.1'\nversion: 0.0.1\nmodules:\n - name: m1\n   type: custom\n")
+		mtaStr, err := mta.Unmarshal(mtaContent)
+		Ω(err).Should(Succeed())
+		root, _ = getContentNode(mtaContent)
+		_, warnings = runSemanticValidations(mtaStr, root, getTestPath("testproject"), "paths,emptyPath", true, &LintConfig{})
+		Ω(warnings).Should(ConsistOf(YamlValidationIssue{Msg: `the "m1" module is of the "custom" type`, Line: 6, Column: 10, Rule: customRuleID}))
+	})
+
+	It("fails to register a rule with an existing ID", func() {
+		err := RegisterRule(&customRule{namesValidation, SeverityWarning})
+		Ω(err).Should(MatchError(fmt.Sprintf(ruleAlreadyExistsMsg, namesValidation)))
+	})
+
+	It("fails to register a rule without an ID", func() {
+		Ω(RegisterRule(&customRule{"", SeverityWarning})).Should(MatchError(emptyRuleIDMsg))
+	})
+
+	It("fails to register a rule with an unknown severity", func() {
+		err := RegisterRule(&customRule{customRuleID, "fatal"})
+		Ω(err).Should(MatchError(fmt.Sprintf(wrongRuleSeverityMsg, "fatal", customRuleID)))
+	})
+
+	It("fails to register a rule which doesn't validate descriptors", func() {
+		err := RegisterRule(&ruleInfo{customRuleID, SeverityWarning, "description", ""})
+		Ω(err).Should(MatchError(fmt.Sprintf(ruleWithoutCheckMsg, customRuleID)))
+	})
+
+	It("doesn't unregister an unknown rule", func() {
+		Ω(UnregisterRule("unknown")).Should(BeFalse())
+	})
+
+	It("excludes only the rules with the exact IDs", func() {
+		var ids []string
+		for _, rule := range getSemanticValidations(" paths , requiredX,names") {
+			ids = append(ids, rule.ID())
+		}
+		Ω(ids).ShouldNot(ContainElement(pathsValidation))
+		Ω(ids).ShouldNot(ContainElement(namesValidation))
+		Ω(ids).Should(ContainElement(requiredValidation))
+		Ω(ids).Should(ContainElement(emptyPathValidation))
+	})
+})
@@ -23,6 +23,14 @@ type YamlValidationIssue struct {
 	Line int
 	// Column - column number of the issue
 	Column int
+	// Rule - the ID of the semantic validation rule which reported the issue; empty for other issues
+	Rule string
+	// Fixes - the quick fixes of the issue
+	Fixes []QuickFix
+	// Range - the range of the text in which the issue is found; nil if the issue is not on a specific line
+	Range *IssueRange
+	// node - the node on which the issue is found, if any; the range of the issue is computed from it
+	node *yaml.Node
 }
 
 // YamlValidationIssues - list of issue's
@@ -44,6 +52,14 @@ func (issues YamlValidationIssues) Sort() {
 	})
 }
 
+// appendNodeIssue appends an issue on the node, in the position of the node
+func appendNodeIssue(issues []YamlValidationIssue, issue string, node *yaml.Node) []YamlValidationIssue {
+	if issue == "" {
+		return issues
+	}
+	return append(issues, YamlValidationIssue{Msg: issue, Line: node.Line, Column: node.Column, node: node})
+}
+
 // YamlCheck - validation check function type
 type YamlCheck func(yNode, yParentNode *yaml.Node, path []string) YamlValidationIssues
 
@@ -250,6 +266,7 @@ func required() YamlCheck {
 						last(path), buildPathString(dropRight(path))),
 					Line:   yParentNode.Line,
 					Column: yParentNode.Column,
+					node:   yParentNode,
 				},
 			}
 		}
@@ -272,6 +289,7 @@ func doesNotExist() YamlCheck {
 						last(path), buildPathString(dropRight(path))),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					node:   yNode,
 				},
 			}
 		}
@@ -313,6 +331,7 @@ func typeIsNotMapArray() YamlCheck {
 					Msg:    fmt.Sprintf(`the "%s" property must be a string`, buildPathString(path)),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					node:   yNode,
 				},
 			}
 		}
@@ -331,6 +350,7 @@ func typeIsArray() YamlCheck {
 						Msg:    fmt.Sprintf(`the "%s" property must be an array`, buildPathString(path)),
 						Line:   yNode.Line,
 						Column: yNode.Column,
+						node:   yNode,
 					},
 				}
 			}
@@ -349,6 +369,7 @@ func typeIsMap() YamlCheck {
 						Msg:    fmt.Sprintf(`the "%s" property must be a map`, buildPathString(path)),
 						Line:   yNode.Line,
 						Column: yNode.Column,
+						node:   yNode,
 					},
 				}
 			}
@@ -367,6 +388,7 @@ func typeIsBoolean() YamlCheck {
 					{Msg: fmt.Sprintf(`the "%s" property must be a boolean`, buildPathString(path)),
 						Line:   yNode.Line,
 						Column: yNode.Column,
+						node:   yNode,
 					},
 				}
 			}
@@ -393,6 +415,7 @@ func matchesRegExp(pattern string) YamlCheck {
 						strValue, buildPathString(path), pattern),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					node:   yNode,
 				},
 			}
 		}
@@ -437,6 +460,7 @@ func matchesEnumValues(enumValues []string) YamlCheck {
 						value, buildPathString(path), expectedSubset),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					node:   yNode,
 				},
 			}
 		}
@@ -218,7 +218,7 @@ func buildTypeValidation(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue
 
 func buildEnumValidation(enumNode *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue) {
 	if !enumNode.IsArray() {
-		return []YamlCheck{}, []YamlValidationIssue{{"invalid .yaml file schema: enums values must be listed as an array", 0, 0}}
+		return []YamlCheck{}, []YamlValidationIssue{{Msg: "invalid .yaml file schema: enums values must be listed as an array"}}
 	}
 
 	enumsNumber, _ := enumNode.GetArraySize()
@@ -227,7 +227,7 @@ func buildEnumValidation(enumNode *simpleyaml.Yaml) ([]YamlCheck, []YamlValidati
 	for i := 0; i < enumsNumber; i++ {
 		enumValueNode := enumNode.GetIndex(i)
 		if enumValueNode.IsArray() || enumValueNode.IsMap() {
-			return []YamlCheck{}, []YamlValidationIssue{{"invalid .yaml file schema: enum values must be simple", 0, 0}}
+			return []YamlCheck{}, []YamlValidationIssue{{Msg: "invalid .yaml file schema: enum values must be simple"}}
 		}
 		enumValue := getLiteralStringValue(enumValueNode)
 		enumValues = append(enumValues, enumValue)
@@ -5,10 +5,13 @@ import (
 	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/SAP/cloud-mta/mta"
 )
 
+const noSourceToBoolFixTitle = `Change the "no-source" build parameter to the %s boolean value`
+
 // ifNoSourceParamBool - validates that "no-source" build parameter is boolean if defined
 func ifNoSourceParamBool(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
 	var issues []YamlValidationIssue
@@ -60,11 +63,11 @@ func ifModulePathEmpty(mta *mta.MTA, mtaNode *yaml.Node, source string, strict b
 			moduleNode := modulesNode.Content[index]
 			pathNode := getPropValueByName(moduleNode, pathYamlField)
 			if pathNode == nil {
-				issues = appendIssue(issues, fmt.Sprintf(`the path of the "%s" module is not defined`,
-					module.Name), moduleNode.Line, moduleNode.Column)
+				issues = appendNodeIssue(issues, fmt.Sprintf(`the path of the "%s" module is not defined`,
+					module.Name), moduleNode)
 			} else {
-				issues = appendIssue(issues, fmt.Sprintf(`the path of the "%s" module is empty`,
-					module.Name), pathNode.Line, pathNode.Column)
+				issues = appendNodeIssue(issues, fmt.Sprintf(`the path of the "%s" module is empty`,
+					module.Name), pathNode)
 			}
 		}
 	}
@@ -81,11 +84,21 @@ func ifNoSource(module *mta.Module, modulesNode *yaml.Node, index int) (bool, *Y
 		if ok {
 			return noSource, nil
 		}
-		return false, &YamlValidationIssue{
+		issue := &YamlValidationIssue{
 			Msg:    `the "no-source" build parameter must be a boolean`,
 			Line:   noSourceNode.Line,
 			Column: noSourceNode.Column,
+			node:   noSourceNode,
+		}
+		// A quoted boolean value can be unquoted
+		if value, isString := module.BuildParams[noSourceYamlField].(string); isString {
+			if value = strings.ToLower(value); value == "true" || value == "false" {
+				if edit, ok := getReplaceQuotedScalarEdit(noSourceNode, value); ok {
+					issue.Fixes = newQuickFix(fmt.Sprintf(noSourceToBoolFixTitle, value), true, edit)
+				}
+			}
 		}
+		return false, issue
 	}
 	return false, nil
 }
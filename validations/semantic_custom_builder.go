@@ -10,6 +10,8 @@ import (
 
 const (
 	customBuilder = "custom"
+
+	addCommandsFixTitle = `Add an empty "commands" property`
 )
 
 func checkProjectBuilders(builders []mta.ProjectBuilder, mtaNode *yaml.Node, fieldName string) []YamlValidationIssue {
@@ -25,16 +27,22 @@ func checkProjectBuilders(builders []mta.ProjectBuilder, mtaNode *yaml.Node, fie
 		builder := builderStr.Builder
 		commandsDefined := builderStr.Commands != nil
 		commandsNode := getPropValueByName(buildersNodes[i], commandsYamlField)
-		issues = append(issues, checkCustomBuilder(builder, commandsDefined, buildersNodes[i], commandsNode)...)
+		issues = append(issues, checkCustomBuilder(mtaNode, builder, commandsDefined, buildersNodes[i], commandsNode, buildersNodes[i])...)
 	}
 	return issues
 }
 
-func checkCustomBuilder(builder string, commandsDefined bool, builderNode *yaml.Node, commandsNode *yaml.Node) []YamlValidationIssue {
+// checkCustomBuilder checks the commands of the builder. The builder map node is the node in which the builder is defined.
+// The commands of the custom builder depend on the build of the module, so the fix which adds empty commands is not preferred.
+func checkCustomBuilder(mtaNode *yaml.Node, builder string, commandsDefined bool, builderNode *yaml.Node, commandsNode *yaml.Node, builderMapNode *yaml.Node) []YamlValidationIssue {
 	if builder == customBuilder && !commandsDefined {
-		return []YamlValidationIssue{{Msg: `the "commands" property is missing in the "custom" builder`, Line: builderNode.Line, Column: builderNode.Column}}
+		issue := YamlValidationIssue{Msg: `the "commands" property is missing in the "custom" builder`, Line: builderNode.Line, Column: builderNode.Column, node: builderNode}
+		if edit, ok := getAddEntryEdit(mtaNode, builderMapNode, commandsYamlField+": []"); ok {
+			issue.Fixes = newQuickFix(addCommandsFixTitle, false, edit)
+		}
+		return []YamlValidationIssue{issue}
 	} else if builder != customBuilder && commandsDefined {
-		return []YamlValidationIssue{{Msg: fmt.Sprintf(`the "commands" property is not supported by the "%s" builder`, builder), Line: commandsNode.Line, Column: commandsNode.Column}}
+		return []YamlValidationIssue{{Msg: fmt.Sprintf(`the "commands" property is not supported by the "%s" builder`, builder), Line: commandsNode.Line, Column: commandsNode.Column, node: commandsNode}}
 	}
 	return nil
 }
@@ -60,7 +68,7 @@ func checkBuildersSemantic(mta *mta.MTA, mtaNode *yaml.Node, source string, stri
 			builderNode := getPropValueByName(buildParamsNode, builderYamlField)
 			commandsDefined := module.BuildParams[commandsYamlField] != nil
 			commandsNode := getPropValueByName(buildParamsNode, commandsYamlField)
-			issues = append(issues, checkCustomBuilder(builder, commandsDefined, builderNode, commandsNode)...)
+			issues = append(issues, checkCustomBuilder(mtaNode, builder, commandsDefined, builderNode, commandsNode, buildParamsNode)...)
 		}
 	}
 
@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+
+	"github.com/SAP/cloud-mta/internal/expression"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	datatypeMismatchMsg       = `the value of the "%s" property does not match its "%s" datatype`
+	mergedDatatypeMismatchMsg = `the value of the "%s" property does not match its "%s" datatype, which is declared in the "%s" file`
+
+	strDatatype        = "str"
+	intDatatype        = "int"
+	floatDatatype      = "float"
+	boolDatatype       = "bool"
+	structuredDatatype = "structured"
+	arrayDatatype      = "array"
+)
+
+func checkDatatypes(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	issues := validateMetadata(mta, mtaNode, source, checkPropertiesDatatypes)
+
+	if strict {
+		return issues, nil
+	}
+	return nil, issues
+}
+
+// checkPropertiesDatatypes checks the values of the properties match the datatypes in their metadata
+func checkPropertiesDatatypes(m map[string]interface{}, metadata map[string]mta.MetaData, parentNode *yaml.Node, mapType int) []YamlValidationIssue {
+	if mapType != mapTypeProperties {
+		return nil
+	}
+
+	var issues []YamlValidationIssue
+	mapNode := getPropValueByName(parentNode, propertiesYamlField)
+	for key, value := range m {
+		datatype, ok := getMismatchedDatatype(value, metadata[key])
+		if !ok {
+			continue
+		}
+		if valueNode := getPropValueByName(mapNode, key); valueNode != nil {
+			issues = append(issues, YamlValidationIssue{Msg: fmt.Sprintf(datatypeMismatchMsg, key, datatype), Line: valueNode.Line, Column: valueNode.Column, node: valueNode})
+		}
+	}
+	return issues
+}
+
+// checkMergedDatatypes checks the values of the properties in the merged MTA match the datatypes in their metadata.
+// The issues are reported on the MTA extension descriptors which supply the values; the values in the MTA descriptor
+// are checked in its validation.
+func checkMergedDatatypes(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (errors map[string][]YamlValidationIssue, warnings map[string][]YamlValidationIssue) {
+	issues := make(map[string][]YamlValidationIssue)
+	for _, m := range getMergedMaps(mta) {
+		if m.mapType != mapTypeProperties {
+			continue
+		}
+		for key, value := range m.values {
+			datatype, ok := getMismatchedDatatype(value, m.metadata[key])
+			if !ok {
+				continue
+			}
+			index, _, valueNode := getSupplyingDescriptor(descriptors, m.path, propertiesYamlField, key)
+			if index <= 0 || valueNode == nil {
+				continue
+			}
+			path := descriptors[index].Path
+			issues[path] = append(issues[path], YamlValidationIssue{
+				Msg:  fmt.Sprintf(mergedDatatypeMismatchMsg, key, datatype, filepath.Base(descriptors[0].Path)),
+				Line: valueNode.Line, Column: valueNode.Column, node: valueNode,
+			})
+		}
+	}
+
+	if strict {
+		return issues, nil
+	}
+	return nil, issues
+}
+
+// getMismatchedDatatype returns the datatype in the metadata, and true if the value doesn't match it.
+// Empty values and values which are a variable or a placeholder are not checked, because their datatype
+// is known only when they are resolved; unknown datatypes are reported by the schema validation.
+func getMismatchedDatatype(value interface{}, metadata mta.MetaData) (string, bool) {
+	datatype, ok := metadata.Datatype.(string)
+	if !ok || value == nil {
+		return "", false
+	}
+	if str, ok := value.(string); ok {
+		parsed := expression.Parse(str)
+		if _, ok := parsed.WholeExpression(expression.Variable); ok {
+			return "", false
+		}
+		if _, ok := parsed.WholeExpression(expression.Placeholder); ok {
+			return "", false
+		}
+	}
+
+	matches := true
+	switch datatype {
+	case strDatatype:
+		_, matches = value.(string)
+	case intDatatype:
+		matches = isIntValue(value)
+	case floatDatatype:
+		_, isFloat := value.(float64)
+		matches = isFloat || isIntValue(value)
+	case boolDatatype:
+		_, matches = value.(bool)
+	case structuredDatatype:
+		switch value.(type) {
+		case map[string]interface{}, map[interface{}]interface{}:
+		default:
+			matches = false
+		}
+	case arrayDatatype:
+		_, matches = value.([]interface{})
+	}
+	return datatype, !matches
+}
+
+func isIntValue(value interface{}) bool {
+	switch value.(type) {
+	case int, int64, uint64:
+		return true
+	}
+	return false
+}
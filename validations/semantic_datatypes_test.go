@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("datatypes semantic validations", func() {
+	var _ = DescribeTable("getMismatchedDatatype", func(value interface{}, datatype interface{}, expectedMismatch bool) {
+		_, mismatch := getMismatchedDatatype(value, mta.MetaData{Datatype: datatype})
+		Ω(mismatch).Should(Equal(expectedMismatch))
+	},
+		Entry("string", "abc", strDatatype, false),
+		Entry("number which is not a string", 1, strDatatype, true),
+		Entry("int", 1, intDatatype, false),
+		Entry("large int", uint64(1<<63), intDatatype, false),
+		Entry("float which is not an int", 1.5, intDatatype, true),
+		Entry("string which is not an int", "1", intDatatype, true),
+		Entry("float", 1.5, floatDatatype, false),
+		Entry("int which is a float", 1, floatDatatype, false),
+		Entry("bool", true, boolDatatype, false),
+		Entry("string which is not a bool", "true", boolDatatype, true),
+		Entry("structured", map[string]interface{}{"a": 1}, structuredDatatype, false),
+		Entry("array which is not structured", []interface{}{1}, structuredDatatype, true),
+		Entry("array", []interface{}{1}, arrayDatatype, false),
+		Entry("string which is not an array", "a", arrayDatatype, true),
+		Entry("empty value", nil, intDatatype, false),
+		Entry("whole placeholder", "${default-port}", intDatatype, false),
+		Entry("whole variable", "~{srv/port}", boolDatatype, false),
+		Entry("string with a placeholder", "port ${default-port}", intDatatype, true),
+		Entry("no datatype", 1, nil, false),
+		Entry("unknown datatype", 1, "number", false),
+	)
+
+	var _ = Describe("checkDatatypes", func() {
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: ui5app
+   type: html5
+   properties:
+     port: abc
+     ratio: 1
+   properties-metadata:
+     port:
+       datatype: int
+     ratio:
+       datatype: float
+   requires:
+     - name: r1
+       properties:
+         enabled: "true"
+       properties-metadata:
+         enabled:
+           datatype: bool
+
+resources:
+ - name: r1
+   properties:
+     config: a
+   properties-metadata:
+     config:
+       datatype: structured
+`)
+
+		It("returns errors on the values which don't match their datatypes in strict mode", func() {
+			mtaStr, err := mta.Unmarshal(mtaContent)
+			Ω(err).Should(Succeed())
+			root, _ := getContentNode(mtaContent)
+			errors, warnings := checkDatatypes(mtaStr, root, "", true)
+			Ω(warnings).Should(BeEmpty())
+			Ω(withoutNodes(errors)).Should(ConsistOf(
+				YamlValidationIssue{Msg: fmt.Sprintf(datatypeMismatchMsg, "port", intDatatype), Line: 10, Column: 12},
+				YamlValidationIssue{Msg: fmt.Sprintf(datatypeMismatchMsg, "enabled", boolDatatype), Line: 20, Column: 19},
+				YamlValidationIssue{Msg: fmt.Sprintf(datatypeMismatchMsg, "config", structuredDatatype), Line: 28, Column: 14},
+			))
+		})
+
+		It("returns warnings in non-strict mode", func() {
+			mtaStr, _ := mta.Unmarshal(mtaContent)
+			root, _ := getContentNode(mtaContent)
+			errors, warnings := checkDatatypes(mtaStr, root, "", false)
+			Ω(errors).Should(BeEmpty())
+			Ω(warnings).Should(HaveLen(3))
+		})
+	})
+
+	It("reports the values supplied by the MTA extensions in the extension files", func() {
+		mtaYamlPath := getTestPath("datatypesProject", "mta.yaml")
+		devExtPath := getTestPath("datatypesProject", "dev.mtaext")
+		prodExtPath := getTestPath("datatypesProject", "prod.mtaext")
+		issue := func(message string, line, column int) types.GomegaMatcher {
+			return MatchAllFields(Fields{
+				"Severity": Equal(SeverityError),
+				"Message":  Equal(message),
+				"Line":     Equal(line),
+				"Column":   Equal(column),
+				"Rule":     Equal(datatypesValidation),
+				"Fixes":    BeEmpty(),
+				"Range":    Not(BeNil()),
+			})
+		}
+		// The extensions are merged in the order of the extends chain
+		result := Validate(mtaYamlPath, []string{prodExtPath, devExtPath})
+		Ω(result).Should(MatchAllKeys(Keys{
+			mtaYamlPath: BeEmpty(),
+			devExtPath: ConsistOf(
+				issue(fmt.Sprintf(mergedDatatypeMismatchMsg, "port", intDatatype, "mta.yaml"), 8, 13),
+				issue(fmt.Sprintf(mergedDatatypeMismatchMsg, "hosts", arrayDatatype, "mta.yaml"), 14, 18),
+			),
+			prodExtPath: ConsistOf(
+				issue(fmt.Sprintf(mergedDatatypeMismatchMsg, "debug", boolDatatype, "mta.yaml"), 8, 14),
+				issue(fmt.Sprintf(mergedDatatypeMismatchMsg, "schema", strDatatype, "mta.yaml"), 15, 15),
+			),
+		}))
+	})
+})
@@ -11,6 +11,10 @@ const (
 	missingConfigDocLink = "https://sap.github.io/cloud-mta-build-tool/migration/#features-that-are-handled-differently-in-the-cloud-mta-build-tool"
 	missingConfigsMsg    = `the "%s" module does not contain the mandatory "supported-platforms" and "build-result" build configurations; see %q`
 	missingConfigMsg     = `the "%s" module does not contain the mandatory "%s" build configuration; see %q`
+
+	addSupportedPlatformsFixTitle = `Add the "supported-platforms" build parameter with an empty list`
+	addBuildResultFixTitle        = `Add the "build-result" build parameter with the "dist" folder`
+	defaultBuildResult            = "dist"
 )
 
 func checkDeployerConstraints(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
@@ -34,14 +38,14 @@ func checkHTML5ModulesParams(mta *mta.MTA, mtaNode *yaml.Node) []YamlValidationI
 
 	for i, module := range mta.Modules {
 		if module.Type == html5ModuleType {
-			issues = append(issues, checkHTML5ModuleParams(module, modulesNode[i])...)
+			issues = append(issues, checkHTML5ModuleParams(mtaNode, module, modulesNode[i])...)
 		}
 	}
 
 	return issues
 }
 
-func checkHTML5ModuleParams(module *mta.Module, moduleNode *yaml.Node) []YamlValidationIssue {
+func checkHTML5ModuleParams(mtaNode *yaml.Node, module *mta.Module, moduleNode *yaml.Node) []YamlValidationIssue {
 
 	supportedPlatformsDefined := false
 	buildResultDefined := false
@@ -50,30 +54,55 @@ func checkHTML5ModuleParams(module *mta.Module, moduleNode *yaml.Node) []YamlVal
 		buildResultDefined = module.BuildParams[buildResultYamlField] != nil
 	}
 
+	var issue YamlValidationIssue
 	if !supportedPlatformsDefined && !buildResultDefined {
-		return []YamlValidationIssue{
-			{
-				Msg:    fmt.Sprintf(missingConfigsMsg, module.Name, missingConfigDocLink),
-				Line:   moduleNode.Line,
-				Column: moduleNode.Column,
-			},
+		issue = YamlValidationIssue{
+			Msg:    fmt.Sprintf(missingConfigsMsg, module.Name, missingConfigDocLink),
+			Line:   moduleNode.Line,
+			Column: moduleNode.Column,
+			node:   moduleNode,
 		}
 	} else if !supportedPlatformsDefined {
-		return []YamlValidationIssue{
-			{
-				Msg:    fmt.Sprintf(missingConfigMsg, module.Name, supportedPlatformsYamlField, missingConfigDocLink),
-				Line:   moduleNode.Line,
-				Column: moduleNode.Column,
-			},
+		issue = YamlValidationIssue{
+			Msg:    fmt.Sprintf(missingConfigMsg, module.Name, supportedPlatformsYamlField, missingConfigDocLink),
+			Line:   moduleNode.Line,
+			Column: moduleNode.Column,
+			node:   moduleNode,
 		}
 	} else if !buildResultDefined {
-		return []YamlValidationIssue{
-			{
-				Msg:    fmt.Sprintf(missingConfigMsg, module.Name, buildResultYamlField, missingConfigDocLink),
-				Line:   moduleNode.Line,
-				Column: moduleNode.Column,
-			},
+		issue = YamlValidationIssue{
+			Msg:    fmt.Sprintf(missingConfigMsg, module.Name, buildResultYamlField, missingConfigDocLink),
+			Line:   moduleNode.Line,
+			Column: moduleNode.Column,
+			node:   moduleNode,
 		}
+	} else {
+		return nil
+	}
+
+	// The modules which are deployed to the HTML5 repository are not deployed to any platform.
+	// The build result is usually in the "dist" folder, but it depends on the build of the module, so the fix is not preferred.
+	if !supportedPlatformsDefined {
+		issue.Fixes = append(issue.Fixes, getAddBuildParamFix(mtaNode, moduleNode, addSupportedPlatformsFixTitle, true, supportedPlatformsYamlField+": []")...)
+	}
+	if !buildResultDefined {
+		issue.Fixes = append(issue.Fixes, getAddBuildParamFix(mtaNode, moduleNode, addBuildResultFixTitle, false, buildResultYamlField+": "+defaultBuildResult)...)
+	}
+	return []YamlValidationIssue{issue}
+}
+
+// getAddBuildParamFix returns a fix which adds the build parameter to the module, and adds the build parameters if they are not defined
+func getAddBuildParamFix(mtaNode *yaml.Node, moduleNode *yaml.Node, title string, preferred bool, param string) []QuickFix {
+	buildParamsNode := getPropValueByName(moduleNode, buildParametersYamlField)
+	var edit TextEdit
+	var ok bool
+	if buildParamsNode == nil {
+		edit, ok = getAddEntryEdit(mtaNode, moduleNode, buildParametersYamlField+":", "  "+param)
+	} else {
+		edit, ok = getAddEntryEdit(mtaNode, buildParamsNode, param)
+	}
+	if !ok {
+		return nil
 	}
-	return nil
+	return newQuickFix(title, preferred, edit)
 }
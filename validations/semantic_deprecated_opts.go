@@ -10,13 +10,20 @@ import (
 const (
 	deprecatedOptMsg     = `the "%s" build configuration parameter is not supported by Cloud MTA Build tool; use the "custom" builder instead; see %q`
 	customBuilderDocLink = "https://sap.github.io/cloud-mta-build-tool/configuration/#configuring-the-custom-builder"
+
+	removeDeprecatedOptFixTitle = `Remove the "%s" build configuration parameter`
 )
 
-func checkDeprecatedOpt(buildParams map[string]interface{}, buildParamsNode *yaml.Node, optFieldName string) []YamlValidationIssue {
+func checkDeprecatedOpt(mtaNode *yaml.Node, buildParams map[string]interface{}, buildParamsNode *yaml.Node, optFieldName string) []YamlValidationIssue {
 
 	if buildParams[optFieldName] != nil {
 		optsNode := getPropByName(buildParamsNode, optFieldName)
-		return []YamlValidationIssue{{Msg: fmt.Sprintf(deprecatedOptMsg, optFieldName, customBuilderDocLink), Line: optsNode.Line, Column: optsNode.Column}}
+		issue := YamlValidationIssue{Msg: fmt.Sprintf(deprecatedOptMsg, optFieldName, customBuilderDocLink), Line: optsNode.Line, Column: optsNode.Column, node: optsNode}
+		// The parameter is ignored by the Cloud MTA Build tool, so it is safe to remove it
+		if edit, ok := getRemoveEntryEdit(mtaNode, buildParamsNode, optFieldName); ok {
+			issue.Fixes = newQuickFix(fmt.Sprintf(removeDeprecatedOptFixTitle, optFieldName), true, edit)
+		}
+		return []YamlValidationIssue{issue}
 	}
 	return nil
 }
@@ -33,7 +40,7 @@ func checkDeprecatedOpts(mta *mta.MTA, mtaNode *yaml.Node, source string, strict
 		if buildParams != nil {
 			buildParamsNode := getPropValueByName(modulesNode[i], buildParametersYamlField)
 			for _, opt := range opts {
-				issues = append(issues, checkDeprecatedOpt(buildParams, buildParamsNode, opt)...)
+				issues = append(issues, checkDeprecatedOpt(mtaNode, buildParams, buildParamsNode, opt)...)
 			}
 		}
 	}
@@ -53,7 +60,7 @@ func checkExtDeprecatedOpts(mta *mta.EXT, mtaNode *yaml.Node, source string, str
 		if buildParams != nil {
 			buildParamsNode := getPropValueByName(modulesNode[i], buildParametersYamlField)
 			for _, opt := range opts {
-				issues = append(issues, checkDeprecatedOpt(buildParams, buildParamsNode, opt)...)
+				issues = append(issues, checkDeprecatedOpt(mtaNode, buildParams, buildParamsNode, opt)...)
 			}
 		}
 	}
@@ -0,0 +1,67 @@
+package validate
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/expression"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const strTag = "!!str"
+
+// checkExpressions - validates the syntax of the variables and placeholders in the string values of the MTA
+func checkExpressions(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, checkExpressionsInNode(mtaNode)
+}
+
+// checkExtExpressions - validates the syntax of the variables and placeholders in the string values of the MTA extension
+func checkExtExpressions(mtaExt *mta.EXT, extNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, checkExpressionsInNode(extNode)
+}
+
+func checkExpressionsInNode(node *yaml.Node) []YamlValidationIssue {
+	var issues []YamlValidationIssue
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == strTag {
+			for _, err := range expression.Parse(node.Value).Errors {
+				line, column := getExpressionPosition(node, err.Offset)
+				issues = appendIssue(issues, err.Message, line, column)
+			}
+		}
+	case yaml.MappingNode:
+		// only the values are checked
+		for i := 1; i < len(node.Content); i += 2 {
+			issues = append(issues, checkExpressionsInNode(node.Content[i])...)
+		}
+	default:
+		for _, child := range node.Content {
+			issues = append(issues, checkExpressionsInNode(child)...)
+		}
+	}
+	return issues
+}
+
+// getExpressionPosition returns the line and column of the byte offset in the value of the scalar node.
+// The position is exact for single-line values; for block scalars, only the line is exact and the column
+// is the column of the block indicator, since the indentation of the block content is not kept in the node.
+func getExpressionPosition(node *yaml.Node, offset int) (int, int) {
+	line, column := expression.LineColumn(node.Value, offset)
+	switch node.Style {
+	case yaml.LiteralStyle, yaml.FoldedStyle:
+		return node.Line + 1 + line, node.Column
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		if line == 0 {
+			// skip the quote
+			return node.Line, node.Column + 1 + column
+		}
+	default:
+		if line == 0 {
+			return node.Line, node.Column + column
+		}
+	}
+	return node.Line + line, node.Column
+}
@@ -0,0 +1,62 @@
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("SemanticExpressions", func() {
+	It("reports malformed expressions with their positions", func() {
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '2.1'
+version: 0.0.1
+
+modules:
+ - name: ui5app
+   type: html5
+   parameters:
+     ${unterminated: value
+     plain: abc ${unterminated
+     quoted: "abc ~{a${b}}"
+     valid: '~{a/b} ${c} \${escaped} ~{{d}}'
+     list:
+       - x ${}
+     block: |
+       first line
+       second ${line
+`)
+		mtaObj, _ := mta.Unmarshal(mtaContent)
+		node, _ := getContentNode(mtaContent)
+		errors, warnings := checkExpressions(mtaObj, node, "", true)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(Equal([]YamlValidationIssue{
+			{Msg: `the "${unterminated" expression is not terminated; add "}" at the end of the expression`, Line: 11, Column: 17},
+			{Msg: `the "${b}" expression is nested in another expression; nested expressions are not supported`, Line: 12, Column: 22},
+			{Msg: `the "${}" expression is empty`, Line: 15, Column: 12},
+			{Msg: `the "${line" expression is not terminated; add "}" at the end of the expression`, Line: 18, Column: 13},
+		}))
+	})
+
+	It("reports malformed expressions in the MTA extension", func() {
+		extContent := []byte(`
+ID: mtahtml5.ext
+_schema-version: '2.1'
+extends: mtahtml5
+
+modules:
+ - name: ui5app
+   parameters:
+     plain: abc ~{
+`)
+		extObj, _ := mta.UnmarshalExt(extContent)
+		node, _ := getContentNode(extContent)
+		errors, warnings := checkExtExpressions(extObj, node, "", true)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(Equal([]YamlValidationIssue{
+			{Msg: `the "~{" expression is not terminated; add "}" at the end of the expression`, Line: 9, Column: 17},
+		}))
+	})
+})
@@ -2,35 +2,40 @@ package validate
 
 import (
 	"gopkg.in/yaml.v3"
-	"strings"
 
 	"github.com/SAP/cloud-mta/mta"
 )
 
 type checkExtSemantic func(mta *mta.EXT, root *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue)
 
-// runSemanticValidations - runs semantic validations
-func runExtSemanticValidations(mtaExt *mta.EXT, root *yaml.Node, source string, exclude string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+// runExtSemanticValidations - runs semantic validations; the rules which are disabled in the lint configuration don't run
+func runExtSemanticValidations(mtaExt *mta.EXT, root *yaml.Node, source string, exclude string, strict bool,
+	config *LintConfig) ([]YamlValidationIssue, []YamlValidationIssue) {
 	var errors []YamlValidationIssue
 	var warnings []YamlValidationIssue
 
-	validations := getExtSemanticValidations(exclude)
-	for _, validation := range validations {
-		validationErrors, validationWarnings := validation(mtaExt, root, source, strict)
-		errors = append(errors, validationErrors...)
-		warnings = append(warnings, validationWarnings...)
+	validations := getExtSemanticValidations(config.getExcludedRules(exclude))
+	for _, rule := range validations {
+		validation, ok := configureRule(rule, config).(ExtRule)
+		if !ok {
+			validation = rule
+		}
+		validationErrors, validationWarnings := validation.CheckExt(mtaExt, root, source, strict)
+		errors = append(errors, setIssuesRule(validationErrors, validation.ID())...)
+		warnings = append(warnings, setIssuesRule(validationWarnings, validation.ID())...)
 	}
 	return errors, warnings
 }
 
-// getSemanticValidations - gets list of all semantic validations minus excludes validations
-func getExtSemanticValidations(exclude string) []checkExtSemantic {
-	var validations []checkExtSemantic
-	if !strings.Contains(exclude, namesValidation) {
-		validations = append(validations, checkSingleExtendNames)
-	}
-	if !strings.Contains(exclude, deprecatedOptsValidation) {
-		validations = append(validations, checkExtDeprecatedOpts)
+// getExtSemanticValidations - gets list of all registered MTA extension rules minus excluded rules;
+// exclude is a comma-separated list of rule IDs
+func getExtSemanticValidations(exclude string) []ExtRule {
+	var validations []ExtRule
+	excluded := parseExcludedRules(exclude)
+	for _, rule := range GetRules() {
+		if extRule, ok := rule.(ExtRule); ok && !excluded[rule.ID()] {
+			validations = append(validations, extRule)
+		}
 	}
 	return validations
 }
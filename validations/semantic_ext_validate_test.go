@@ -37,12 +37,12 @@ resources:
 		mtaExt, e := mta.UnmarshalExt(mtaContent)
 		Ω(e).Should(Succeed())
 		root, _ := getContentNode(mtaContent)
-		issues, _ := runExtSemanticValidations(mtaExt, root, getTestPath("testproject"), "", true)
+		issues, _ := runExtSemanticValidations(mtaExt, root, getTestPath("testproject"), "", true, nil)
 		Ω(issues).Should(ConsistOf(
-			YamlValidationIssue{fmt.Sprintf(nameAlreadyExtendedMsg, "ui5app", "module", "another", "module", 8), 14, 10},
-			YamlValidationIssue{fmt.Sprintf(nameAlreadyExtendedMsg, "test", "resource", "another", "resource", 17), 21, 10},
+			YamlValidationIssue{Msg: fmt.Sprintf(nameAlreadyExtendedMsg, "ui5app", "module", "another", "module", 8), Line: 14, Column: 10, Rule: namesValidation},
+			YamlValidationIssue{Msg: fmt.Sprintf(nameAlreadyExtendedMsg, "test", "resource", "another", "resource", 17), Line: 21, Column: 10, Rule: namesValidation},
 		))
-		issues, _ = runExtSemanticValidations(mtaExt, root, getTestPath("testproject"), "names", true)
+		issues, _ = runExtSemanticValidations(mtaExt, root, getTestPath("testproject"), "names", true, nil)
 		Ω(len(issues)).Should(Equal(0))
 	})
 
@@ -0,0 +1,191 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	fileReferenceNotFoundMsg = `the "%s" file in the %s of the %s does not exist`
+	fileReferenceFolderMsg   = `the "%s" path in the %s of the %s is a folder; expected a file`
+	fileReferenceSyntaxMsg   = `the "%s" file in the %s of the %s is not a valid %s file; line %d: %s`
+	fileReferenceNotMapMsg   = `the "%s" file in the %s of the %s does not contain a map of parameters`
+	referencedFileSyntaxMsg  = `the file is not a valid %s file: %s`
+
+	includesYamlField     = "includes"
+	pathParameterLocation = "path parameter"
+	includeLocation       = `"%s" include`
+	jsonFormat            = "JSON"
+	yamlFormat            = "YAML"
+)
+
+// fileReference is a file which is referenced by the MTA: the file in the path parameter of a resource,
+// which contains the service creation parameters, or the file in an include of a module or a resource
+type fileReference struct {
+	// owner - the description of the entity which references the file, e.g. "srv" module
+	owner string
+	// location - the description of the reference in the entity, e.g. path parameter
+	location string
+	path     string
+	format   string
+	// node - the node of the path in the MTA descriptor, or the node of the name of the entity if the path is not in it
+	node *yaml.Node
+}
+
+// referencedFile is the result of the validation of a referenced file
+type referencedFile struct {
+	filePath string
+	msg      string
+	content  []byte
+	// syntaxErr - the syntax error in the content of the file
+	syntaxErr *contentSyntaxError
+}
+
+// checkFileReferences checks the files in the path parameters of the resources and in the includes of the modules
+// and the resources: they must exist and be valid JSON or YAML files which contain a map. The syntax errors are reported
+// with their positions in the files. The paths which escape the project folder are reported by the opt-in sandbox rules.
+func checkFileReferences(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	for _, reference := range getFileReferences(mta, mtaNode) {
+		result := reference.validate(source)
+		if result.msg == "" || reference.node == nil {
+			continue
+		}
+		if strict {
+			errors = appendNodeIssue(errors, result.msg, reference.node)
+		} else {
+			warnings = appendNodeIssue(warnings, result.msg, reference.node)
+		}
+	}
+	return errors, warnings
+}
+
+// validateReferencedFiles returns the syntax errors in the files which are referenced by the MTA, by the paths of the files.
+// The lint configuration applies to the issues as in the validation of the descriptors.
+func validateReferencedFiles(mtaStr *mta.MTA, projectPath string, config *LintConfig) map[string]*fileIssues {
+	if parseExcludedRules(config.getExcludedRules(""))[fileReferencesValidation] {
+		return nil
+	}
+	result := make(map[string]*fileIssues)
+	for _, reference := range getFileReferences(mtaStr, nil) {
+		file := reference.validate(projectPath)
+		if file.syntaxErr == nil || result[file.filePath] != nil {
+			continue
+		}
+		issues := &fileIssues{errs: []YamlValidationIssue{{
+			Msg:    fmt.Sprintf(referencedFileSyntaxMsg, reference.format, file.syntaxErr.msg),
+			Line:   file.syntaxErr.line,
+			Column: file.syntaxErr.column,
+			Rule:   fileReferencesValidation,
+		}}}
+		issues.errs, issues.warns, issues.infos = config.applyLintConfig(getRelativePath(projectPath, file.filePath), nil, issues.errs, nil)
+		setIssueRanges(file.content, nil, &issues.errs, &issues.warns, &issues.infos)
+		result[file.filePath] = issues
+	}
+	return result
+}
+
+// getFileReferences returns the files referenced by the modules and the resources, with their nodes in the MTA descriptor.
+// The references don't have nodes if the MTA descriptor is not sent or doesn't contain their values.
+func getFileReferences(mta *mta.MTA, mtaNode *yaml.Node) []fileReference {
+	var references []fileReference
+	for _, module := range mta.Modules {
+		moduleNode := getEntityNode(mtaNode, []entityStep{{modulesYamlField, module.Name}})
+		references = append(references, getIncludeReferences(module.Includes, moduleNode, fmt.Sprintf(`"%s" %s`, module.Name, moduleEntityKind))...)
+	}
+	for _, resource := range mta.Resources {
+		resourceNode := getEntityNode(mtaNode, []entityStep{{resourcesYamlField, resource.Name}})
+		owner := fmt.Sprintf(`"%s" %s`, resource.Name, resourceEntityKind)
+		// The path parameter can be inherited from the type of the resource
+		if path, ok := resource.Parameters[configPathParameter].(string); ok && path != "" {
+			node := getPropValueByName(getPropValueByName(resourceNode, parametersYamlField), configPathParameter)
+			if node == nil {
+				node = getPropValueByName(resourceNode, nameYamlField)
+			}
+			references = append(references, fileReference{owner, pathParameterLocation, path, jsonFormat, node})
+		}
+		references = append(references, getIncludeReferences(resource.Includes, resourceNode, owner)...)
+	}
+	return references
+}
+
+// getIncludeReferences returns the files in the includes. The files with the ".json" extension are JSON files
+// and the other files are YAML files, the same as when the includes are expanded.
+func getIncludeReferences(includes []mta.Includes, entityNode *yaml.Node, owner string) []fileReference {
+	var references []fileReference
+	includesNode := getPropContent(entityNode, includesYamlField)
+	for i, include := range includes {
+		if include.Path == "" {
+			continue
+		}
+		format := yamlFormat
+		if strings.EqualFold(filepath.Ext(include.Path), ".json") {
+			format = jsonFormat
+		}
+		var node *yaml.Node
+		if i < len(includesNode) {
+			node = getPropValueByName(includesNode[i], pathYamlField)
+		}
+		references = append(references, fileReference{owner, fmt.Sprintf(includeLocation, include.Name), include.Path, format, node})
+	}
+	return references
+}
+
+// validate checks the referenced file; the path is relative to the project folder
+func (r *fileReference) validate(projectPath string) referencedFile {
+	filePath := r.path
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(projectPath, filePath)
+	}
+	result := referencedFile{filePath: filePath}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		result.msg = fmt.Sprintf(fileReferenceNotFoundMsg, r.path, r.location, r.owner)
+		return result
+	}
+	if info.IsDir() {
+		result.msg = fmt.Sprintf(fileReferenceFolderMsg, r.path, r.location, r.owner)
+		return result
+	}
+	content, err := fs.ReadFile(filePath)
+	if err != nil {
+		result.msg = fmt.Sprintf(fileReferenceNotFoundMsg, r.path, r.location, r.owner)
+		return result
+	}
+	result.content = content
+
+	root, syntaxErr := parseReferencedFile(content, r.format)
+	if syntaxErr != nil {
+		result.syntaxErr = syntaxErr
+		result.msg = fmt.Sprintf(fileReferenceSyntaxMsg, r.path, r.location, r.owner, r.format, syntaxErr.line, syntaxErr.msg)
+	} else if root.Kind != yaml.MappingNode {
+		result.msg = fmt.Sprintf(fileReferenceNotMapMsg, r.path, r.location, r.owner)
+	}
+	return result
+}
+
+// parseReferencedFile parses the content of a JSON or YAML file, and returns the syntax error with its position
+func parseReferencedFile(content []byte, format string) (*yaml.Node, *contentSyntaxError) {
+	if format == jsonFormat {
+		root, err := getJSONContentNode(content)
+		if err != nil {
+			if syntaxErr, ok := err.(*contentSyntaxError); ok {
+				return nil, syntaxErr
+			}
+			return nil, &contentSyntaxError{msg: err.Error()}
+		}
+		return root, nil
+	}
+	root, err := getContentNode(content)
+	if err != nil {
+		// The YAML errors have only a line
+		issue := convertError(err)[0]
+		return nil, &contentSyntaxError{line: issue.Line, msg: issue.Msg}
+	}
+	return root, nil
+}
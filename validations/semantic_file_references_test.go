@@ -0,0 +1,60 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("file references semantic validations", func() {
+	getReferencesIssues := func(strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		content, err := fs.ReadFile(getTestPath("fileReferencesProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		return checkFileReferences(mtaStr, root, getTestPath("fileReferencesProject"), strict)
+	}
+
+	It("checks the files in the includes and in the path parameters of the resources", func() {
+		errors, warnings := getReferencesIssues(true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(fileReferenceSyntaxMsg, "config/bad.yaml", `"bad" include`, `"srv" module`, yamlFormat, 2,
+				"did not find expected ',' or ']'"), Line: 12, Column: 13},
+			YamlValidationIssue{Msg: fmt.Sprintf(fileReferenceNotMapMsg, "config/list.yaml", `"list" include`, `"srv" module`), Line: 14, Column: 13},
+			YamlValidationIssue{Msg: fmt.Sprintf(fileReferenceSyntaxMsg, "config/invalid.json", pathParameterLocation, `"invalid" resource`, jsonFormat, 3,
+				`invalid character '"' after object key`), Line: 26, Column: 12},
+			YamlValidationIssue{Msg: fmt.Sprintf(fileReferenceNotFoundMsg, "config/missing.json", pathParameterLocation, `"missing" resource`), Line: 30, Column: 12},
+			YamlValidationIssue{Msg: fmt.Sprintf(fileReferenceFolderMsg, "config/folder", pathParameterLocation, `"folder" resource`), Line: 34, Column: 12},
+		))
+		Ω(warnings).Should(BeEmpty())
+	})
+
+	It("returns the issues as warnings in non-strict mode", func() {
+		errors, warnings := getReferencesIssues(false)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(HaveLen(5))
+	})
+
+	It("reports the syntax errors on the referenced files in the validation of the project", func() {
+		result := Validate(getTestPath("fileReferencesProject", "mta.yaml"), nil)
+		Ω(result).Should(HaveLen(3))
+		Ω(result[getTestPath("fileReferencesProject", "config", "invalid.json")]).Should(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Severity": Equal(SeverityError),
+			"Message":  Equal(fmt.Sprintf(referencedFileSyntaxMsg, jsonFormat, `invalid character '"' after object key`)),
+			"Line":     Equal(3),
+			"Column":   Equal(16),
+			"Rule":     Equal(fileReferencesValidation),
+		})))
+		Ω(result[getTestPath("fileReferencesProject", "config", "bad.yaml")]).Should(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Message": Equal(fmt.Sprintf(referencedFileSyntaxMsg, yamlFormat, "did not find expected ',' or ']'")),
+			"Line":    Equal(2),
+		})))
+	})
+})
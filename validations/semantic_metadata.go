@@ -3,6 +3,8 @@ package validate
 import (
 	"fmt"
 	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
 
 	"github.com/SAP/cloud-mta/mta"
 )
@@ -11,6 +13,9 @@ const (
 	unknownNameInMetadataMsg             = `metadata cannot be defined for the "%s" undefined %s`
 	emptyRequiredFieldMsg                = `the value for the required and non-overwritable "%s" %s cannot be empty`
 	propertiesMetadataWithListOrGroupMsg = `the "properties-metadata" cannot be used in the context of list and group`
+	emptyRequiredMergedFieldMsg          = `the value for the required "%s" %s is empty after the "%s" MTA extension descriptors are merged; set the value in one of them`
+
+	removeUnknownNameMetadataFixTitle = `Remove the metadata of the "%s" %s`
 
 	// Key for mapTypes map
 	mapTypeParameters = iota
@@ -38,7 +43,7 @@ type mapTypeProps struct {
 }
 
 func checkParamsAndPropertiesMetadata(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
-	issues := validateMetadata(mta, mtaNode, source, checkMetadata)
+	issues := validateMetadata(mta, mtaNode, source, getMetadataValidator(mtaNode))
 
 	if strict {
 		return issues, nil
@@ -46,31 +51,41 @@ func checkParamsAndPropertiesMetadata(mta *mta.MTA, mtaNode *yaml.Node, source s
 	return nil, issues
 }
 
-// Check:
+// getMetadataValidator returns a metadata validator which checks:
 // 1. Each property/parameter in the metadata is defined in the map
 // 2. Non-optional and non-overwritable property/parameter is not nil
 // 3. properties-metadata is not defined when list or group is defined
-func checkMetadata(m map[string]interface{}, metadata map[string]mta.MetaData, parentNode *yaml.Node, mapType int) []YamlValidationIssue {
-	var issues []YamlValidationIssue
+// The root is the content node of the MTA descriptor, in which the fixes are found.
+func getMetadataValidator(root *yaml.Node) metadataValidator {
+	return func(m map[string]interface{}, metadata map[string]mta.MetaData, parentNode *yaml.Node, mapType int) []YamlValidationIssue {
+		var issues []YamlValidationIssue
 
-	metadataNodeValue := getPropValueByName(parentNode, mapTypes[mapType].metadataNodeName)
-	issues = checkMetadataKeyIsDefinedInMap(metadata, m, metadataNodeValue, issues, mapType)
+		metadataNodeValue := getPropValueByName(parentNode, mapTypes[mapType].metadataNodeName)
+		issues = checkMetadataKeyIsDefinedInMap(root, metadata, m, metadataNodeValue, issues, mapType)
 
-	mapNode := getPropValueByName(parentNode, mapTypes[mapType].mapNodeName)
-	issues = checkNoEmptyRequiredFields(metadata, m, mapNode, issues, mapType)
+		mapNode := getPropValueByName(parentNode, mapTypes[mapType].mapNodeName)
+		issues = checkNoEmptyRequiredFields(metadata, m, mapNode, issues, mapType)
 
-	metadataNodeName := getPropByName(parentNode, mapTypes[mapType].metadataNodeName)
-	issues = checkPropertiesMetadataWithListOrGroup(mapType, metadataNodeName, parentNode, issues)
+		metadataNodeName := getPropByName(parentNode, mapTypes[mapType].metadataNodeName)
+		issues = checkPropertiesMetadataWithListOrGroup(mapType, metadataNodeName, parentNode, issues)
 
-	return issues
+		return issues
+	}
 }
 
-func checkMetadataKeyIsDefinedInMap(metadata map[string]mta.MetaData, m map[string]interface{}, metadataNodeValue *yaml.Node, issues []YamlValidationIssue, mapType int) []YamlValidationIssue {
+// checkMetadataKeyIsDefinedInMap checks that the keys in the metadata are defined in the map. The key in the map can have
+// a typo, so the fix which removes the metadata is not preferred.
+func checkMetadataKeyIsDefinedInMap(root *yaml.Node, metadata map[string]mta.MetaData, m map[string]interface{}, metadataNodeValue *yaml.Node, issues []YamlValidationIssue, mapType int) []YamlValidationIssue {
 	for key := range metadata {
 		_, ok := m[key]
-		if !ok {
-			keyNode := getPropByName(metadataNodeValue, key)
-			issues = append(issues, YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, key, mapTypes[mapType].entityKind), Line: keyNode.Line, Column: keyNode.Column})
+		// The metadata which is inherited from a declared type is not in the descriptor of the entity
+		keyNode := getPropByName(metadataNodeValue, key)
+		if !ok && keyNode != nil {
+			issue := YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, key, mapTypes[mapType].entityKind), Line: keyNode.Line, Column: keyNode.Column, node: keyNode}
+			if edit, ok := getRemoveEntryEdit(root, metadataNodeValue, key); ok {
+				issue.Fixes = newQuickFix(fmt.Sprintf(removeUnknownNameMetadataFixTitle, key, mapTypes[mapType].entityKind), false, edit)
+			}
+			issues = append(issues, issue)
 		}
 	}
 	return issues
@@ -81,9 +96,9 @@ func checkNoEmptyRequiredFields(metadata map[string]mta.MetaData, m map[string]i
 		for key, value := range m {
 			// If there's no metadata for the key we don't perform this check (since it's overwritable by default)
 			if meta, ok := metadata[key]; ok {
-				if !isPropertyOptional(meta.Optional) && !isPropertyOverWritable(meta.OverWritable) && value == nil {
-					keyNode := getPropByName(mapNode, key)
-					issues = append(issues, YamlValidationIssue{Msg: fmt.Sprintf(emptyRequiredFieldMsg, key, mapTypes[mapType].entityKind), Line: keyNode.Line, Column: keyNode.Column})
+				keyNode := getPropByName(mapNode, key)
+				if !isPropertyOptional(meta.Optional) && !isPropertyOverWritable(meta.OverWritable) && value == nil && keyNode != nil {
+					issues = append(issues, YamlValidationIssue{Msg: fmt.Sprintf(emptyRequiredFieldMsg, key, mapTypes[mapType].entityKind), Line: keyNode.Line, Column: keyNode.Column, node: keyNode})
 				}
 			}
 		}
@@ -91,6 +106,42 @@ func checkNoEmptyRequiredFields(metadata map[string]mta.MetaData, m map[string]i
 	return issues
 }
 
+// checkMergedRequiredFields checks the required overwritable parameters and properties which are empty in the MTA descriptor
+// have values after the MTA extension descriptors are merged. The issues are reported on the keys in the MTA descriptor.
+// The required non-overwritable parameters and properties which are empty are reported in the validation of the MTA descriptor.
+func checkMergedRequiredFields(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (errors map[string][]YamlValidationIssue, warnings map[string][]YamlValidationIssue) {
+	var extNames []string
+	for _, descriptor := range descriptors[1:] {
+		extNames = append(extNames, filepath.Base(descriptor.Path))
+	}
+
+	var issues []YamlValidationIssue
+	for _, m := range getMergedMaps(mta) {
+		for key, meta := range m.metadata {
+			value, ok := m.values[key]
+			if !ok || value != nil || isPropertyOptional(meta.Optional) || !isPropertyOverWritable(meta.OverWritable) {
+				continue
+			}
+			mapNode := getPropValueByName(getEntityNode(descriptors[0].Root, m.path), mapTypes[m.mapType].mapNodeName)
+			if keyNode := getPropByName(mapNode, key); keyNode != nil {
+				issues = append(issues, YamlValidationIssue{
+					Msg:  fmt.Sprintf(emptyRequiredMergedFieldMsg, key, mapTypes[m.mapType].entityKind, strings.Join(extNames, `", "`)),
+					Line: keyNode.Line, Column: keyNode.Column, node: keyNode,
+				})
+			}
+		}
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	result := map[string][]YamlValidationIssue{descriptors[0].Path: issues}
+	if strict {
+		return result, nil
+	}
+	return nil, result
+}
+
 func isPropertyOverWritable(value *bool) bool {
 	if value == nil {
 		return true
@@ -108,7 +159,7 @@ func isPropertyOptional(value *bool) bool {
 func checkPropertiesMetadataWithListOrGroup(mapType int, metadataNodeName *yaml.Node, parentNode *yaml.Node, issues []YamlValidationIssue) []YamlValidationIssue {
 	if mapType == mapTypeProperties && metadataNodeName != nil {
 		if getPropByName(parentNode, listYamlField) != nil || getPropByName(parentNode, groupYamlField) != nil {
-			issues = append(issues, YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: metadataNodeName.Line, Column: metadataNodeName.Column})
+			issues = append(issues, YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: metadataNodeName.Line, Column: metadataNodeName.Column, node: metadataNodeName})
 		}
 	}
 	return issues
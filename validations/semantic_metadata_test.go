@@ -5,6 +5,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
 
 	"github.com/SAP/cloud-mta/mta"
 )
@@ -129,29 +131,86 @@ resources:
 			node, _ := getContentNode(mtaContent)
 			errors, warn := checkParamsAndPropertiesMetadata(mta, node, "", true)
 			Ω(len(warn)).Should(Equal(0))
-			Ω(errors).Should(ConsistOf(
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), 11, 3},
-				YamlValidationIssue{fmt.Sprintf(emptyRequiredFieldMsg, "memory", "parameter"), 18, 6},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "x", "property"), 27, 6},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), 34, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), 41, 14},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), 51, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), 56, 10},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 50, 8},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), 65, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "m", "parameter"), 74, 6},
-				YamlValidationIssue{fmt.Sprintf(emptyRequiredFieldMsg, "b", "property"), 81, 6},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), 94, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), 99, 10},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 93, 8},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 107, 6},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 110, 6},
+			Ω(withoutNodes(errors)).Should(ConsistOf(
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), Line: 11, Column: 3, Fixes: getRemoveMetadataFix("b", "parameter", 11, 12)},
+				YamlValidationIssue{Msg: fmt.Sprintf(emptyRequiredFieldMsg, "memory", "parameter"), Line: 18, Column: 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "x", "property"), Line: 27, Column: 6, Fixes: getRemoveMetadataFix("x", "property", 27, 28)},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), Line: 34, Column: 10, Fixes: getRemoveMetadataFix("b", "parameter", 34, 35)},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), Line: 41, Column: 14, Fixes: getRemoveMetadataFix("a", "parameter", 41, 42)},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), Line: 51, Column: 10, Fixes: getRemoveMetadataFix("b", "property", 51, 52)},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), Line: 56, Column: 10, Fixes: getRemoveMetadataFix("a", "parameter", 56, 57)},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 50, Column: 8},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), Line: 65, Column: 10, Fixes: getRemoveMetadataFix("b", "property", 65, 66)},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "m", "parameter"), Line: 74, Column: 6, Fixes: getRemoveMetadataFix("m", "parameter", 74, 76)},
+				YamlValidationIssue{Msg: fmt.Sprintf(emptyRequiredFieldMsg, "b", "property"), Line: 81, Column: 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), Line: 94, Column: 10, Fixes: getRemoveMetadataFix("b", "property", 94, 95)},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), Line: 99, Column: 10, Fixes: getRemoveMetadataFix("a", "parameter", 99, 100)},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 93, Column: 8},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 107, Column: 6},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 110, Column: 6},
 			))
 		})
 
 	})
+
+	var _ = Describe("checkMergedRequiredFields", func() {
+		It("reports the required values which are empty after the merge on the MTA descriptor", func() {
+			mtaYamlPath := getTestPath("requiredProject", "mta.yaml")
+			devExtPath := getTestPath("requiredProject", "dev.mtaext")
+			prodExtPath := getTestPath("requiredProject", "prod.mtaext")
+			issue := func(message string, line, column int) types.GomegaMatcher {
+				return MatchAllFields(Fields{
+					"Severity": Equal(SeverityError),
+					"Message":  Equal(message),
+					"Line":     Equal(line),
+					"Column":   Equal(column),
+					"Rule":     Equal(metadataValidation),
+					"Fixes":    BeEmpty(),
+					"Range":    Not(BeNil()),
+				})
+			}
+			result := Validate(mtaYamlPath, []string{prodExtPath, devExtPath})
+			Ω(result).Should(MatchAllKeys(Keys{
+				mtaYamlPath: ConsistOf(
+					issue(fmt.Sprintf(emptyRequiredMergedFieldMsg, "space", "parameter", `dev.mtaext", "prod.mtaext`), 6, 3),
+					// The required non-overwritable value is reported by the validation of the MTA descriptor
+					issue(fmt.Sprintf(emptyRequiredFieldMsg, "fixed", "property"), 18, 7),
+					issue(fmt.Sprintf(emptyRequiredMergedFieldMsg, "service-name", "parameter", `dev.mtaext", "prod.mtaext`), 32, 7),
+				),
+				devExtPath:  BeEmpty(),
+				prodExtPath: BeEmpty(),
+			}))
+		})
+
+		It("doesn't report the required values which are empty when there are no MTA extension descriptors", func() {
+			mtaYamlPath := getTestPath("requiredProject", "mta.yaml")
+			result := Validate(mtaYamlPath, nil)
+			Ω(result[mtaYamlPath]).Should(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Message": Equal(fmt.Sprintf(emptyRequiredFieldMsg, "fixed", "property")),
+			})))
+		})
+
+		It("returns warnings in non-strict mode", func() {
+			mtaContent := []byte("ID: a\nparameters:\n  p:\nparameters-metadata:\n  p:\n    optional: false\n")
+			mtaStr, err := mta.Unmarshal(mtaContent)
+			Ω(err).Should(Succeed())
+			root, _ := getContentNode(mtaContent)
+			errors, warnings := checkMergedRequiredFields(mtaStr, []*MergedDescriptor{{"mta.yaml", root}, {"dev.mtaext", nil}}, false)
+			Ω(errors).Should(BeEmpty())
+			Ω(warnings).Should(HaveLen(1))
+			Ω(withoutNodes(warnings["mta.yaml"])).Should(Equal([]YamlValidationIssue{
+				{Msg: fmt.Sprintf(emptyRequiredMergedFieldMsg, "p", "parameter", "dev.mtaext"), Line: 3, Column: 3},
+			}))
+		})
+	})
 })
 
+// getRemoveMetadataFix returns the fix which removes the lines of the metadata of the key
+func getRemoveMetadataFix(key string, kind string, line int, lastLine int) []QuickFix {
+	return newQuickFix(fmt.Sprintf(removeUnknownNameMetadataFixTitle, key, kind), false,
+		TextEdit{Range: Range{Position{line - 1, 0}, Position{lastLine, 0}}})
+}
+
 var _ = Describe("isPropertyOverWritable", func() {
 	It("default value", func() {
 		Ω(isPropertyOverWritable(nil)).Should(BeTrue())
@@ -4,33 +4,78 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"reflect"
-	"regexp"
 	"strings"
 
+	"github.com/SAP/cloud-mta/internal/expression"
 	"github.com/SAP/cloud-mta/mta"
 )
 
-// ifRequiredDefined - validates that required property sets are defined in modules, provided sections or resources
+const (
+	undefinedPlaceholderParameterMsg = `the "%s" %s of the %s is unresolved; the "%s" parameter is not defined`
+	inactiveRequiredResourceMsg      = `the "%s" resource required by the "%s" %s is not active; the deployer ignores the requires section`
+	mergedInactiveRequiredMsg        = `the "%s" resource is not active after the extensions are merged, but it is required by the "%s" %s and it is not optional`
+	activeYamlField                  = "active"
+)
+
+// deployerParameters are the parameters which the deployer provides or computes by default,
+// so the placeholders can reference them without defining them in the MTA
+var deployerParameters = map[string]bool{
+	"app-name": true, "authorization-url": true, "controller-url": true, "default-app-name": true, "default-domain": true,
+	"default-host": true, "default-instance-name": true, "default-service-name": true, "default-uri": true, "default-url": true,
+	"default-xsappname": true, "deploy-url": true, "domain": true, "domains": true, "generated-password": true,
+	"generated-user": true, "host": true, "hosts": true, "org": true, "protocol": true, "service-name": true, "space": true,
+	"user": true, "xs-type": true, "xsappname": true,
+}
+
+// parametersScope is the parameters which the placeholders in a value can reference, from the inner scope to the outer scope
+type parametersScope []map[string]interface{}
+
+func (scope parametersScope) isDefined(paramName string) bool {
+	for _, params := range scope {
+		if _, ok := params[paramName]; ok {
+			return true
+		}
+	}
+	return deployerParameters[paramName]
+}
+
+// ifRequiredDefined - validates that required property sets are defined in modules, provided sections or resources,
+// and that the placeholders reference parameters which are defined in their scope.
+// The inactive resources don't provide property sets; the requires sections which reference them are ignored,
+// and they are reported as warnings unless the resources are optional.
 func ifRequiredDefined(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
 	var issues []YamlValidationIssue
+	var warnings []YamlValidationIssue
 
 	// init set of all provided property sets
 	provided := make(map[string]map[string]interface{})
+	// init set of the parameters of the entities which can be required, by the names with which they are required
+	providerParams := make(map[string]map[string]interface{})
 
 	for _, module := range mta.Modules {
 		// add module to provided property sets
 		provided[module.Name] = module.Properties
+		providerParams[module.Name] = module.Parameters
 		// add all property sets provided by module
 		for _, prov := range module.Provides {
 			provided[prov.Name] = prov.Properties
+			providerParams[prov.Name] = module.Parameters
 		}
 	}
 
 	// init set of all provided property sets with configuration type
 	configurationProvided := make(map[string]bool)
 
+	// init set of the inactive resources, mapped to whether they are optional
+	inactive := make(map[string]bool)
+
 	// add resources to provided property sets
 	for _, resource := range mta.Resources {
+		if !resource.IsActive() {
+			inactive[resource.Name] = resource.Optional
+			continue
+		}
+		providerParams[resource.Name] = resource.Parameters
 		if resource.Type == configuration {
 			configurationProvided[resource.Name] = true
 		} else {
@@ -38,20 +83,33 @@ func ifRequiredDefined(mta *mta.MTA, mtaNode *yaml.Node, source string, strict b
 		}
 	}
 
+	addIssues := func(errs []YamlValidationIssue, warns []YamlValidationIssue) {
+		issues = append(issues, errs...)
+		warnings = append(warnings, warns...)
+	}
 	modulesNode := getPropContent(mtaNode, modulesYamlField)
 	for i, module := range mta.Modules {
-		issues = append(issues, checkComponent(provided, configurationProvided, module, modulesNode[i], "module")...)
+		moduleScope := parametersScope{module.Parameters, mta.Parameters}
+		addIssues(checkComponent(provided, configurationProvided, providerParams, inactive, module, modulesNode[i], "module", moduleScope))
 		for j, moduleProvides := range module.Provides {
 			providesNode := getPropValueByName(modulesNode[i], providesYamlField)
-			issues = append(issues, checkComponent(provided, configurationProvided, &moduleProvides, providesNode.Content[j], "provided property set of the "+module.Name+" module")...)
+			addIssues(checkComponent(provided, configurationProvided, providerParams, inactive, &moduleProvides, providesNode.Content[j],
+				"provided property set of the "+module.Name+" module", moduleScope))
+		}
+		hooksNode := getPropContent(modulesNode[i], hooksYamlField)
+		for j := range module.Hooks {
+			hookScope := append(parametersScope{module.Hooks[j].Parameters}, moduleScope...)
+			addIssues(checkComponent(provided, configurationProvided, providerParams, inactive, &module.Hooks[j], hooksNode[j],
+				"hook of the "+module.Name+" module", hookScope))
 		}
 	}
 
 	resourcesNode := getPropContent(mtaNode, resourcesYamlField)
 	for i, resource := range mta.Resources {
-		issues = append(issues, checkComponent(provided, configurationProvided, resource, resourcesNode[i], "resource")...)
+		addIssues(checkComponent(provided, configurationProvided, providerParams, inactive, resource, resourcesNode[i], "resource",
+			parametersScope{resource.Parameters, mta.Parameters}))
 	}
-	return issues, nil
+	return issues, warnings
 }
 
 func structFieldToMap(str interface{}, field string) map[string]interface{} {
@@ -82,91 +140,140 @@ func structFieldToString(str interface{}) string {
 	return *strValue
 }
 
-func checkComponent(provided map[string]map[string]interface{}, configurationProvided map[string]bool, component interface{}, compNode *yaml.Node, compDesc string) []YamlValidationIssue {
+// checkComponent checks the variables in the values of the component and its requires sections, and returns the errors
+// on the unresolved variables and the warnings on the placeholders which reference undefined parameters.
+// The scope is the parameters which the placeholders in the values of the component can reference.
+func checkComponent(provided map[string]map[string]interface{}, configurationProvided map[string]bool, providerParams map[string]map[string]interface{},
+	inactive map[string]bool, component interface{}, compNode *yaml.Node, compDesc string, scope parametersScope) ([]YamlValidationIssue, []YamlValidationIssue) {
 	var issues []YamlValidationIssue
+	var warnings []YamlValidationIssue
+	addIssues := func(errs []YamlValidationIssue, warns []YamlValidationIssue) {
+		issues = append(issues, errs...)
+		warnings = append(warnings, warns...)
+	}
 
 	compName := structFieldToString(component)
+	requiringObject := fmt.Sprintf(`"%s" %s`, compName, compDesc)
 	propsNode := getPropValueByName(compNode, propertiesYamlField)
-	issues = append(issues,
-		checkRequiredProperties(provided, configurationProvided, "", structFieldToMap(component, propertiesMtaField),
-			fmt.Sprintf(`"%s" %s`, compName, compDesc), propsNode, propertyEntityKind)...)
+	addIssues(checkRequiredProperties(provided, configurationProvided, "", structFieldToMap(component, propertiesMtaField),
+		requiringObject, propsNode, propertyEntityKind, scope))
 	paramsNode := getPropValueByName(compNode, parametersYamlField)
-	issues = append(issues,
-		checkRequiredProperties(provided, configurationProvided, "", structFieldToMap(component, parametersMtaField),
-			fmt.Sprintf(`"%s" %s`, compName, compDesc), paramsNode, parameterEntityKind)...)
+	addIssues(checkRequiredProperties(provided, configurationProvided, "", structFieldToMap(component, parametersMtaField),
+		requiringObject, paramsNode, parameterEntityKind, scope))
+	// the placeholders in the build parameters are not resolved by the deployer
 	buildParamsNode := getPropValueByName(compNode, buildParametersYamlField)
-	issues = append(issues,
-		checkRequiredProperties(provided, configurationProvided, "", structFieldToMap(component, buildParametersMtaField),
-			fmt.Sprintf(`"%s" %s`, compName, compDesc), buildParamsNode, buildParamEntityKind)...)
+	addIssues(checkRequiredProperties(provided, configurationProvided, "", structFieldToMap(component, buildParametersMtaField),
+		requiringObject, buildParamsNode, buildParamEntityKind, nil))
 	// check that each required by resource property set was provided in mta.yaml
 	requiresNode := getPropValueByName(compNode, requiresYamlField)
 	for i, requires := range structFieldToRequires(component) {
+		if optional, ok := inactive[requires.Name]; ok {
+			if !optional {
+				reqNameNode := getPropValueByName(requiresNode.Content[i], nameYamlField)
+				warnings = appendNodeIssue(warnings, fmt.Sprintf(inactiveRequiredResourceMsg, requires.Name, compName, compDesc),
+					reqNameNode)
+			}
+			continue
+		}
 		_, contains := provided[requires.Name]
 		_, containsConfiguration := configurationProvided[requires.Name]
 		if !contains && !containsConfiguration {
 			reqNameNode := getPropValueByName(requiresNode.Content[i], nameYamlField)
-			issues = appendIssue(issues,
+			issues = appendNodeIssue(issues,
 				fmt.Sprintf(`the "%s" property set required by the "%s" %s is not defined`,
-					requires.Name, compName, compDesc), reqNameNode.Line, reqNameNode.Column)
+					requires.Name, compName, compDesc), reqNameNode)
 		}
+		// the placeholders in the requires section can reference the parameters of the required entity and of the requires section
+		requiresScope := append(parametersScope{providerParams[requires.Name], requires.Parameters}, scope...)
 		// check that each property of resource is resolved
 		reqPropsNode := getPropValueByName(requiresNode.Content[i], propertiesYamlField)
-		issues = append(issues,
-			checkRequiredProperties(provided, configurationProvided, requires.Name, requires.Properties,
-				fmt.Sprintf(`"%s" %s`, compName, compDesc), reqPropsNode, propertyEntityKind)...)
+		addIssues(checkRequiredProperties(provided, configurationProvided, requires.Name, requires.Properties,
+			requiringObject, reqPropsNode, propertyEntityKind, requiresScope))
 		// check that each parameter of resource is resolved
 		reqParamsNode := getPropValueByName(requiresNode.Content[i], parametersYamlField)
-		issues = append(issues,
-			checkRequiredProperties(provided, configurationProvided, requires.Name, requires.Parameters,
-				fmt.Sprintf(`"%s" %s`, compName, compDesc), reqParamsNode, parameterEntityKind)...)
+		addIssues(checkRequiredProperties(provided, configurationProvided, requires.Name, requires.Parameters,
+			requiringObject, reqParamsNode, parameterEntityKind, requiresScope))
 	}
-	return issues
+	return issues, warnings
 }
 
 func checkRequiredProperties(providedProps map[string]map[string]interface{}, configurationProvided map[string]bool, requiredPropSet string,
-	requiredEntities map[string]interface{}, requiringObject string, node *yaml.Node, entityKind string) []YamlValidationIssue {
+	requiredEntities map[string]interface{}, requiringObject string, node *yaml.Node, entityKind string, scope parametersScope) ([]YamlValidationIssue, []YamlValidationIssue) {
 
 	var issues []YamlValidationIssue
+	var warnings []YamlValidationIssue
 	if requiredEntities == nil {
-		return nil
+		return nil, nil
 	}
 	for entityName, entityValue := range requiredEntities {
 		entityNode := getPropValueByName(node, entityName)
-		issues = append(issues, checkValue(providedProps, configurationProvided, entityName, entityKind, requiredPropSet, requiringObject, entityValue, entityNode)...)
+		errs, warns := checkValue(providedProps, configurationProvided, entityName, entityKind, requiredPropSet, requiringObject, entityValue, entityNode, scope)
+		issues = append(issues, errs...)
+		warnings = append(warnings, warns...)
 	}
-	return issues
+	return issues, warnings
 }
 
+// checkValue checks the variables and placeholders in the string values in the value, in its maps and arrays at any depth.
+// The placeholders are not checked if the scope is nil.
 func checkValue(providedProps map[string]map[string]interface{}, configurationProvided map[string]bool,
-	entityName, entityKind, propSet, requiringObject string, entityValue interface{}, node *yaml.Node) []YamlValidationIssue {
+	entityName, entityKind, propSet, requiringObject string, entityValue interface{}, node *yaml.Node, scope parametersScope) ([]YamlValidationIssue, []YamlValidationIssue) {
 	var issues []YamlValidationIssue
-	propValueStr, ok := entityValue.(string)
-	if ok {
+	var warnings []YamlValidationIssue
+	switch value := entityValue.(type) {
+	case string:
 		// property is simple - check if it can be resolved
-		issues = checkStringEntityValue(providedProps, configurationProvided, entityName, propValueStr, entityKind, propSet, requiringObject, node)
-	} else {
-		propValueMap, ok := entityValue.(map[string]interface{})
-		if ok {
-			// property is a map
-			for key, value := range propValueMap {
-				childNode := getPropValueByName(node, key)
-				// check every sub property
-				issues = append(issues, checkValue(providedProps, configurationProvided, entityName+"."+key, entityKind, propSet, requiringObject, value, childNode)...)
+		if node == nil {
+			return nil, nil
+		}
+		issues = checkStringEntityValue(providedProps, configurationProvided, entityName, value, entityKind, propSet, requiringObject, node)
+		if scope != nil {
+			warnings = checkPlaceholders(entityName, value, entityKind, requiringObject, node, scope)
+		}
+	case map[string]interface{}:
+		// property is a map
+		for key, childValue := range value {
+			childNode := getPropValueByName(node, key)
+			// check every sub property
+			errs, warns := checkValue(providedProps, configurationProvided, entityName+"."+key, entityKind, propSet, requiringObject, childValue, childNode, scope)
+			issues = append(issues, errs...)
+			warnings = append(warnings, warns...)
+		}
+	case map[interface{}]interface{}:
+		// property is a map in an array
+		for key, childValue := range value {
+			keyStr := fmt.Sprint(key)
+			childNode := getPropValueByName(node, keyStr)
+			errs, warns := checkValue(providedProps, configurationProvided, entityName+"."+keyStr, entityKind, propSet, requiringObject, childValue, childNode, scope)
+			issues = append(issues, errs...)
+			warnings = append(warnings, warns...)
+		}
+	case []interface{}:
+		// property is an array
+		for i, childValue := range value {
+			var childNode *yaml.Node
+			if node != nil && node.Kind == yaml.SequenceNode && i < len(node.Content) {
+				childNode = node.Content[i]
 			}
+			// check every item
+			errs, warns := checkValue(providedProps, configurationProvided, fmt.Sprintf("%s[%d]", entityName, i), entityKind, propSet, requiringObject, childValue, childNode, scope)
+			issues = append(issues, errs...)
+			warnings = append(warnings, warns...)
 		}
 	}
-	return issues
+	return issues, warnings
 }
 
 func checkStringEntityValue(providedProps map[string]map[string]interface{}, configurationProvided map[string]bool,
 	entityName, entityValue, entityKind, propSet, requiringObject string, entityNode *yaml.Node) []YamlValidationIssue {
 	var issues []YamlValidationIssue
-	r := regexp.MustCompile(`~{[^{}]+}`)
-	// find all placeholders
-	matches := r.FindAllString(entityValue, -1)
-	for _, match := range matches {
-		// get placeholder pure name, removing tilda and brackets
-		requiredProp := strings.TrimPrefix(strings.TrimSuffix(match, "}"), "~{")
+	// find all variables; malformed expressions are reported by the expressions validation
+	for _, variable := range expression.Parse(entityValue).Expressions(expression.Variable) {
+		if variable.DoubleBraced {
+			continue
+		}
+		requiredProp := variable.Value
+		line, column := getExpressionPosition(entityNode, variable.Start)
 		// if property set was not provided it has to be presented in placeholder
 		if propSet == "" {
 			// split placeholder to property set and property name
@@ -175,22 +282,36 @@ func checkStringEntityValue(providedProps map[string]map[string]interface{}, con
 				// no property set provided
 				issues = appendIssue(issues,
 					fmt.Sprintf(`the "%s" %s of the %s is unresolved; the "%s" property is not provided`,
-						entityName, entityKind, requiringObject, requiredProp), entityNode.Line, entityNode.Column)
+						entityName, entityKind, requiringObject, requiredProp), line, column)
 			} else {
 				// check existence of property if property set
 				issues = appendIssue(issues,
-					checkRequiredProperty(providedProps, configurationProvided, entityName, entityKind, requiredPropArr[0], requiredPropArr[1], requiringObject), entityNode.Line, entityNode.Column)
+					checkRequiredProperty(providedProps, configurationProvided, entityName, entityKind, requiredPropArr[0], requiredPropArr[1], requiringObject), line, column)
 			}
 		} else {
 			// check existence of property if property set
 			issues = appendIssue(issues,
-				checkRequiredProperty(providedProps, configurationProvided, entityName, entityKind, propSet, requiredProp, requiringObject), entityNode.Line, entityNode.Column)
+				checkRequiredProperty(providedProps, configurationProvided, entityName, entityKind, propSet, requiredProp, requiringObject), line, column)
 		}
 
 	}
 	return issues
 }
 
+// checkPlaceholders checks the placeholders in the value reference parameters which are defined in the scope
+func checkPlaceholders(entityName, entityValue, entityKind, requiringObject string, entityNode *yaml.Node, scope parametersScope) []YamlValidationIssue {
+	var issues []YamlValidationIssue
+	// malformed expressions are reported by the expressions validation
+	for _, placeholder := range expression.Parse(entityValue).Expressions(expression.Placeholder) {
+		if placeholder.DoubleBraced || scope.isDefined(placeholder.Value) {
+			continue
+		}
+		line, column := getExpressionPosition(entityNode, placeholder.Start)
+		issues = appendIssue(issues, fmt.Sprintf(undefinedPlaceholderParameterMsg, entityName, entityKind, requiringObject, placeholder.Value), line, column)
+	}
+	return issues
+}
+
 func checkRequiredProperty(providedProps map[string]map[string]interface{}, configurationProvided map[string]bool, entityName, entityKind,
 	requiredSet, requiredProp, requiringObject string) string {
 	providedSet, ok := providedProps[requiredSet]
@@ -206,3 +327,74 @@ func checkRequiredProperty(providedProps map[string]map[string]interface{}, conf
 	}
 	return ""
 }
+
+// checkMergedInactiveRequires reports the resources which an MTA extension descriptor makes inactive while
+// they are required and not optional. The warnings are on the "active" field in the extension which sets it last.
+func checkMergedInactiveRequires(mtaObj *mta.MTA, descriptors []*MergedDescriptor, strict bool) (errors map[string][]YamlValidationIssue, warnings map[string][]YamlValidationIssue) {
+	warnings = make(map[string][]YamlValidationIssue)
+	for _, resource := range mtaObj.Resources {
+		if resource.IsActive() || resource.Optional {
+			continue
+		}
+		activeNode := getSupplyingActiveNode(descriptors, resource.Name)
+		if activeNode == nil {
+			continue
+		}
+		for _, requiring := range getRequiringEntities(mtaObj, resource.Name) {
+			warnings[activeNode.path] = appendNodeIssue(warnings[activeNode.path],
+				fmt.Sprintf(mergedInactiveRequiredMsg, resource.Name, requiring.name, requiring.desc), activeNode.node)
+		}
+	}
+	return nil, warnings
+}
+
+type descriptorNode struct {
+	path string
+	node *yaml.Node
+}
+
+// getSupplyingActiveNode returns the value node of the "active" field of the resource in the MTA extension descriptor
+// which sets it last, or nil if only the MTA descriptor sets it
+func getSupplyingActiveNode(descriptors []*MergedDescriptor, resourceName string) *descriptorNode {
+	for i := len(descriptors) - 1; i > 0; i-- {
+		resourceNode := getEntityNode(descriptors[i].Root, []entityStep{{resourcesYamlField, resourceName}})
+		if activeNode := getPropValueByName(resourceNode, activeYamlField); activeNode != nil {
+			return &descriptorNode{descriptors[i].Path, activeNode}
+		}
+	}
+	return nil
+}
+
+type requiringEntity struct {
+	name string
+	desc string
+}
+
+// getRequiringEntities returns the modules, hooks and resources which have a requires section with the name
+func getRequiringEntities(mtaObj *mta.MTA, name string) []requiringEntity {
+	var result []requiringEntity
+	requiresName := func(requiresList []mta.Requires) bool {
+		for _, requires := range requiresList {
+			if requires.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, module := range mtaObj.Modules {
+		if requiresName(module.Requires) {
+			result = append(result, requiringEntity{module.Name, "module"})
+		}
+		for _, hook := range module.Hooks {
+			if requiresName(hook.Requires) {
+				result = append(result, requiringEntity{hook.Name, "hook of the " + module.Name + " module"})
+			}
+		}
+	}
+	for _, resource := range mtaObj.Resources {
+		if requiresName(resource.Requires) {
+			result = append(result, requiringEntity{resource.Name, "resource"})
+		}
+	}
+	return result
+}
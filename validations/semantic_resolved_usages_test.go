@@ -1,8 +1,11 @@
 package validate
 
 import (
+	"fmt"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 
 	"github.com/SAP/cloud-mta/mta"
 )
@@ -151,4 +154,198 @@ modules:
 		issues, _ := ifRequiredDefined(mta, node, "", true)
 		Ω(len(issues)).Should(Equal(13))
 	})
+
+	It("reports unresolved variables at their columns", func() {
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '2.1'
+version: 0.0.1
+
+modules:
+ - name: pricing-ui
+   type: javascript.nodejs
+   properties:
+     conn_string: "~{price_opt/protocol}://~{price_opt/uri}/~{{literal}}"
+     escaped: \~{price_opt/uri}
+
+ - name: pricing-backend
+   type: html5
+   provides:
+   - name: price_opt
+     properties:
+       protocol: http
+`)
+		mta, _ := mta.Unmarshal(mtaContent)
+		node, _ := getContentNode(mtaContent)
+		issues, _ := ifRequiredDefined(mta, node, "", true)
+		Ω(issues).Should(Equal([]YamlValidationIssue{{
+			Msg:    `the "conn_string" property of the "pricing-ui" module is unresolved; the "price_opt/uri" property is not provided`,
+			Line:   10,
+			Column: 44,
+		}}))
+	})
+
+	It("checks the variables in arrays at any depth and in hooks", func() {
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: nodejs
+   properties:
+     urls:
+       - ~{api/url}
+       - ["~{api/missing}"]
+       - nested:
+           - ~{api/unknown}
+   hooks:
+     - name: h1
+       parameters:
+         name: ~{api/missing}
+       requires:
+         - name: api
+           properties:
+             a: ~{url}
+             b: ~{other}
+         - name: undefined
+
+ - name: backend
+   type: nodejs
+   provides:
+     - name: api
+       properties:
+         url: http://localhost
+`)
+		mta, _ := mta.Unmarshal(mtaContent)
+		node, _ := getContentNode(mtaContent)
+		issues, warnings := ifRequiredDefined(mta, node, "", true)
+		Ω(warnings).Should(BeEmpty())
+		Ω(withoutNodes(issues)).Should(ConsistOf(
+			YamlValidationIssue{Msg: `the "urls[1][0]" property of the "srv" module is unresolved; the "api/missing" property is not provided`, Line: 12, Column: 12},
+			YamlValidationIssue{Msg: `the "urls[2].nested[0]" property of the "srv" module is unresolved; the "api/unknown" property is not provided`, Line: 14, Column: 14},
+			YamlValidationIssue{Msg: `the "name" parameter of the "h1" hook of the srv module is unresolved; the "api/missing" property is not provided`, Line: 18, Column: 16},
+			YamlValidationIssue{Msg: `the "b" property of the "h1" hook of the srv module is unresolved; the "api/other" property is not provided`, Line: 23, Column: 17},
+			YamlValidationIssue{Msg: `the "undefined" property set required by the "h1" hook of the srv module is not defined`, Line: 24, Column: 18},
+		))
+	})
+
+	It("reports the placeholders which reference parameters that are not defined in their scope", func() {
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+parameters:
+  global: a
+
+modules:
+ - name: srv
+   type: nodejs
+   parameters:
+     own: b
+   properties:
+     a: ${own}-${global}-${default-url}-${{literal}}
+     b: ["${missing}"]
+   build-parameters:
+     c: ${not-resolved-by-the-deployer}
+   requires:
+     - name: db
+       parameters:
+         req: c
+       properties:
+         c: ${req}-${schema}-${own}
+         d: ${other}
+   hooks:
+     - name: h1
+       parameters:
+         hook: d
+         e: ${hook}-${own}-${unknown}
+
+resources:
+ - name: db
+   type: com.sap.xs.hdi-container
+   parameters:
+     schema: DB
+     f: ${own}
+`)
+		mta, _ := mta.Unmarshal(mtaContent)
+		node, _ := getContentNode(mtaContent)
+		issues, warnings := ifRequiredDefined(mta, node, "", true)
+		Ω(issues).Should(BeEmpty())
+		Ω(warnings).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(undefinedPlaceholderParameterMsg, "b[0]", "property", `"srv" module`, "missing"), Line: 16, Column: 11},
+			YamlValidationIssue{Msg: fmt.Sprintf(undefinedPlaceholderParameterMsg, "d", "property", `"srv" module`, "other"), Line: 25, Column: 13},
+			YamlValidationIssue{Msg: fmt.Sprintf(undefinedPlaceholderParameterMsg, "e", "parameter", `"h1" hook of the srv module`, "unknown"), Line: 30, Column: 28},
+			YamlValidationIssue{Msg: fmt.Sprintf(undefinedPlaceholderParameterMsg, "f", "parameter", `"db" resource`, "own"), Line: 37, Column: 9},
+		))
+	})
+
+	It("ignores the inactive resources and warns on the requires sections which reference them unless they are optional", func() {
+		mtaContent := []byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: nodejs
+   properties:
+     url: ~{db/url}
+   requires:
+     - name: db
+       properties:
+         schema: ~{schema}
+     - name: cache
+   hooks:
+     - name: h1
+       requires:
+         - name: db
+
+resources:
+ - name: db
+   type: org.cloudfoundry.existing-service
+   active: false
+   properties:
+     url: https://db
+     schema: DB
+ - name: cache
+   type: org.cloudfoundry.existing-service
+   active: false
+   optional: true
+`)
+		mta, _ := mta.Unmarshal(mtaContent)
+		node, _ := getContentNode(mtaContent)
+		issues, warnings := ifRequiredDefined(mta, node, "", true)
+		Ω(issues).Should(ConsistOf(
+			YamlValidationIssue{Msg: `the "url" property of the "srv" module is unresolved; the "db/url" property is not provided`, Line: 10, Column: 11},
+		))
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(inactiveRequiredResourceMsg, "db", "srv", "module"), Line: 12, Column: 14},
+			YamlValidationIssue{Msg: fmt.Sprintf(inactiveRequiredResourceMsg, "db", "h1", "hook of the srv module"), Line: 19, Column: 18},
+		))
+	})
+
+	var _ = Describe("checkMergedInactiveRequires", func() {
+		It("warns on the extension which makes a required resource inactive", func() {
+			mtaYamlPath := getTestPath("inactiveProject", "mta.yaml")
+			devExtPath := getTestPath("inactiveProject", "dev.mtaext")
+			result := Validate(mtaYamlPath, []string{devExtPath})
+			Ω(result[devExtPath]).Should(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Severity": Equal(SeverityWarning),
+				"Message":  Equal(fmt.Sprintf(mergedInactiveRequiredMsg, "db", "srv", "module")),
+				"Line":     Equal(7),
+				"Column":   Equal(12),
+				"Rule":     Equal(requiredValidation),
+			})))
+			// The resource which is inactive in the MTA descriptor is reported by the validation of the MTA descriptor
+			Ω(result[mtaYamlPath]).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Severity": Equal(SeverityWarning),
+				"Message":  Equal(fmt.Sprintf(inactiveRequiredResourceMsg, "log", "srv", "module")),
+				"Line":     Equal(11),
+				"Column":   Equal(14),
+			})))
+		})
+	})
 })
@@ -0,0 +1,107 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	sandboxAbsolutePathMsg = `the "%s" %s of the %s is an absolute path; expected a path relative to the project folder`
+	sandboxOutsidePathMsg  = `the "%s" %s of the %s is outside of the project folder`
+	sandboxLinkedPathMsg   = `the "%s" %s of the %s is outside of the project folder after its symbolic links are resolved`
+
+	modulePathLocation  = "path"
+	includePathLocation = "path of the %s"
+)
+
+// sandboxRules are the opt-in rules which keep the paths in the MTA descriptor in the project folder
+var sandboxRules = []string{modulePathSandboxValidation, includePathSandboxValidation, resourcePathSandboxValidation}
+
+// checkModulePathSandbox checks that the paths of the modules are in the project folder
+func checkModulePathSandbox(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var references []fileReference
+	for _, module := range mta.Modules {
+		if module.Path == "" {
+			continue
+		}
+		moduleNode := getEntityNode(mtaNode, []entityStep{{modulesYamlField, module.Name}})
+		references = append(references, fileReference{fmt.Sprintf(`"%s" %s`, module.Name, moduleEntityKind),
+			modulePathLocation, module.Path, "", getPropValueByName(moduleNode, pathYamlField)})
+	}
+	return checkSandboxPaths(references, source, strict)
+}
+
+// checkIncludePathSandbox checks that the paths of the includes of the modules and the resources are in the project folder
+func checkIncludePathSandbox(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var references []fileReference
+	for _, reference := range getFileReferences(mta, mtaNode) {
+		if reference.location != pathParameterLocation {
+			reference.location = fmt.Sprintf(includePathLocation, reference.location)
+			references = append(references, reference)
+		}
+	}
+	return checkSandboxPaths(references, source, strict)
+}
+
+// checkResourcePathSandbox checks that the path parameters of the resources are in the project folder
+func checkResourcePathSandbox(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var references []fileReference
+	for _, reference := range getFileReferences(mta, mtaNode) {
+		if reference.location == pathParameterLocation {
+			references = append(references, reference)
+		}
+	}
+	return checkSandboxPaths(references, source, strict)
+}
+
+func checkSandboxPaths(references []fileReference, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	for _, reference := range references {
+		msgFormat := getSandboxViolation(source, reference.path)
+		if msgFormat == "" || reference.node == nil {
+			continue
+		}
+		msg := fmt.Sprintf(msgFormat, reference.path, reference.location, reference.owner)
+		if strict {
+			errors = appendNodeIssue(errors, msg, reference.node)
+		} else {
+			warnings = appendNodeIssue(warnings, msg, reference.node)
+		}
+	}
+	return errors, warnings
+}
+
+// getSandboxViolation returns the message format of the way in which the path escapes the project folder,
+// or an empty string if the path is in the project folder. The path is relative to the project folder.
+func getSandboxViolation(projectPath string, path string) string {
+	if filepath.IsAbs(path) {
+		return sandboxAbsolutePathMsg
+	}
+	fullPath := filepath.Join(projectPath, path)
+	if isOutsideFolder(projectPath, fullPath) {
+		return sandboxOutsidePathMsg
+	}
+	// The symbolic links in the project folder itself are resolved too, so they don't cause violations
+	if inFolder, err := fs.IsInFolder(projectPath, fullPath); err == nil && !inFolder {
+		return sandboxLinkedPathMsg
+	}
+	return ""
+}
+
+// isOutsideFolder returns true if the path is not in the folder
+func isOutsideFolder(folder string, path string) bool {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	relPath, err := filepath.Rel(absFolder, absPath)
+	return err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,78 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("sandbox semantic validations", func() {
+	linkPath := getTestPath("sandboxProject", "linked")
+
+	BeforeEach(func() {
+		// The link points to the testdata folder, outside of the project folder
+		Ω(os.Symlink(getTestPath(), linkPath)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Ω(os.Remove(linkPath)).Should(Succeed())
+	})
+
+	getSandboxIssues := func(check checkSemantic, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		content, err := fs.ReadFile(getTestPath("sandboxProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		return check(mtaStr, root, getTestPath("sandboxProject"), strict)
+	}
+
+	It("checks that the paths of the modules are in the project folder", func() {
+		errors, warnings := getSandboxIssues(checkModulePathSandbox, true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxOutsidePathMsg, "../srv", modulePathLocation, `"parent" module`), Line: 16, Column: 10},
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxAbsolutePathMsg, "/srv", modulePathLocation, `"absolute" module`), Line: 19, Column: 10},
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxLinkedPathMsg, "linked/srv", modulePathLocation, `"linked" module`), Line: 22, Column: 10},
+		))
+		Ω(warnings).Should(BeEmpty())
+	})
+
+	It("checks that the paths of the includes are in the project folder", func() {
+		errors, warnings := getSandboxIssues(checkIncludePathSandbox, false)
+		Ω(errors).Should(BeEmpty())
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxOutsidePathMsg, "config/../../env.yaml", `path of the "outside" include`, `"inside" module`), Line: 13, Column: 13},
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxLinkedPathMsg, "linked/env.yaml", `path of the "linked" include`, `"linked" module`), Line: 25, Column: 13},
+		))
+	})
+
+	It("checks that the path parameters of the resources are in the project folder", func() {
+		errors, _ := getSandboxIssues(checkResourcePathSandbox, true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxOutsidePathMsg, "../binding.json", pathParameterLocation, `"outside" resource`), Line: 35, Column: 12},
+			YamlValidationIssue{Msg: fmt.Sprintf(sandboxLinkedPathMsg, "linked/binding.json", pathParameterLocation, `"linked" resource`), Line: 39, Column: 12},
+		))
+	})
+
+	It("runs only the sandbox rules which are enabled in the lint configuration", func() {
+		result := Validate(getTestPath("sandboxProject", "mta.yaml"), nil)
+		var sandboxIssues []FileValidationIssue
+		for _, issue := range result[getTestPath("sandboxProject", "mta.yaml")] {
+			if issue.Rule == modulePathSandboxValidation || issue.Rule == includePathSandboxValidation || issue.Rule == resourcePathSandboxValidation {
+				sandboxIssues = append(sandboxIssues, issue)
+			}
+		}
+		matchModuleIssue := MatchFields(IgnoreExtras, Fields{
+			"Severity": Equal(SeverityError),
+			"Rule":     Equal(modulePathSandboxValidation),
+		})
+		Ω(sandboxIssues).Should(ConsistOf(matchModuleIssue, matchModuleIssue, matchModuleIssue))
+	})
+})
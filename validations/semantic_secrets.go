@@ -0,0 +1,357 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/SAP/cloud-mta/internal/expression"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	secretKeyMsg             = `the "%s" %s looks like a secret because of its name and its value is literal; %s`
+	highEntropyValueMsg      = `the value of the "%s" %s looks like a secret because it is a random string; %s`
+	sensitiveLiteralMsg      = `the value of the sensitive "%s" %s is literal; %s`
+	moveSecretRecommendation = `replace it with a placeholder which is resolved during the deployment, or move it to an MTA extension descriptor which is not committed`
+	extSecretRecommendation  = `replace it with a placeholder which is resolved during the deployment, or keep the MTA extension descriptor out of source control`
+
+	// minSecretLength and minSecretEntropy are the thresholds of the random strings: the Shannon entropy in bits per character
+	// of the generated passwords, keys and tokens is higher than the entropy of names, URLs and identifiers of the same length
+	minSecretLength  = 20
+	minSecretEntropy = 4.3
+	// minSecretCharClasses is the minimum number of classes (lowercase and uppercase letters, digits and symbols) in a random string
+	minSecretCharClasses = 3
+)
+
+// secretsRules are the opt-in rules which detect secrets in the descriptors
+var secretsRules = []string{secretKeysValidation, highEntropyValuesValidation, sensitiveLiteralsValidation}
+
+// secretKeyNames are the parts of the names of the keys whose values are usually secrets. The names are compared
+// in lowercase and without separators, so "client_secret" and "clientSecret" match "secret".
+var secretKeyNames = []string{"password", "passwd", "secret", "token", "apikey", "accesskey", "privatekey", "credentials"}
+
+// secretKeyExactNames are the names which match only as whole names, because they are parts of other words
+var secretKeyExactNames = map[string]bool{"pwd": true, "pass": true}
+
+// nonSecretKeySuffixes are the suffixes of the names of the keys which reference secrets but don't contain them,
+// e.g. "token-url" or "secret-name"
+var nonSecretKeySuffixes = []string{"url", "uri", "endpoint", "name", "path", "file", "type"}
+
+// secretsAndExtRule is a built-in rule which detects secrets in MTA descriptors and MTA extension descriptors.
+// Its checks are created with the allowlist of the lint configuration of the validation.
+type secretsAndExtRule struct {
+	*semanticAndExtRule
+	newChecks func(allowlist *secretsAllowlist) (checkSemantic, checkExtSemantic)
+}
+
+func newSecretsAndExtRule(id, severity, description, docLink string,
+	newChecks func(allowlist *secretsAllowlist) (checkSemantic, checkExtSemantic)) *secretsAndExtRule {
+	check, checkExt := newChecks(&secretsAllowlist{})
+	return &secretsAndExtRule{newSemanticAndExtRule(id, severity, description, docLink, check, checkExt), newChecks}
+}
+
+func (r *secretsAndExtRule) withLintConfig(config *LintConfig) Rule {
+	check, checkExt := r.newChecks(config.getSecretsAllowlist())
+	return &secretsAndExtRule{newSemanticAndExtRule(r.id, r.severity, r.description, r.docLink, check, checkExt), r.newChecks}
+}
+
+// secretsAndMergedRule is a built-in rule which detects secrets in MTA descriptors and merged MTAs.
+// Its checks are created with the allowlist of the lint configuration of the validation.
+type secretsAndMergedRule struct {
+	*semanticAndMergedRule
+	newChecks func(allowlist *secretsAllowlist) (checkSemantic, checkMergedSemantic)
+}
+
+func newSecretsAndMergedRule(id, severity, description, docLink string,
+	newChecks func(allowlist *secretsAllowlist) (checkSemantic, checkMergedSemantic)) *secretsAndMergedRule {
+	check, checkMerged := newChecks(&secretsAllowlist{})
+	return &secretsAndMergedRule{newSemanticAndMergedRule(id, severity, description, docLink, check, checkMerged), newChecks}
+}
+
+func (r *secretsAndMergedRule) withLintConfig(config *LintConfig) Rule {
+	check, checkMerged := r.newChecks(config.getSecretsAllowlist())
+	return &secretsAndMergedRule{newSemanticAndMergedRule(r.id, r.severity, r.description, r.docLink, check, checkMerged), r.newChecks}
+}
+
+// secretsAllowlist is the compiled allowlist of the secret rules
+type secretsAllowlist struct {
+	keys   []string
+	values []*regexp.Regexp
+}
+
+// getSecretsAllowlist returns the compiled allowlist of the configuration.
+// The wrong regular expressions are reported when the configuration is read, so they are skipped.
+func (c *LintConfig) getSecretsAllowlist() *secretsAllowlist {
+	allowlist := &secretsAllowlist{}
+	if c == nil {
+		return allowlist
+	}
+	allowlist.keys = c.Secrets.Allowlist.Keys
+	for _, expr := range c.Secrets.Allowlist.Values {
+		if re, err := regexp.Compile("^(?:" + expr + ")$"); err == nil {
+			allowlist.values = append(allowlist.values, re)
+		}
+	}
+	return allowlist
+}
+
+// allows returns true if the key or the value is in the allowlist
+func (allowlist *secretsAllowlist) allows(key string, value string) bool {
+	if matchesAny(allowlist.keys, key) {
+		return true
+	}
+	for _, re := range allowlist.values {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// literalValue is a literal scalar value in a map of parameters or properties
+type literalValue struct {
+	// key - the name of the key of the value in its map
+	key string
+	// name - the path of the value in the parameters or properties, e.g. "credentials.password"
+	name       string
+	entityKind string
+	node       *yaml.Node
+}
+
+// checkSecretKeys checks the literal values of the parameters and properties which have the names of secrets
+func (allowlist *secretsAllowlist) checkSecretKeys(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, allowlist.getSecretKeyIssues(mtaNode, moveSecretRecommendation)
+}
+
+// checkExtSecretKeys checks the literal values of the parameters and properties in the MTA extension descriptor
+// which have the names of secrets
+func (allowlist *secretsAllowlist) checkExtSecretKeys(mtaExt *mta.EXT, extNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, allowlist.getSecretKeyIssues(extNode, extSecretRecommendation)
+}
+
+func (allowlist *secretsAllowlist) getSecretKeyIssues(root *yaml.Node, recommendation string) []YamlValidationIssue {
+	var issues []YamlValidationIssue
+	for _, value := range getLiteralValues(root) {
+		if value.node.Tag != "!!bool" && isSecretKey(value.key) && !allowlist.allows(value.key, value.node.Value) {
+			issues = appendNodeIssue(issues, fmt.Sprintf(secretKeyMsg, value.name, value.entityKind, recommendation),
+				value.node)
+		}
+	}
+	return issues
+}
+
+// checkHighEntropyValues checks the literal values of the parameters and properties which are random strings
+func (allowlist *secretsAllowlist) checkHighEntropyValues(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, allowlist.getHighEntropyIssues(mtaNode, moveSecretRecommendation)
+}
+
+// checkExtHighEntropyValues checks the literal values of the parameters and properties in the MTA extension descriptor
+// which are random strings
+func (allowlist *secretsAllowlist) checkExtHighEntropyValues(mtaExt *mta.EXT, extNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, allowlist.getHighEntropyIssues(extNode, extSecretRecommendation)
+}
+
+func (allowlist *secretsAllowlist) getHighEntropyIssues(root *yaml.Node, recommendation string) []YamlValidationIssue {
+	var issues []YamlValidationIssue
+	for _, value := range getLiteralValues(root) {
+		if value.node.Tag == "!!str" && isRandomString(value.node.Value) && !allowlist.allows(value.key, value.node.Value) {
+			issues = appendNodeIssue(issues, fmt.Sprintf(highEntropyValueMsg, value.name, value.entityKind, recommendation),
+				value.node)
+		}
+	}
+	return issues
+}
+
+// checkSensitiveLiterals checks that the values of the parameters and properties which are sensitive in their metadata
+// are not literal values
+func (allowlist *secretsAllowlist) checkSensitiveLiterals(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	issues := validateMetadata(mta, mtaNode, source, allowlist.getSensitiveLiteralsValidator())
+
+	if strict {
+		return issues, nil
+	}
+	return nil, issues
+}
+
+// getSensitiveLiteralsValidator returns a metadata validator which checks the values of the sensitive keys in the map
+func (allowlist *secretsAllowlist) getSensitiveLiteralsValidator() metadataValidator {
+	return func(m map[string]interface{}, metadata map[string]mta.MetaData, parentNode *yaml.Node, mapType int) []YamlValidationIssue {
+		var issues []YamlValidationIssue
+		mapNode := getPropValueByName(parentNode, mapTypes[mapType].mapNodeName)
+		for key, keyMetadata := range metadata {
+			valueNode := getPropValueByName(mapNode, key)
+			if keyMetadata.Sensitive && isLiteralNode(valueNode) && !allowlist.allows(key, valueNode.Value) {
+				issues = appendNodeIssue(issues, fmt.Sprintf(sensitiveLiteralMsg, key, mapTypes[mapType].entityKind, moveSecretRecommendation),
+					valueNode)
+			}
+		}
+		return issues
+	}
+}
+
+// checkMergedSensitiveLiterals checks that the values which the MTA extension descriptors supply for the sensitive
+// parameters and properties are not literal values. The values in the MTA descriptor are checked in its validation.
+func (allowlist *secretsAllowlist) checkMergedSensitiveLiterals(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (errors map[string][]YamlValidationIssue, warnings map[string][]YamlValidationIssue) {
+	issues := make(map[string][]YamlValidationIssue)
+	for _, m := range getMergedMaps(mta) {
+		for key, keyMetadata := range m.metadata {
+			if !keyMetadata.Sensitive {
+				continue
+			}
+			index, _, valueNode := getSupplyingDescriptor(descriptors, m.path, mapTypes[m.mapType].mapNodeName, key)
+			if index <= 0 || !isLiteralNode(valueNode) || allowlist.allows(key, valueNode.Value) {
+				continue
+			}
+			path := descriptors[index].Path
+			issues[path] = appendNodeIssue(issues[path], fmt.Sprintf(sensitiveLiteralMsg, key, mapTypes[m.mapType].entityKind, extSecretRecommendation),
+				valueNode)
+		}
+	}
+
+	if strict {
+		return issues, nil
+	}
+	return nil, issues
+}
+
+// getLiteralValues returns the literal values in the maps of parameters and properties in the descriptor, in their maps
+// and lists at any depth: the global parameters and the parameters and properties of the modules, the resources,
+// the provided and required property sets and the hooks
+func getLiteralValues(root *yaml.Node) []literalValue {
+	var values []literalValue
+	add := func(entityNode *yaml.Node, mapType int) {
+		mapNode := getPropValueByName(entityNode, mapTypes[mapType].mapNodeName)
+		values = append(values, getLiteralValuesInNode("", "", mapNode, mapTypes[mapType].entityKind)...)
+	}
+	addRequires := func(entityNode *yaml.Node) {
+		for _, requiresNode := range getPropContent(entityNode, requiresYamlField) {
+			add(requiresNode, mapTypeParameters)
+			add(requiresNode, mapTypeProperties)
+		}
+	}
+
+	add(root, mapTypeParameters)
+	for _, moduleNode := range getPropContent(root, modulesYamlField) {
+		add(moduleNode, mapTypeParameters)
+		add(moduleNode, mapTypeProperties)
+		for _, providesNode := range getPropContent(moduleNode, providesYamlField) {
+			add(providesNode, mapTypeProperties)
+		}
+		addRequires(moduleNode)
+		for _, hookNode := range getPropContent(moduleNode, hooksYamlField) {
+			add(hookNode, mapTypeParameters)
+			addRequires(hookNode)
+		}
+	}
+	for _, resourceNode := range getPropContent(root, resourcesYamlField) {
+		add(resourceNode, mapTypeParameters)
+		add(resourceNode, mapTypeProperties)
+		addRequires(resourceNode)
+	}
+	return values
+}
+
+func getLiteralValuesInNode(key string, name string, node *yaml.Node, entityKind string) []literalValue {
+	if node == nil {
+		return nil
+	}
+	var values []literalValue
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey := node.Content[i].Value
+			childName := childKey
+			if name != "" {
+				childName = name + "." + childKey
+			}
+			values = append(values, getLiteralValuesInNode(childKey, childName, node.Content[i+1], entityKind)...)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			values = append(values, getLiteralValuesInNode(key, fmt.Sprintf("%s[%d]", name, i), child, entityKind)...)
+		}
+	case yaml.ScalarNode:
+		if isLiteralNode(node) {
+			values = append(values, literalValue{key, name, entityKind, node})
+		}
+	}
+	return values
+}
+
+// isLiteralNode returns true if the node is a scalar value which is not empty and doesn't contain expressions
+func isLiteralNode(node *yaml.Node) bool {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" || node.Value == "" {
+		return false
+	}
+	parsed := expression.Parse(node.Value)
+	return !parsed.HasExpressions(expression.Placeholder) && !parsed.HasExpressions(expression.Variable)
+}
+
+// isSecretKey returns true if the name of the key is the name of a secret
+func isSecretKey(key string) bool {
+	name := strings.ToLower(strings.NewReplacer("-", "", "_", "", ".", "").Replace(key))
+	if secretKeyExactNames[name] {
+		return true
+	}
+	for _, suffix := range nonSecretKeySuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	for _, secretName := range secretKeyNames {
+		if strings.Contains(name, secretName) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRandomString returns true if the value looks like a generated password, key or token: a long string
+// without whitespaces and with several character classes, whose entropy is high
+func isRandomString(value string) bool {
+	if len(value) < minSecretLength || strings.Contains(value, "://") {
+		return false
+	}
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range value {
+		switch {
+		case unicode.IsSpace(r):
+			return false
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+	classes := 0
+	for _, hasClass := range []bool{hasLower, hasUpper, hasDigit, hasSymbol} {
+		if hasClass {
+			classes++
+		}
+	}
+	return classes >= minSecretCharClasses && getShannonEntropy(value) >= minSecretEntropy
+}
+
+// getShannonEntropy returns the Shannon entropy of the value in bits per character
+func getShannonEntropy(value string) float64 {
+	counts := make(map[rune]int)
+	length := 0
+	for _, r := range value {
+		counts[r]++
+		length++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		probability := float64(count) / float64(length)
+		entropy -= probability * math.Log2(probability)
+	}
+	return entropy
+}
@@ -0,0 +1,135 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("secrets semantic validations", func() {
+	getAllowlist := func() *secretsAllowlist {
+		config, err := LoadLintConfig(getTestPath("secretsProject"))
+		Ω(err).Should(Succeed())
+		return config.getSecretsAllowlist()
+	}
+
+	getSecretsIssues := func(check checkSemantic, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		content, err := fs.ReadFile(getTestPath("secretsProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		return check(mtaStr, root, getTestPath("secretsProject"), strict)
+	}
+
+	It("reports the literal values of the keys with the names of secrets, except the allowed keys and values", func() {
+		errors, warnings := getSecretsIssues(getAllowlist().checkSecretKeys, true)
+		Ω(errors).Should(BeEmpty())
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(secretKeyMsg, "api-key", parameterEntityKind, moveSecretRecommendation), Line: 6, Column: 12},
+			YamlValidationIssue{Msg: fmt.Sprintf(secretKeyMsg, "DB_PASSWORD", propertyEntityKind, moveSecretRecommendation), Line: 12, Column: 19},
+			YamlValidationIssue{Msg: fmt.Sprintf(secretKeyMsg, "config.credentials.tokens[0]", parameterEntityKind, moveSecretRecommendation), Line: 40, Column: 13},
+		))
+	})
+
+	It("reports the literal values which are random strings", func() {
+		errors, warnings := getSecretsIssues(getAllowlist().checkHighEntropyValues, true)
+		Ω(errors).Should(BeEmpty())
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(highEntropyValueMsg, "signing-key", propertyEntityKind, moveSecretRecommendation), Line: 16, Column: 19},
+			YamlValidationIssue{Msg: fmt.Sprintf(highEntropyValueMsg, "config.credentials.tokens[0]", parameterEntityKind, moveSecretRecommendation), Line: 40, Column: 13},
+		))
+	})
+
+	It("reports the literal values of the sensitive keys", func() {
+		errors, warnings := getSecretsIssues(getAllowlist().checkSensitiveLiterals, true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(sensitiveLiteralMsg, "license", parameterEntityKind, moveSecretRecommendation), Line: 22, Column: 15},
+		))
+		Ω(warnings).Should(BeEmpty())
+
+		errors, warnings = getSecretsIssues(getAllowlist().checkSensitiveLiterals, false)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(HaveLen(1))
+	})
+
+	It("reports the secrets in the MTA extension descriptors", func() {
+		mtaYamlPath := getTestPath("secretsProject", "mta.yaml")
+		devExtPath := getTestPath("secretsProject", "dev.mtaext")
+		result := Validate(mtaYamlPath, []string{devExtPath})
+		Ω(result[devExtPath]).Should(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{
+				"Severity": Equal(SeverityWarning),
+				"Message":  Equal(fmt.Sprintf(secretKeyMsg, "DB_PASSWORD", propertyEntityKind, extSecretRecommendation)),
+				"Line":     Equal(8),
+				"Column":   Equal(19),
+				"Rule":     Equal(secretKeysValidation),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Severity": Equal(SeverityError),
+				"Message":  Equal(fmt.Sprintf(sensitiveLiteralMsg, "license", parameterEntityKind, extSecretRecommendation)),
+				"Line":     Equal(10),
+				"Column":   Equal(15),
+				"Rule":     Equal(sensitiveLiteralsValidation),
+			}),
+		))
+	})
+
+	It("doesn't run the secrets rules unless they are enabled in the lint configuration", func() {
+		content, err := fs.ReadFile(getTestPath("secretsProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		errors, warnings := runSemanticValidations(mtaStr, root, getTestPath("secretsProject"), "", true, nil)
+		for _, issue := range append(errors, warnings...) {
+			Ω(secretsRules).ShouldNot(ContainElement(issue.Rule))
+		}
+	})
+
+	It("doesn't report the values in the allowlist of the lint configuration which runs the rule", func() {
+		rule, ok := GetRule(secretKeysValidation)
+		Ω(ok).Should(BeTrue())
+		content, err := fs.ReadFile(getTestPath("secretsProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		config := &LintConfig{Secrets: SecretsConfig{Allowlist: SecretsAllowlist{Keys: []string{"*"}}}}
+		_, warnings := configureRule(rule, config.withDefaultRules(RulesConfig{})).(MtaRule).Check(nil, root, "", true)
+		Ω(warnings).Should(BeEmpty())
+		_, warnings = rule.(MtaRule).Check(nil, root, "", true)
+		Ω(warnings).ShouldNot(BeEmpty())
+	})
+
+	var _ = DescribeTable("isSecretKey", func(key string, expected bool) {
+		Ω(isSecretKey(key)).Should(Equal(expected))
+	},
+		Entry("password", "password", true),
+		Entry("client secret in camel case", "clientSecret", true),
+		Entry("access key with separators", "AWS_ACCESS_KEY", true),
+		Entry("exact short name", "pwd", true),
+		Entry("short name in another word", "cwd", false),
+		Entry("URL of a token", "token-url", false),
+		Entry("name of a secret", "secret-name", false),
+		Entry("other key", "memory", false),
+	)
+
+	var _ = DescribeTable("isRandomString", func(value string, expected bool) {
+		Ω(isRandomString(value)).Should(Equal(expected))
+	},
+		Entry("generated key", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY", true),
+		Entry("short value", "aB3$xY9!", false),
+		Entry("name", "MyApplicationServiceName2024", false),
+		Entry("lowercase identifier", "org.cloudfoundry.managed-service", false),
+		Entry("URL", "https://Ab1Cd2Ef3Gh4Ij5Kl6.example.com/Mn7Op8", false),
+		Entry("text with whitespaces", "The Quick Brown Fox 1234 Jumps Over", false),
+	)
+})
@@ -0,0 +1,306 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	securityDescriptorIssueMsg      = `the "%s" security descriptor of the "%s" resource is not valid; line %d: %s`
+	securityDescriptorNotObjectMsg  = `the security descriptor must be a JSON object`
+	securityFieldTypeMsg            = `the "%s" field must be %s`
+	securityTenantModeMsg           = `the "%s" tenant mode is not supported; expected one of the following: dedicated, shared, external`
+	securityMissingNameMsg          = `the "%s" field does not have a name`
+	securityDuplicateNameMsg        = `the "%s" name is not unique in the "%s" field`
+	securityUndefinedScopeMsg       = `the "%s" role template references the "%s" scope, which is not defined in the "scopes" field`
+	securityXsappnameMismatchMsg    = `the "%s" xsappname in the config parameter of the "%s" resource overrides the "%s" xsappname of the security descriptor`
+	securityMtaXsappnameMismatchMsg = `the "%s" xsappname in the config parameter overrides the "%s" xsappname of the "%s" security descriptor`
+
+	xsuaaService             = "xsuaa"
+	xsuaaTypePrefix          = "com.sap.xs.uaa"
+	serviceParameter         = "service"
+	xsappnameField           = "xsappname"
+	tenantModeField          = "tenant-mode"
+	scopesField              = "scopes"
+	attributesField          = "attributes"
+	roleTemplatesField       = "role-templates"
+	roleCollectionsField     = "role-collections"
+	scopeReferencesField     = "scope-references"
+	attributeRefsField       = "attribute-references"
+	roleTemplateRefsField    = "role-template-references"
+	oauth2ConfigurationField = "oauth2-configuration"
+	authoritiesField         = "authorities"
+	foreignScopeRefsField    = "foreign-scope-references"
+	xsappnameScopePrefix     = "$XSAPPNAME."
+
+	jsonStringKind       = "a string"
+	jsonObjectKind       = "an object"
+	jsonObjectsArrayKind = "an array of objects"
+	jsonStringsArrayKind = "an array of strings"
+)
+
+// securityFieldKinds are the kinds of the fields of the security descriptor which are validated
+var securityFieldKinds = map[string]string{
+	xsappnameField:           jsonStringKind,
+	tenantModeField:          jsonStringKind,
+	scopesField:              jsonObjectsArrayKind,
+	attributesField:          jsonObjectsArrayKind,
+	roleTemplatesField:       jsonObjectsArrayKind,
+	roleCollectionsField:     jsonObjectsArrayKind,
+	oauth2ConfigurationField: jsonObjectKind,
+	authoritiesField:         jsonStringsArrayKind,
+	foreignScopeRefsField:    jsonStringsArrayKind,
+}
+
+// securityElementFieldKinds are the kinds of the fields of the elements of the arrays in the security descriptor
+var securityElementFieldKinds = map[string]map[string]string{
+	roleTemplatesField:   {scopeReferencesField: jsonStringsArrayKind, attributeRefsField: jsonObjectsArrayKind},
+	roleCollectionsField: {roleTemplateRefsField: jsonStringsArrayKind},
+}
+
+var tenantModes = map[string]bool{"dedicated": true, "shared": true, "external": true}
+
+// securityDescriptor is the security descriptor (xs-security.json) in the path parameter of an xsuaa resource
+type securityDescriptor struct {
+	resource *mta.Resource
+	// path - the path parameter of the resource
+	path string
+	// filePath - the path of the file in the project folder
+	filePath string
+	content  []byte
+	root     *yaml.Node
+}
+
+// securityIssue is an issue in a security descriptor, on its node in the descriptor
+type securityIssue struct {
+	msg  string
+	node *yaml.Node
+	// mtaMsg - the message of the issue in the MTA descriptor, for an issue on the config parameter of the resource;
+	// it is reported on the xsappname in the config parameter and for each resource which references the descriptor
+	mtaMsg  string
+	warning bool
+}
+
+// checkSecurityDescriptors validates the security descriptors of the xsuaa resources: their structure, the uniqueness
+// of the scopes and the role templates, the scopes referenced by the role templates and the xsappname which the config
+// parameter overrides. The issues are reported on the path parameter of the resource, or on the xsappname in its config.
+// The security descriptors which don't exist or are not valid JSON files are reported by the file references rule.
+func checkSecurityDescriptors(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	for _, descriptor := range getSecurityDescriptors(mta, source) {
+		resourceNode := getEntityNode(mtaNode, []entityStep{{resourcesYamlField, descriptor.resource.Name}})
+		if resourceNode == nil {
+			continue
+		}
+		parametersNode := getPropValueByName(resourceNode, parametersYamlField)
+		// The path parameter can be inherited from the type of the resource
+		pathNode := getPropValueByName(parametersNode, configPathParameter)
+		if pathNode == nil {
+			pathNode = getPropValueByName(resourceNode, nameYamlField)
+		}
+		for _, issue := range descriptor.getIssues() {
+			node := pathNode
+			msg := fmt.Sprintf(securityDescriptorIssueMsg, descriptor.path, descriptor.resource.Name, issue.node.Line, issue.msg)
+			if issue.mtaMsg != "" {
+				if xsappnameNode := getPropValueByName(getPropValueByName(parametersNode, configParameter), xsappnameField); xsappnameNode != nil {
+					node = xsappnameNode
+				}
+				msg = issue.mtaMsg
+			}
+			if issue.warning || !strict {
+				warnings = appendNodeIssue(warnings, msg, node)
+			} else {
+				errors = appendNodeIssue(errors, msg, node)
+			}
+		}
+	}
+	return errors, warnings
+}
+
+// validateSecurityDescriptors validates the security descriptors of the xsuaa resources and returns the issues
+// by the paths of the descriptors. The lint configuration applies to the issues as in the validation of the descriptors.
+func validateSecurityDescriptors(mtaStr *mta.MTA, projectPath string, config *LintConfig) map[string]*fileIssues {
+	if parseExcludedRules(config.getExcludedRules(""))[securityDescriptorValidation] {
+		return nil
+	}
+	if effectiveMta, err := mtaStr.Effective(); err == nil {
+		mtaStr = effectiveMta
+	}
+
+	result := make(map[string]*fileIssues)
+	descriptors := make(map[string]*securityDescriptor)
+	for _, descriptor := range getSecurityDescriptors(mtaStr, projectPath) {
+		// The structure of a descriptor which is referenced by several resources is reported once
+		_, reported := descriptors[descriptor.filePath]
+		descriptors[descriptor.filePath] = descriptor
+		for _, issue := range descriptor.getIssues() {
+			if reported && issue.mtaMsg == "" {
+				continue
+			}
+			issues := result[descriptor.filePath]
+			if issues == nil {
+				issues = &fileIssues{}
+				result[descriptor.filePath] = issues
+			}
+			issueWithRule := YamlValidationIssue{Msg: issue.msg, Line: issue.node.Line, Column: issue.node.Column, node: issue.node, Rule: securityDescriptorValidation}
+			if issue.warning {
+				issues.warns = append(issues.warns, issueWithRule)
+			} else {
+				issues.errs = append(issues.errs, issueWithRule)
+			}
+		}
+	}
+
+	for path, issues := range result {
+		descriptor := descriptors[path]
+		issues.errs, issues.warns, issues.infos = config.applyLintConfig(getRelativePath(projectPath, path), descriptor.root, issues.errs, issues.warns)
+		issues.errs.Sort()
+		issues.warns.Sort()
+		issues.infos.Sort()
+		setIssueRanges(descriptor.content, descriptor.root, &issues.errs, &issues.warns, &issues.infos)
+	}
+	return result
+}
+
+// getSecurityDescriptors returns the security descriptors of the xsuaa resources which exist and are valid JSON files.
+// The paths are relative to the project folder.
+func getSecurityDescriptors(mta *mta.MTA, projectPath string) []*securityDescriptor {
+	var descriptors []*securityDescriptor
+	for _, resource := range mta.Resources {
+		if resource.Parameters[serviceParameter] != xsuaaService && !strings.HasPrefix(resource.Type, xsuaaTypePrefix) {
+			continue
+		}
+		path, ok := resource.Parameters[configPathParameter].(string)
+		if !ok || path == "" {
+			continue
+		}
+		filePath := path
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(projectPath, filePath)
+		}
+		content, err := fs.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		root, err := getJSONContentNode(content)
+		if err != nil {
+			continue
+		}
+		descriptors = append(descriptors, &securityDescriptor{resource, path, filePath, content, root})
+	}
+	return descriptors
+}
+
+// getIssues returns the issues in the security descriptor
+func (d *securityDescriptor) getIssues() []securityIssue {
+	if d.root.Kind != yaml.MappingNode {
+		return []securityIssue{{msg: securityDescriptorNotObjectMsg, node: d.root}}
+	}
+
+	var issues []securityIssue
+	for i := 0; i+1 < len(d.root.Content); i += 2 {
+		field, value := d.root.Content[i].Value, d.root.Content[i+1]
+		if kind, ok := securityFieldKinds[field]; ok && !isJSONKind(value, kind) {
+			issues = append(issues, securityIssue{msg: fmt.Sprintf(securityFieldTypeMsg, field, kind), node: value})
+		}
+	}
+	if tenantMode := getPropValueByName(d.root, tenantModeField); isJSONKind(tenantMode, jsonStringKind) && !tenantModes[tenantMode.Value] {
+		issues = append(issues, securityIssue{msg: fmt.Sprintf(securityTenantModeMsg, tenantMode.Value), node: tenantMode})
+	}
+
+	scopes := make(map[string]bool)
+	for _, field := range []string{scopesField, attributesField, roleTemplatesField, roleCollectionsField} {
+		names := make(map[string]bool)
+		for i, element := range getSecurityElements(d.root, field) {
+			elementField := fmt.Sprintf("%s[%d]", field, i)
+			for name, kind := range securityElementFieldKinds[field] {
+				if value := getPropValueByName(element, name); value != nil && !isJSONKind(value, kind) {
+					issues = append(issues, securityIssue{msg: fmt.Sprintf(securityFieldTypeMsg, elementField+"."+name, kind), node: value})
+				}
+			}
+			nameNode := getPropValueByName(element, nameYamlField)
+			if !isJSONKind(nameNode, jsonStringKind) || nameNode.Value == "" {
+				issues = append(issues, securityIssue{msg: fmt.Sprintf(securityMissingNameMsg, elementField), node: element})
+				continue
+			}
+			if names[nameNode.Value] && (field == scopesField || field == roleTemplatesField) {
+				issues = append(issues, securityIssue{msg: fmt.Sprintf(securityDuplicateNameMsg, nameNode.Value, field), node: nameNode})
+			}
+			names[nameNode.Value] = true
+		}
+		if field == scopesField {
+			scopes = names
+		}
+	}
+
+	// The references to the scopes of other applications and to the built-in scopes are not checked
+	for _, roleTemplate := range getSecurityElements(d.root, roleTemplatesField) {
+		nameNode := getPropValueByName(roleTemplate, nameYamlField)
+		references := getPropValueByName(roleTemplate, scopeReferencesField)
+		if nameNode == nil || !isJSONKind(references, jsonStringsArrayKind) {
+			continue
+		}
+		for _, reference := range references.Content {
+			if strings.HasPrefix(reference.Value, xsappnameScopePrefix) && !scopes[reference.Value] {
+				issues = append(issues, securityIssue{msg: fmt.Sprintf(securityUndefinedScopeMsg, nameNode.Value, reference.Value), node: reference})
+			}
+		}
+	}
+
+	// The config parameter of the resource is merged over the descriptor
+	if config, ok := d.resource.Parameters[configParameter].(map[string]interface{}); ok {
+		xsappname, ok := config[xsappnameField].(string)
+		if xsappnameNode := getPropValueByName(d.root, xsappnameField); ok && isJSONKind(xsappnameNode, jsonStringKind) && xsappnameNode.Value != xsappname {
+			issues = append(issues, securityIssue{
+				msg:     fmt.Sprintf(securityXsappnameMismatchMsg, xsappname, d.resource.Name, xsappnameNode.Value),
+				node:    xsappnameNode,
+				mtaMsg:  fmt.Sprintf(securityMtaXsappnameMismatchMsg, xsappname, xsappnameNode.Value, d.path),
+				warning: true,
+			})
+		}
+	}
+	return issues
+}
+
+// getSecurityElements returns the elements of the array field of the descriptor which are objects
+func getSecurityElements(root *yaml.Node, field string) []*yaml.Node {
+	var elements []*yaml.Node
+	for _, element := range getPropContent(root, field) {
+		if element.Kind == yaml.MappingNode {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// isJSONKind returns true if the node is a JSON value of the kind
+func isJSONKind(node *yaml.Node, kind string) bool {
+	if node == nil {
+		return false
+	}
+	switch kind {
+	case jsonStringKind:
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!str"
+	case jsonObjectKind:
+		return node.Kind == yaml.MappingNode
+	case jsonObjectsArrayKind, jsonStringsArrayKind:
+		if node.Kind != yaml.SequenceNode {
+			return false
+		}
+		elementKind := jsonObjectKind
+		if kind == jsonStringsArrayKind {
+			elementKind = jsonStringKind
+		}
+		for _, element := range node.Content {
+			if !isJSONKind(element, elementKind) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
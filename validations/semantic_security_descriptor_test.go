@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("security descriptor semantic validations", func() {
+	descriptorIssue := func(line int, msg string) string {
+		return fmt.Sprintf(securityDescriptorIssueMsg, "./xs-security.json", "uaa", line, msg)
+	}
+
+	getSecurityIssues := func(strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		content, err := fs.ReadFile(getTestPath("securityProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		return checkSecurityDescriptors(mtaStr, root, getTestPath("securityProject"), strict)
+	}
+
+	It("reports the issues in the security descriptors of the xsuaa resources on their path parameters", func() {
+		errors, warnings := getSecurityIssues(true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: descriptorIssue(3, fmt.Sprintf(securityTenantModeMsg, "private")), Line: 11, Column: 12},
+			YamlValidationIssue{Msg: descriptorIssue(6, fmt.Sprintf(securityDuplicateNameMsg, "$XSAPPNAME.Read", scopesField)), Line: 11, Column: 12},
+			YamlValidationIssue{Msg: descriptorIssue(11, fmt.Sprintf(securityUndefinedScopeMsg, "Viewer", "$XSAPPNAME.Write")), Line: 11, Column: 12},
+			YamlValidationIssue{Msg: descriptorIssue(15, fmt.Sprintf(securityMissingNameMsg, "role-collections[0]")), Line: 11, Column: 12},
+			YamlValidationIssue{Msg: descriptorIssue(17, fmt.Sprintf(securityFieldTypeMsg, oauth2ConfigurationField, jsonObjectKind)), Line: 11, Column: 12},
+		))
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(securityMtaXsappnameMismatchMsg, "other-app", "app", "./xs-security.json"), Line: 13, Column: 19},
+		))
+	})
+
+	It("returns all the issues as warnings in non-strict mode", func() {
+		errors, warnings := getSecurityIssues(false)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(HaveLen(6))
+	})
+
+	It("validates the structure of the security descriptor", func() {
+		getMessages := func(json string) []string {
+			root, err := getJSONContentNode([]byte(json))
+			Ω(err).Should(Succeed())
+			descriptor := &securityDescriptor{resource: &mta.Resource{Name: "uaa"}, path: "xs-security.json", root: root}
+			var messages []string
+			for _, issue := range descriptor.getIssues() {
+				messages = append(messages, issue.msg)
+			}
+			return messages
+		}
+		Ω(getMessages(`[]`)).Should(ConsistOf(securityDescriptorNotObjectMsg))
+		Ω(getMessages(`{"xsappname": 1, "scopes": [{"name": "a"}, "b"], "role-templates": [{"name": "r", "scope-references": "a"}]}`)).Should(ConsistOf(
+			fmt.Sprintf(securityFieldTypeMsg, xsappnameField, jsonStringKind),
+			fmt.Sprintf(securityFieldTypeMsg, scopesField, jsonObjectsArrayKind),
+			fmt.Sprintf(securityFieldTypeMsg, "role-templates[0].scope-references", jsonStringsArrayKind),
+		))
+	})
+
+	It("reports the issues on the security descriptors in the validation of the project", func() {
+		mtaPath := getTestPath("securityProject", "mta.yaml")
+		descriptorPath := getTestPath("securityProject", "xs-security.json")
+		result := Validate(mtaPath, nil)
+		Ω(result).Should(HaveKey(mtaPath))
+		Ω(result).Should(HaveLen(2))
+		Ω(result[descriptorPath]).Should(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{"Severity": Equal(SeverityError), "Line": Equal(3), "Column": Equal(17),
+				"Message": Equal(fmt.Sprintf(securityTenantModeMsg, "private")), "Rule": Equal(securityDescriptorValidation)}),
+			MatchFields(IgnoreExtras, Fields{"Severity": Equal(SeverityError), "Line": Equal(6), "Column": Equal(13),
+				"Message": Equal(fmt.Sprintf(securityDuplicateNameMsg, "$XSAPPNAME.Read", scopesField))}),
+			MatchFields(IgnoreExtras, Fields{"Severity": Equal(SeverityError), "Line": Equal(11), "Column": Equal(44),
+				"Message": Equal(fmt.Sprintf(securityUndefinedScopeMsg, "Viewer", "$XSAPPNAME.Write"))}),
+			MatchFields(IgnoreExtras, Fields{"Severity": Equal(SeverityError), "Line": Equal(15), "Column": Equal(3),
+				"Message": Equal(fmt.Sprintf(securityMissingNameMsg, "role-collections[0]"))}),
+			MatchFields(IgnoreExtras, Fields{"Severity": Equal(SeverityError), "Line": Equal(17), "Column": Equal(26),
+				"Message": Equal(fmt.Sprintf(securityFieldTypeMsg, oauth2ConfigurationField, jsonObjectKind))}),
+			MatchFields(IgnoreExtras, Fields{"Severity": Equal(SeverityWarning), "Line": Equal(2), "Column": Equal(15),
+				"Message": Equal(fmt.Sprintf(securityXsappnameMismatchMsg, "other-app", "uaa", "app"))}),
+		))
+	})
+})
@@ -0,0 +1,207 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	missingTypeParameterMsg     = `the "%s" %s does not define the "%s" parameter, which the "%s" type requires`
+	unsupportedTypeParameterMsg = `the "%s" parameter is not supported by the "%s" type of the "%s" %s`
+	deprecatedTypeParameterMsg  = `the "%s" parameter of the "%s" %s is deprecated; use the "%s" parameter instead`
+	removedTypeParameterMsg     = `the "%s" parameter of the "%s" %s is deprecated`
+	missingTypeConfigKeyMsg     = `the "%s" %s does not define the "%s" key in the "config" parameter, which the "%s" type requires`
+	unsupportedTypeConfigKeyMsg = `the "%s" key of the "config" parameter is not supported by the "%s" type of the "%s" %s`
+	deprecatedTypeConfigKeyMsg  = `the "%s" key of the "config" parameter of the "%s" %s is deprecated; use the "%s" key instead`
+	removedTypeConfigKeyMsg     = `the "%s" key of the "config" parameter of the "%s" %s is deprecated`
+	typeYamlField               = "type"
+	moduleTypesYamlField        = "module-types"
+	resourceTypesYamlField      = "resource-types"
+	configParameter             = "config"
+	configPathParameter         = "path"
+	invalidTypesCatalogIssueMsg = `the types catalog is not valid: %s`
+)
+
+// declaredType is a module type or a resource type which is declared in the MTA
+type declaredType struct {
+	extends    string
+	parameters map[string]interface{}
+}
+
+// typedEntity is a module or a resource with the description of its type in the catalog
+type typedEntity struct {
+	kind        string
+	name        string
+	typeName    string
+	parameters  map[string]interface{}
+	node        *yaml.Node
+	catalogType *CatalogType
+	common      CatalogParameters
+	// inherited - the parameters which the entity inherits from the types declared in the MTA
+	inherited map[string]interface{}
+}
+
+// checkTypes checks the parameters of the modules and the resources against the types catalog.
+// The types declared in the MTA supply their parameters to the entities of these types, and their extended types are checked.
+func checkTypes(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	catalog, err := GetTypesCatalog(source)
+	if err != nil {
+		return appendIssue(nil, fmt.Sprintf(invalidTypesCatalogIssueMsg, err.Error()), 0, 0), nil
+	}
+
+	moduleTypes := make(map[string]declaredType)
+	for _, moduleType := range mta.ModuleTypes {
+		moduleTypes[moduleType.Name] = declaredType{moduleType.Extends, moduleType.Parameters}
+	}
+	resourceTypes := make(map[string]declaredType)
+	for _, resourceType := range mta.ResourceTypes {
+		resourceTypes[resourceType.Name] = declaredType{resourceType.Extends, resourceType.Parameters}
+	}
+
+	var entities []typedEntity
+	modulesNode := getPropContent(mtaNode, modulesYamlField)
+	for i, module := range mta.Modules {
+		if i < len(modulesNode) {
+			catalogType, inherited := resolveCatalogType(module.Type, moduleTypes, catalog.GetModuleType)
+			entities = append(entities, typedEntity{moduleEntityKind, module.Name, module.Type, module.Parameters,
+				modulesNode[i], catalogType, catalog.ModuleParameters, inherited})
+		}
+	}
+	resourcesNode := getPropContent(mtaNode, resourcesYamlField)
+	for i, resource := range mta.Resources {
+		if i < len(resourcesNode) {
+			catalogType, inherited := resolveCatalogType(resource.Type, resourceTypes, catalog.GetResourceType)
+			entities = append(entities, typedEntity{resourceEntityKind, resource.Name, resource.Type, resource.Parameters,
+				resourcesNode[i], catalogType, catalog.ResourceParameters, inherited})
+		}
+	}
+
+	for _, entity := range entities {
+		if entity.catalogType == nil {
+			continue
+		}
+		errs, warns := checkTypedEntity(entity)
+		errors = append(errors, errs...)
+		warnings = append(warnings, warns...)
+	}
+
+	if strict {
+		return errors, warnings
+	}
+	return nil, append(errors, warnings...)
+}
+
+// resolveCatalogType returns the catalog type of the entities of the type, and the parameters which they inherit from
+// the types declared in the MTA. A declared type takes precedence over the catalog type with the same name,
+// and is described by the catalog type which it extends, directly or through other declared types.
+// It returns nil if the type is not in the catalog and doesn't extend a type in the catalog.
+func resolveCatalogType(typeName string, declaredTypes map[string]declaredType, getType func(string) *CatalogType) (*CatalogType, map[string]interface{}) {
+	inherited := make(map[string]interface{})
+	visited := make(map[string]bool)
+	for name := typeName; name != "" && !visited[name]; {
+		visited[name] = true
+		declared, ok := declaredTypes[name]
+		if !ok {
+			return getType(name), inherited
+		}
+		for key, value := range declared.parameters {
+			if _, ok := inherited[key]; !ok {
+				inherited[key] = value
+			}
+		}
+		name = declared.extends
+	}
+	return nil, inherited
+}
+
+// checkTypedEntity returns the missing required parameters of the entity as errors, and the unsupported
+// and deprecated parameters as warnings. The "config" parameter is checked if the catalog type describes it.
+func checkTypedEntity(entity typedEntity) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	typeNode := getPropValueByName(entity.node, typeYamlField)
+	if typeNode == nil {
+		typeNode = entity.node
+	}
+	params := &entity.catalogType.Parameters
+	for _, key := range append(append([]string{}, entity.common.Required...), params.Required...) {
+		_, defined := entity.parameters[key]
+		_, inherited := entity.inherited[key]
+		if !defined && !inherited {
+			errors = appendNodeIssue(errors, fmt.Sprintf(missingTypeParameterMsg, entity.name, entity.kind, key, entity.typeName), typeNode)
+		}
+	}
+
+	paramsNode := getPropValueByName(entity.node, parametersYamlField)
+	warnings = append(warnings, checkTypeKeys(entity, paramsNode, params, entity.common,
+		unsupportedTypeParameterMsg, deprecatedTypeParameterMsg, removedTypeParameterMsg)...)
+
+	config := entity.catalogType.Config
+	if config == nil {
+		return errors, warnings
+	}
+	configNode := getPropValueByName(paramsNode, configParameter)
+	if configNode != nil && configNode.Kind != yaml.MappingNode {
+		// The config is a variable or a placeholder
+		return errors, warnings
+	}
+	if _, ok := entity.parameters[configPathParameter]; !ok {
+		// The keys which are not in the config can be in the file in the path parameter
+		for _, key := range config.Required {
+			if getPropByName(configNode, key) == nil {
+				errors = appendNodeIssue(errors, fmt.Sprintf(missingTypeConfigKeyMsg, entity.name, entity.kind, key, entity.typeName), typeNode)
+			}
+		}
+	}
+	warnings = append(warnings, checkTypeKeys(entity, configNode, config, CatalogParameters{},
+		unsupportedTypeConfigKeyMsg, deprecatedTypeConfigKeyMsg, removedTypeConfigKeyMsg)...)
+	return errors, warnings
+}
+
+// checkTypeKeys returns the issues on the keys of the map which are deprecated or not supported by the parameters description
+func checkTypeKeys(entity typedEntity, mapNode *yaml.Node, params *CatalogParameters, common CatalogParameters,
+	unsupportedMsg, deprecatedMsg, removedMsg string) []YamlValidationIssue {
+	if mapNode == nil || mapNode.Kind != yaml.MappingNode {
+		return nil
+	}
+	var issues []YamlValidationIssue
+	for i := 0; i+1 < len(mapNode.Content); i += 2 {
+		keyNode := mapNode.Content[i]
+		key := keyNode.Value
+		if replacement, ok := params.getDeprecation(key, common); ok {
+			if replacement == "" {
+				issues = appendNodeIssue(issues, fmt.Sprintf(removedMsg, key, entity.name, entity.kind), keyNode)
+			} else {
+				issues = appendNodeIssue(issues, fmt.Sprintf(deprecatedMsg, key, entity.name, entity.kind, replacement), keyNode)
+			}
+		} else if !params.isSupported(key, common) {
+			issues = appendNodeIssue(issues, fmt.Sprintf(unsupportedMsg, key, entity.typeName, entity.name, entity.kind), keyNode)
+		}
+	}
+	return issues
+}
+
+// getInheritanceIssue returns the issue of the error in the inheritance of the declared types. The issue is on the type
+// of the module or the resource which can't inherit its type, or on the name of the type which extends itself.
+func getInheritanceIssue(err error, root *yaml.Node) YamlValidationIssue {
+	issue := YamlValidationIssue{Msg: err.Error()}
+	inheritanceErr, ok := err.(*mta.InheritanceError)
+	if !ok {
+		return issue
+	}
+	var node *yaml.Node
+	switch inheritanceErr.Kind {
+	case mta.ModuleInheritanceKind:
+		node = getPropValueByName(getEntityNode(root, []entityStep{{modulesYamlField, inheritanceErr.Name}}), typeYamlField)
+	case mta.ResourceInheritanceKind:
+		node = getPropValueByName(getEntityNode(root, []entityStep{{resourcesYamlField, inheritanceErr.Name}}), typeYamlField)
+	case mta.ModuleTypeInheritanceKind:
+		node = getPropValueByName(getEntityNode(root, []entityStep{{moduleTypesYamlField, inheritanceErr.Name}}), nameYamlField)
+	case mta.ResourceTypeInheritanceKind:
+		node = getPropValueByName(getEntityNode(root, []entityStep{{resourceTypesYamlField, inheritanceErr.Name}}), nameYamlField)
+	}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	return issue
+}
@@ -0,0 +1,237 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("types semantic validations", func() {
+	getTypesIssues := func(content []byte, projectPath string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		return checkTypes(mtaStr, root, projectPath, strict)
+	}
+
+	It("checks the parameters of the modules and resources against the embedded catalog", func() {
+		errors, warnings := getTypesIssues([]byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: ui5app
+   type: html5
+   parameters:
+     keep-existing-routes: true
+ - name: custom
+   type: my.type
+   parameters:
+     keep-existing-routes: true
+
+resources:
+ - name: uaa
+   type: org.cloudfoundry.managed-service
+   parameters:
+     service: xsuaa
+     plan: application
+ - name: existing
+   type: org.cloudfoundry.existing-service
+ - name: hdi
+   type: com.sap.xs.hdi-container
+   parameters:
+     config:
+       schema: S
+       unknown: a
+`), "", true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(missingTypeParameterMsg, "uaa", resourceEntityKind, "service-plan", "org.cloudfoundry.managed-service"), Line: 18, Column: 10},
+			YamlValidationIssue{Msg: fmt.Sprintf(missingTypeParameterMsg, "existing", resourceEntityKind, "service-name", "org.cloudfoundry.existing-service"), Line: 23, Column: 10},
+		))
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(deprecatedTypeParameterMsg, "keep-existing-routes", "ui5app", moduleEntityKind, "keep-existing"), Line: 10, Column: 6},
+			YamlValidationIssue{Msg: fmt.Sprintf(unsupportedTypeParameterMsg, "plan", "org.cloudfoundry.managed-service", "uaa", resourceEntityKind), Line: 21, Column: 6},
+			YamlValidationIssue{Msg: fmt.Sprintf(unsupportedTypeConfigKeyMsg, "unknown", "com.sap.xs.hdi-container", "hdi", resourceEntityKind), Line: 29, Column: 8},
+		))
+	})
+
+	It("returns all the issues as warnings in non-strict mode", func() {
+		errors, warnings := getTypesIssues([]byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+resources:
+ - name: existing
+   type: org.cloudfoundry.existing-service
+   parameters:
+     unknown: a
+`), "", false)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(HaveLen(2))
+	})
+
+	It("takes into account the types declared in the MTA", func() {
+		errors, warnings := getTypesIssues([]byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+resources:
+ - name: db
+   type: hana-db
+ - name: cache
+   type: redis
+ - name: custom
+   type: my.custom
+ - name: cycle
+   type: a
+
+resource-types:
+ - name: hana-db
+   extends: managed-hana
+   parameters:
+     service-plan: hdi-shared
+ - name: managed-hana
+   extends: org.cloudfoundry.managed-service
+   parameters:
+     service: hana
+ - name: redis
+   extends: org.cloudfoundry.managed-service
+   parameters:
+     service: redis
+ - name: my.custom
+ - name: a
+   extends: b
+ - name: b
+   extends: a
+`), "", true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(missingTypeParameterMsg, "cache", resourceEntityKind, "service-plan", "redis"), Line: 10, Column: 10},
+		))
+		Ω(warnings).Should(BeEmpty())
+	})
+
+	It("uses the types catalog file of the project", func() {
+		mtaContent, err := fs.ReadFile(getTestPath("typesProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		errors, warnings := getTypesIssues(mtaContent, getTestPath("typesProject"), true)
+		Ω(withoutNodes(errors)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(missingTypeParameterMsg, "existing", resourceEntityKind, "service-name", "org.cloudfoundry.existing-service"), Line: 13, Column: 10},
+			YamlValidationIssue{Msg: fmt.Sprintf(missingTypeParameterMsg, "queue", resourceEntityKind, "queue-name", "com.acme.queue"), Line: 18, Column: 10},
+		))
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(removedTypeParameterMsg, "legacy-timeout", "srv", moduleEntityKind), Line: 9, Column: 6},
+		))
+	})
+
+	It("returns an error when the types catalog file of the project is not valid", func() {
+		catalogPath := getTestPath("typesProject", "badVersion", TypesCatalogFileName)
+		errors, warnings := getTypesIssues([]byte("ID: mta\n_schema-version: '3.2'\n"), getTestPath("typesProject", "badVersion"), false)
+		Ω(errors).Should(ConsistOf(YamlValidationIssue{
+			Msg:  fmt.Sprintf(invalidTypesCatalogIssueMsg, fmt.Sprintf(wrongTypesCatalogVersionMsg, 2, catalogPath, TypesCatalogVersion)),
+			Line: 0, Column: 0,
+		}))
+		Ω(warnings).Should(BeEmpty())
+	})
+
+	Describe("inheritance of the declared types", func() {
+		getSemanticIssues := func(content []byte, exclude string) ([]YamlValidationIssue, []YamlValidationIssue) {
+			mtaStr, err := mta.Unmarshal(content)
+			Ω(err).Should(Succeed())
+			root, err := getContentNode(content)
+			Ω(err).Should(Succeed())
+			// The modules don't have paths
+			return runSemanticValidations(mtaStr, root, "", "paths,emptyPath,"+exclude, true, nil)
+		}
+
+		It("validates the values which the modules inherit from their types", func() {
+			errors, warnings := getSemanticIssues([]byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: service
+   properties:
+     url: https://${api-host}.example.com
+ - name: other
+   type: nodejs
+   properties:
+     url: https://${api-host}.example.com
+
+module-types:
+ - name: service
+   extends: nodejs
+   parameters:
+     api-host: srv
+`), "")
+			Ω(errors).Should(BeEmpty())
+			Ω(warnings).Should(ConsistOf(YamlValidationIssue{
+				Msg:  fmt.Sprintf(undefinedPlaceholderParameterMsg, "url", propertyEntityKind, `"other" module`, "api-host"),
+				Line: 14, Column: 19, Rule: requiredValidation,
+			}))
+		})
+
+		It("reports the errors in the inheritance on the type of the module", func() {
+			errors, _ := getSemanticIssues([]byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: service
+   parameters:
+     buildpack: other
+
+module-types:
+ - name: service
+   parameters:
+     buildpack: nodejs_buildpack
+   parameters-metadata:
+     buildpack:
+       overwritable: false
+`), "")
+			Ω(errors).Should(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Msg":    ContainSubstring(`the "buildpack" field cannot be overwritten`),
+				"Line":   Equal(8),
+				"Column": Equal(10),
+				"Rule":   Equal(typesValidation),
+			})))
+		})
+
+		It("reports a type which extends itself on the name of the type, unless the types rule is excluded", func() {
+			content := []byte(`
+ID: mtahtml5
+_schema-version: '3.2'
+version: 0.0.1
+
+resources:
+ - name: db
+   type: a
+
+resource-types:
+ - name: a
+   extends: b
+ - name: b
+   extends: a
+`)
+			errors, _ := getSemanticIssues(content, "")
+			Ω(errors).Should(ConsistOf(YamlValidationIssue{
+				Msg:  `the "a" resource type extends itself in its "extends" chain`,
+				Line: 11, Column: 10, Rule: typesValidation,
+			}))
+			errors, _ = getSemanticIssues(content, typesValidation)
+			Ω(errors).Should(BeEmpty())
+		})
+	})
+})
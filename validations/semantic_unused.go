@@ -0,0 +1,237 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/expression"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	unusedResourceMsg         = `the "%s" resource is not required by any module, resource or hook`
+	unconsumedProvidesMsg     = `the "%s" property set provided by the "%s" module is not required by any module, resource or hook; set "public: true" if other MTAs require it`
+	unreferencedPropertyMsg   = `the "%s" property of the "%s" provided property set is not referenced by any requires section`
+	unreadProvidedOverrideMsg = `the "%s" property of the "%s" provided property set is overridden, but the property set is not required by any module, resource or hook`
+	unreadPropertyOverrideMsg = `the "%s" property of the "%s" provided property set is overridden, but it is not referenced by any requires section`
+	unreadResourceOverrideMsg = `the "%s" property of the "%s" resource is overridden, but the resource is not required by any module, resource or hook`
+	inactiveOverrideMsg       = `the "%s" %s of the "%s" resource is overridden, but the resource is not active`
+
+	propertySetSeparator = "/"
+)
+
+// unusedRules are the opt-in rules which report the configuration which nothing uses
+var unusedRules = []string{unusedResourcesValidation, unconsumedProvidesValidation, unreferencedPropertiesValidation, unreadOverridesValidation}
+
+// configUsages are the property sets which are required or referenced in the MTA, and their properties which are referenced
+type configUsages struct {
+	requiredSets    map[string]bool
+	referencedProps map[string]map[string]bool
+}
+
+// getConfigUsages returns the usages of the property sets in the values and in the requires sections of the MTA
+func getConfigUsages(mtaObj *mta.MTA) *configUsages {
+	usages := &configUsages{make(map[string]bool), make(map[string]map[string]bool)}
+	addRequires := func(requiresList []mta.Requires) {
+		for _, requires := range requiresList {
+			usages.requiredSets[requires.Name] = true
+			usages.addReferences(requires.Name, requires.Properties)
+			usages.addReferences(requires.Name, requires.Parameters)
+		}
+	}
+
+	usages.addReferences("", mtaObj.Parameters)
+	for _, module := range mtaObj.Modules {
+		usages.addReferences("", module.Properties)
+		usages.addReferences("", module.Parameters)
+		usages.addReferences("", module.BuildParams)
+		for _, provides := range module.Provides {
+			usages.addReferences("", provides.Properties)
+		}
+		addRequires(module.Requires)
+		for _, hook := range module.Hooks {
+			usages.addReferences("", hook.Parameters)
+			addRequires(hook.Requires)
+		}
+	}
+	for _, resource := range mtaObj.Resources {
+		usages.addReferences("", resource.Properties)
+		usages.addReferences("", resource.Parameters)
+		addRequires(resource.Requires)
+	}
+	return usages
+}
+
+// addReferences adds the variables in the string values in the value, in its maps and arrays at any depth.
+// In a requires section a variable references a property of the required set; elsewhere it has the form ~{set/property}.
+func (usages *configUsages) addReferences(requiredSet string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		// malformed expressions are reported by the expressions validation
+		for _, variable := range expression.Parse(v).Expressions(expression.Variable) {
+			if variable.DoubleBraced {
+				continue
+			}
+			set, prop := requiredSet, variable.Value
+			if set == "" {
+				parts := strings.SplitN(variable.Value, propertySetSeparator, 2)
+				if len(parts) != 2 {
+					continue
+				}
+				set, prop = parts[0], parts[1]
+			}
+			if usages.referencedProps[set] == nil {
+				usages.referencedProps[set] = make(map[string]bool)
+			}
+			usages.referencedProps[set][prop] = true
+		}
+	case map[string]interface{}:
+		for _, childValue := range v {
+			usages.addReferences(requiredSet, childValue)
+		}
+	case map[interface{}]interface{}:
+		for _, childValue := range v {
+			usages.addReferences(requiredSet, childValue)
+		}
+	case []interface{}:
+		for _, childValue := range v {
+			usages.addReferences(requiredSet, childValue)
+		}
+	}
+}
+
+// isUsed returns true if the property set is required or one of its properties is referenced
+func (usages *configUsages) isUsed(set string) bool {
+	return usages.requiredSets[set] || len(usages.referencedProps[set]) > 0
+}
+
+// checkUnusedResources checks that the active resources are required by modules, resources or hooks.
+// The inactive resources are not deployed, so they are not reported.
+func checkUnusedResources(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var warnings []YamlValidationIssue
+	usages := getConfigUsages(mta)
+	for _, resource := range mta.Resources {
+		if !resource.IsActive() || usages.isUsed(resource.Name) {
+			continue
+		}
+		nameNode := getPropValueByName(getEntityNode(mtaNode, []entityStep{{resourcesYamlField, resource.Name}}), nameYamlField)
+		if nameNode != nil {
+			warnings = appendNodeIssue(warnings, fmt.Sprintf(unusedResourceMsg, resource.Name), nameNode)
+		}
+	}
+	return nil, warnings
+}
+
+// checkUnconsumedProvides checks that the property sets provided by the modules are required by modules, resources or hooks.
+// The public property sets can be required by other MTAs, so they are not reported.
+func checkUnconsumedProvides(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var warnings []YamlValidationIssue
+	usages := getConfigUsages(mta)
+	for _, module := range mta.Modules {
+		for _, provides := range module.Provides {
+			if provides.Public || usages.isUsed(provides.Name) {
+				continue
+			}
+			providesNode := getEntityNode(mtaNode, []entityStep{{modulesYamlField, module.Name}, {providesYamlField, provides.Name}})
+			if nameNode := getPropValueByName(providesNode, nameYamlField); nameNode != nil {
+				warnings = appendNodeIssue(warnings, fmt.Sprintf(unconsumedProvidesMsg, provides.Name, module.Name), nameNode)
+			}
+		}
+	}
+	return nil, warnings
+}
+
+// checkUnreferencedProperties checks that the properties of the provided property sets are referenced by variables.
+// The properties of the public and the unconsumed property sets are not reported.
+func checkUnreferencedProperties(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var warnings []YamlValidationIssue
+	usages := getConfigUsages(mta)
+	for _, module := range mta.Modules {
+		for _, provides := range module.Provides {
+			if provides.Public || !usages.isUsed(provides.Name) {
+				continue
+			}
+			providesNode := getEntityNode(mtaNode, []entityStep{{modulesYamlField, module.Name}, {providesYamlField, provides.Name}})
+			propsNode := getPropValueByName(providesNode, propertiesYamlField)
+			for _, key := range getSortedKeys(provides.Properties) {
+				if usages.referencedProps[provides.Name][key] {
+					continue
+				}
+				if keyNode := getPropByName(propsNode, key); keyNode != nil {
+					warnings = appendNodeIssue(warnings, fmt.Sprintf(unreferencedPropertyMsg, key, provides.Name), keyNode)
+				}
+			}
+		}
+	}
+	return nil, warnings
+}
+
+// checkUnreadOverrides checks the values in the MTA extension descriptors which nothing reads in the merged MTA:
+// the properties of the provided property sets which are not consumed or referenced, the properties of the resources
+// which are not required and the values of the inactive resources. The issues are reported on the extension descriptors.
+func checkUnreadOverrides(mta *mta.MTA, descriptors []*MergedDescriptor, strict bool) (map[string][]YamlValidationIssue, map[string][]YamlValidationIssue) {
+	warnings := make(map[string][]YamlValidationIssue)
+	usages := getConfigUsages(mta)
+	for _, descriptor := range descriptors[1:] {
+		var issues []YamlValidationIssue
+		addKeys := func(mapNode *yaml.Node, getMsg func(key string) string) {
+			if mapNode == nil || mapNode.Kind != yaml.MappingNode {
+				return
+			}
+			for i := 0; i+1 < len(mapNode.Content); i += 2 {
+				keyNode := mapNode.Content[i]
+				if msg := getMsg(keyNode.Value); msg != "" {
+					issues = appendNodeIssue(issues, msg, keyNode)
+				}
+			}
+		}
+
+		for _, module := range mta.Modules {
+			for _, provides := range module.Provides {
+				if provides.Public {
+					continue
+				}
+				providesNode := getEntityNode(descriptor.Root, []entityStep{{modulesYamlField, module.Name}, {providesYamlField, provides.Name}})
+				addKeys(getPropValueByName(providesNode, propertiesYamlField), func(key string) string {
+					if !usages.isUsed(provides.Name) {
+						return fmt.Sprintf(unreadProvidedOverrideMsg, key, provides.Name)
+					}
+					if !usages.referencedProps[provides.Name][key] {
+						return fmt.Sprintf(unreadPropertyOverrideMsg, key, provides.Name)
+					}
+					return ""
+				})
+			}
+		}
+		for _, resource := range mta.Resources {
+			resourceNode := getEntityNode(descriptor.Root, []entityStep{{resourcesYamlField, resource.Name}})
+			if !resource.IsActive() {
+				addKeys(getPropValueByName(resourceNode, parametersYamlField), func(key string) string {
+					return fmt.Sprintf(inactiveOverrideMsg, key, parameterEntityKind, resource.Name)
+				})
+				addKeys(getPropValueByName(resourceNode, propertiesYamlField), func(key string) string {
+					return fmt.Sprintf(inactiveOverrideMsg, key, propertyEntityKind, resource.Name)
+				})
+			} else if !usages.isUsed(resource.Name) {
+				addKeys(getPropValueByName(resourceNode, propertiesYamlField), func(key string) string {
+					return fmt.Sprintf(unreadResourceOverrideMsg, key, resource.Name)
+				})
+			}
+		}
+		if len(issues) > 0 {
+			warnings[descriptor.Path] = issues
+		}
+	}
+	return nil, warnings
+}
+
+func getSortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,86 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("unused configuration semantic validations", func() {
+	getUnusedIssues := func(check checkSemantic, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		content, err := fs.ReadFile(getTestPath("unusedProject", "mta.yaml"))
+		Ω(err).Should(Succeed())
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		return check(mtaStr, root, getTestPath("unusedProject"), strict)
+	}
+
+	It("reports the active resources which are not required", func() {
+		errors, warnings := getUnusedIssues(checkUnusedResources, true)
+		Ω(errors).Should(BeEmpty())
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(unusedResourceMsg, "config"), Line: 38, Column: 10},
+			YamlValidationIssue{Msg: fmt.Sprintf(unusedResourceMsg, "unused"), Line: 44, Column: 10},
+		))
+	})
+
+	It("reports the provided property sets which are not required, unless they are public", func() {
+		errors, warnings := getUnusedIssues(checkUnconsumedProvides, true)
+		Ω(errors).Should(BeEmpty())
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(unconsumedProvidesMsg, "unconsumed", "srv"), Line: 14, Column: 13},
+		))
+	})
+
+	It("reports the properties of the consumed provided property sets which are not referenced", func() {
+		errors, warnings := getUnusedIssues(checkUnreferencedProperties, false)
+		Ω(errors).Should(BeEmpty())
+		Ω(withoutNodes(warnings)).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(unreferencedPropertyMsg, "unreferenced", "srv_api"), Line: 13, Column: 9},
+		))
+	})
+
+	It("reports the values in the MTA extension descriptors which nothing reads", func() {
+		mtaYamlPath := getTestPath("unusedProject", "mta.yaml")
+		devExtPath := getTestPath("unusedProject", "dev.mtaext")
+		issue := func(message string, line, column int) interface{} {
+			return MatchFields(IgnoreExtras, Fields{
+				"Severity": Equal(SeverityWarning),
+				"Message":  Equal(message),
+				"Line":     Equal(line),
+				"Column":   Equal(column),
+				"Rule":     Equal(unreadOverridesValidation),
+			})
+		}
+		result := Validate(mtaYamlPath, []string{devExtPath})
+		Ω(result[devExtPath]).Should(ConsistOf(
+			issue(fmt.Sprintf(unreadPropertyOverrideMsg, "unreferenced", "srv_api"), 11, 9),
+			issue(fmt.Sprintf(unreadProvidedOverrideMsg, "url", "unconsumed"), 14, 9),
+			issue(fmt.Sprintf(unreadResourceOverrideMsg, "key", "unused"), 20, 6),
+			issue(fmt.Sprintf(inactiveOverrideMsg, "service-plan", parameterEntityKind, "inactive"), 23, 6),
+		))
+		Ω(result[mtaYamlPath]).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Severity": Equal(SeverityWarning),
+			"Message":  Equal(fmt.Sprintf(unreferencedPropertyMsg, "unreferenced", "srv_api")),
+			"Rule":     Equal(unreferencedPropertiesValidation),
+		})))
+	})
+
+	It("doesn't run the unused configuration rules unless they are enabled in the lint configuration", func() {
+		mtaYamlPath := getTestPath("requiredProject", "mta.yaml")
+		devExtPath := getTestPath("requiredProject", "dev.mtaext")
+		result := Validate(mtaYamlPath, []string{devExtPath})
+		for _, issues := range result {
+			for _, issue := range issues {
+				Ω(unusedRules).ShouldNot(ContainElement(issue.Rule))
+			}
+		}
+	})
+})
@@ -2,7 +2,6 @@ package validate
 
 import (
 	"gopkg.in/yaml.v3"
-	"strings"
 
 	"github.com/SAP/cloud-mta/mta"
 )
@@ -40,15 +39,30 @@ const (
 	gruntOptsYamlField = "grunt-opts"
 	mavenOptsYamlField = "maven-opts"
 
-	pathsValidation               = "paths"
-	emptyPathValidation           = "emptyPath"
-	namesValidation               = "names"
-	requiredValidation            = "required"
-	buildersValidation            = "builders"
-	deprecatedOptsValidation      = "deprecatedOpts"
-	deployerConstrValidation      = "deployerConstraints"
-	metadataValidation            = "metadata"
-	ifNoSourceParamBoolValidation = "checkNoSourceParam"
+	pathsValidation                  = "paths"
+	emptyPathValidation              = "emptyPath"
+	namesValidation                  = "names"
+	requiredValidation               = "required"
+	buildersValidation               = "builders"
+	deprecatedOptsValidation         = "deprecatedOpts"
+	deployerConstrValidation         = "deployerConstraints"
+	metadataValidation               = "metadata"
+	ifNoSourceParamBoolValidation    = "checkNoSourceParam"
+	expressionsValidation            = "expressions"
+	datatypesValidation              = "datatypes"
+	typesValidation                  = "types"
+	securityDescriptorValidation     = "securityDescriptor"
+	fileReferencesValidation         = "fileReferences"
+	modulePathSandboxValidation      = "modulePathSandbox"
+	includePathSandboxValidation     = "includePathSandbox"
+	resourcePathSandboxValidation    = "resourcePathSandbox"
+	unusedResourcesValidation        = "unusedResources"
+	unconsumedProvidesValidation     = "unconsumedProvides"
+	unreferencedPropertiesValidation = "unreferencedProperties"
+	unreadOverridesValidation        = "unreadOverrides"
+	secretKeysValidation             = "secretKeys"
+	highEntropyValuesValidation      = "highEntropyValues"
+	sensitiveLiteralsValidation      = "sensitiveLiterals"
 
 	propertiesMtaField      = "Properties"
 	parametersMtaField      = "Parameters"
@@ -69,52 +83,51 @@ const (
 	html5ModuleType = "html5"
 )
 
-// runSemanticValidations - runs semantic validations
-func runSemanticValidations(mtaStr *mta.MTA, root *yaml.Node, source string, exclude string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+// runSemanticValidations - runs semantic validations; the rules which are disabled in the lint configuration don't run
+func runSemanticValidations(mtaStr *mta.MTA, root *yaml.Node, source string, exclude string, strict bool,
+	config *LintConfig) ([]YamlValidationIssue, []YamlValidationIssue) {
 	var errors []YamlValidationIssue
 	var warnings []YamlValidationIssue
+	exclude = config.getExcludedRules(exclude)
+
+	// The rules validate the effective MTA, in which the modules and resources inherit the values of their declared types.
+	// If the inheritance fails, the error is reported by the types rule and the rules validate the MTA as it is.
+	effectiveMta, err := mtaStr.Effective()
+	if err != nil {
+		effectiveMta = mtaStr
+		if !parseExcludedRules(exclude)[typesValidation] {
+			issues := setIssuesRule([]YamlValidationIssue{getInheritanceIssue(err, root)}, typesValidation)
+			if strict {
+				errors = append(errors, issues...)
+			} else {
+				warnings = append(warnings, issues...)
+			}
+		}
+	}
 
 	validations := getSemanticValidations(exclude)
-	for _, validation := range validations {
-		validationErrors, validationWarnings := validation(mtaStr, root, source, strict)
-		errors = append(errors, validationErrors...)
-		warnings = append(warnings, validationWarnings...)
-
+	for _, rule := range validations {
+		validation, ok := configureRule(rule, config).(MtaRule)
+		if !ok {
+			validation = rule
+		}
+		validationErrors, validationWarnings := validation.Check(effectiveMta, root, source, strict)
+		errors = append(errors, setIssuesRule(validationErrors, validation.ID())...)
+		warnings = append(warnings, setIssuesRule(validationWarnings, validation.ID())...)
 	}
 	return errors, warnings
 }
 
-// getSemanticValidations - gets list of all semantic validations minus excludes validations
-func getSemanticValidations(exclude string) []checkSemantic {
-	var validations []checkSemantic
-	if !strings.Contains(exclude, pathsValidation) {
-		validations = append(validations, ifModulePathExists)
-	}
-	if !strings.Contains(exclude, emptyPathValidation) {
-		validations = append(validations, ifModulePathEmpty)
-	}
-	if !strings.Contains(exclude, namesValidation) {
-		validations = append(validations, isNameUnique)
-	}
-	if !strings.Contains(exclude, requiredValidation) {
-		validations = append(validations, ifRequiredDefined)
-	}
-	if !strings.Contains(exclude, buildersValidation) {
-		validations = append(validations, checkBuildersSemantic)
+// getSemanticValidations - gets list of all registered MTA rules minus excluded rules;
+// exclude is a comma-separated list of rule IDs
+func getSemanticValidations(exclude string) []MtaRule {
+	var validations []MtaRule
+	excluded := parseExcludedRules(exclude)
+	for _, rule := range GetRules() {
+		if mtaRule, ok := rule.(MtaRule); ok && !excluded[rule.ID()] {
+			validations = append(validations, mtaRule)
+		}
 	}
-	if !strings.Contains(exclude, deprecatedOptsValidation) {
-		validations = append(validations, checkDeprecatedOpts)
-	}
-	if !strings.Contains(exclude, deployerConstrValidation) {
-		validations = append(validations, checkDeployerConstraints)
-	}
-	if !strings.Contains(exclude, metadataValidation) {
-		validations = append(validations, checkParamsAndPropertiesMetadata)
-	}
-	if !strings.Contains(exclude, ifNoSourceParamBoolValidation) {
-		validations = append(validations, ifNoSourceParamBool)
-	}
-
 	return validations
 }
 
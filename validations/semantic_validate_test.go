@@ -44,13 +44,13 @@ resources:
 		err := yaml.Unmarshal(mtaContent, &mtaStr)
 		Ω(err).Should(Succeed())
 		root, _ := getContentNode(mtaContent)
-		issues, _ := runSemanticValidations(&mtaStr, root, getTestPath("testproject"), "", true)
+		issues, _ := runSemanticValidations(&mtaStr, root, getTestPath("testproject"), "", true, nil)
 		Ω(len(issues)).Should(Equal(2))
 		Ω(issues[0].Msg).Should(Equal(`the "ui5app2" path of the "ui5app2" module does not exist`))
 		Ω(issues[0].Line).Should(Equal(14))
 		Ω(issues[1].Msg).Should(Equal(`the "test" resource name is already in use; a provided property set was found with the same name on line 11`))
 		Ω(issues[1].Line).Should(Equal(18))
-		issues, _ = runSemanticValidations(&mtaStr, root, getTestPath("testproject"), "paths,names", true)
+		issues, _ = runSemanticValidations(&mtaStr, root, getTestPath("testproject"), "paths,names", true, nil)
 		Ω(len(issues)).Should(Equal(0))
 	})
 
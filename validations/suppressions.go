@@ -0,0 +1,320 @@
+package validate
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Suppression comments disable semantic validation rules in a part of a descriptor:
+//
+//	# mta-lint-disable-next-line <rule>[, <rule>...]  disables the rules on the next line
+//	key: value # mta-lint-disable-line <rule>          disables the rules on the line of the comment
+//	# mta-lint-disable <rule>[, <rule>...]            disables the rules until a matching enable comment or the end of the file
+//	# mta-lint-enable <rule>[, <rule>...]             enables the rules again
+//
+// When no rule is written, the comment applies to all the rules.
+const (
+	disableNextLineDirective = "mta-lint-disable-next-line"
+	disableLineDirective     = "mta-lint-disable-line"
+	disableDirective         = "mta-lint-disable"
+	enableDirective          = "mta-lint-enable"
+
+	unusedSuppressionMsg     = `the "%s" comment does not suppress any issue of the "%s" rule`
+	unusedSuppressionAllMsg  = `the "%s" comment does not suppress any issue`
+	unknownSuppressedRuleMsg = `the "%s" rule in the "%s" comment is unknown`
+	unmatchedEnableMsg       = `the "%s" comment does not follow a "%s" comment of the "%s" rule`
+	unmatchedEnableAllMsg    = `the "%s" comment does not follow a "%s" comment`
+)
+
+// directives are checked in this order, so a directive is checked before the directives which are its prefixes
+var directives = []string{disableNextLineDirective, disableLineDirective, disableDirective, enableDirective}
+
+// suppressionComment is a suppression directive in a comment
+type suppressionComment struct {
+	directive string
+	// rules are the IDs of the rules in the directive; empty for all the rules
+	rules  []string
+	line   int
+	column int
+	// targetLine is the line on which the rules are disabled, for the line directives
+	targetLine int
+}
+
+// suppression disables a rule (or all the rules, if the rule is empty) between the start and end lines
+type suppression struct {
+	comment   *suppressionComment
+	rule      string
+	startLine int
+	endLine   int
+	used      bool
+}
+
+// applySuppressions removes the issues which are suppressed by the suppression comments in the descriptor,
+// and adds warnings on the suppression comments which don't suppress any issue of the rules which ran
+func applySuppressions(yamlContent []byte, ranRules map[string]bool, errIssues, warnIssues YamlValidationIssues) (YamlValidationIssues, YamlValidationIssues) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(yamlContent, &document); err != nil {
+		// The parse errors are reported by the validation
+		return errIssues, warnIssues
+	}
+	comments := getSuppressionComments(yamlContent, &document)
+	if len(comments) == 0 {
+		return errIssues, warnIssues
+	}
+
+	suppressions, commentIssues := getSuppressions(comments)
+	errIssues = filterSuppressedIssues(errIssues, suppressions)
+	warnIssues = filterSuppressedIssues(warnIssues, suppressions)
+
+	for _, s := range suppressions {
+		if s.used || (s.rule != "" && !ranRules[s.rule]) || (s.rule == "" && len(ranRules) == 0) {
+			continue
+		}
+		if s.rule == "" {
+			commentIssues = appendIssue(commentIssues, fmt.Sprintf(unusedSuppressionAllMsg, s.comment.directive), s.comment.line, s.comment.column)
+		} else {
+			commentIssues = appendIssue(commentIssues, fmt.Sprintf(unusedSuppressionMsg, s.comment.directive, s.rule), s.comment.line, s.comment.column)
+		}
+	}
+	return errIssues, append(warnIssues, commentIssues...)
+}
+
+// removeSuppressedIssues removes the issues which are suppressed by the suppression comments in the descriptor.
+// Unlike applySuppressions, it doesn't report issues on the suppression comments, which are reported by the validation of the descriptor.
+func removeSuppressedIssues(yamlContent []byte, errIssues, warnIssues YamlValidationIssues) (YamlValidationIssues, YamlValidationIssues) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(yamlContent, &document); err != nil {
+		return errIssues, warnIssues
+	}
+	suppressions, _ := getSuppressions(getSuppressionComments(yamlContent, &document))
+	return filterSuppressedIssues(errIssues, suppressions), filterSuppressedIssues(warnIssues, suppressions)
+}
+
+func filterSuppressedIssues(issues YamlValidationIssues, suppressions []*suppression) YamlValidationIssues {
+	var result YamlValidationIssues
+	for _, issue := range issues {
+		suppressed := false
+		for _, s := range suppressions {
+			if issue.Rule != "" && (s.rule == "" || s.rule == issue.Rule) && issue.Line >= s.startLine && issue.Line <= s.endLine {
+				s.used = true
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// getSuppressions returns the suppressions of the comments, and the issues on the comments
+func getSuppressions(comments []*suppressionComment) ([]*suppression, YamlValidationIssues) {
+	var suppressions []*suppression
+	var issues YamlValidationIssues
+	// open are the suppressions of the disable directives which are not enabled yet
+	var open []*suppression
+	for _, comment := range comments {
+		for _, rule := range comment.rules {
+			if _, ok := GetRule(rule); !ok {
+				issues = appendIssue(issues, fmt.Sprintf(unknownSuppressedRuleMsg, rule, comment.directive), comment.line, comment.column)
+			}
+		}
+		rules := comment.rules
+		if len(rules) == 0 {
+			rules = []string{""}
+		}
+
+		switch comment.directive {
+		case disableNextLineDirective, disableLineDirective:
+			for _, rule := range rules {
+				suppressions = append(suppressions, &suppression{comment, rule, comment.targetLine, comment.targetLine, false})
+			}
+		case disableDirective:
+			for _, rule := range rules {
+				s := &suppression{comment, rule, comment.line, int(^uint(0) >> 1), false}
+				suppressions = append(suppressions, s)
+				open = append(open, s)
+			}
+		case enableDirective:
+			for _, rule := range rules {
+				found := false
+				var stillOpen []*suppression
+				for _, s := range open {
+					if s.rule == rule || rule == "" {
+						s.endLine = comment.line
+						found = true
+					} else {
+						stillOpen = append(stillOpen, s)
+					}
+				}
+				open = stillOpen
+				if !found && rule == "" {
+					issues = appendIssue(issues, fmt.Sprintf(unmatchedEnableAllMsg, enableDirective, disableDirective), comment.line, comment.column)
+				} else if !found {
+					issues = appendIssue(issues, fmt.Sprintf(unmatchedEnableMsg, enableDirective, disableDirective, rule), comment.line, comment.column)
+				}
+			}
+		}
+	}
+	return suppressions, issues
+}
+
+// getSuppressionComments returns the suppression comments in the descriptor, ordered by their lines.
+// The comments are read from the text of the descriptor, because the nodes don't keep the lines of their comments.
+func getSuppressionComments(content []byte, document *yaml.Node) []*suppressionComment {
+	var comments []*suppressionComment
+	for _, comment := range getSourceComments(content) {
+		lineTarget := 0
+		if comment.afterValue {
+			lineTarget = comment.line
+		}
+		addSuppressionComment(&comments, comment.text, comment.line, comment.column, lineTarget)
+	}
+
+	// The next line of a comment is the next line which has a node
+	lines := make(map[int]bool)
+	collectNodeLines(document, lines)
+	var sortedLines []int
+	for line := range lines {
+		sortedLines = append(sortedLines, line)
+	}
+	sort.Ints(sortedLines)
+	for _, comment := range comments {
+		if comment.directive == disableNextLineDirective {
+			i := sort.SearchInts(sortedLines, comment.line+1)
+			if i < len(sortedLines) {
+				comment.targetLine = sortedLines[i]
+			}
+		}
+	}
+	return comments
+}
+
+// collectNodeLines collects the lines of the node and its content
+func collectNodeLines(node *yaml.Node, lines map[int]bool) {
+	if node.Kind != yaml.DocumentNode {
+		lines[node.Line] = true
+	}
+	for _, child := range node.Content {
+		collectNodeLines(child, lines)
+	}
+}
+
+// sourceComment is a comment in the text of a descriptor
+type sourceComment struct {
+	text   string
+	line   int
+	column int
+	// afterValue is true if the comment is written after a value on the same line
+	afterValue bool
+}
+
+// blockScalarIndicator matches the end of a line which starts a literal or folded block scalar
+var blockScalarIndicator = regexp.MustCompile(`(^|[\s:-])[|>][0-9+-]*$`)
+
+// getSourceComments returns the comments in the text of a descriptor, in the order of their lines.
+// The "#" characters in quoted scalars and in the content of block scalars don't start comments.
+func getSourceComments(content []byte) []sourceComment {
+	var comments []sourceComment
+	var quote byte
+	// blockIndent is the indentation which the lines of a block scalar exceed; -1 if the line is not in a block scalar
+	blockIndent := -1
+	for i, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if blockIndent >= 0 {
+			if trimmed == "" || len(line)-len(trimmed) > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		valueEnd := len(line)
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			if quote == '\'' {
+				if c == '\'' && j+1 < len(line) && line[j+1] == '\'' {
+					j++
+				} else if c == '\'' {
+					quote = 0
+				}
+				continue
+			}
+			if quote == '"' {
+				if c == '\\' {
+					j++
+				} else if c == '"' {
+					quote = 0
+				}
+				continue
+			}
+			atTokenStart := j == 0 || strings.IndexByte(" \t[{,", line[j-1]) >= 0
+			if c == '#' && atTokenStart {
+				valueEnd = j
+				comments = append(comments, sourceComment{line[j:], i + 1, j + 1, strings.TrimSpace(line[:j]) != ""})
+				break
+			}
+			if (c == '\'' || c == '"') && atTokenStart {
+				quote = c
+			}
+		}
+		if value := strings.TrimSpace(line[:valueEnd]); quote == 0 && blockScalarIndicator.MatchString(value) {
+			blockIndent = getBlockParentIndent(line)
+		}
+	}
+	return comments
+}
+
+// getBlockParentIndent returns the indentation of the key or sequence entry whose value is the block scalar
+// which starts on the line; the lines of the block scalar are indented more than it
+func getBlockParentIndent(line string) int {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if !strings.Contains(line, ":") {
+		return indent
+	}
+	// The key is after the sequence entries on the line
+	for strings.HasPrefix(line[indent:], "- ") {
+		indent += 2
+		indent += len(line[indent:]) - len(strings.TrimLeft(line[indent:], " "))
+	}
+	return indent
+}
+
+// addSuppressionComment adds the comment if it is a suppression directive.
+// The line target is the line of the node for a line comment, which is the target of a disable-line directive.
+func addSuppressionComment(comments *[]*suppressionComment, text string, line int, column int, lineTarget int) {
+	text = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text), "#"))
+	for _, directive := range directives {
+		if text != directive && !strings.HasPrefix(text, directive+" ") {
+			continue
+		}
+		if directive == disableLineDirective && lineTarget == 0 {
+			// A disable-line directive must be written after a value on the same line
+			return
+		}
+		comment := &suppressionComment{directive: directive, line: line, column: column, targetLine: lineTarget}
+		for _, rule := range strings.FieldsFunc(strings.TrimPrefix(text, directive), func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			comment.rules = append(comment.rules, rule)
+		}
+		*comments = append(*comments, comment)
+		return
+	}
+}
+
+// getRanRules returns the IDs of the rules which run on a descriptor with the excluded rules
+func getRanRules(exclude string, ext bool) map[string]bool {
+	ranRules := make(map[string]bool)
+	if ext {
+		for _, rule := range getExtSemanticValidations(exclude) {
+			ranRules[rule.ID()] = true
+		}
+	} else {
+		for _, rule := range getSemanticValidations(exclude) {
+			ranRules[rule.ID()] = true
+		}
+	}
+	return ranRules
+}
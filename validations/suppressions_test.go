@@ -0,0 +1,184 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("Suppressions", func() {
+	const exclude = "paths,emptyPath"
+
+	getIssues := func(mtaContent []byte) (YamlValidationIssues, YamlValidationIssues) {
+		mtaStr, err := mta.Unmarshal(mtaContent)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(mtaContent)
+		Ω(err).Should(Succeed())
+		errIssues, warnIssues := runSemanticValidations(mtaStr, root, getTestPath("testproject"), exclude, true, nil)
+		return applySuppressions(mtaContent, getRanRules(exclude, false), errIssues, warnIssues)
+	}
+
+	It("suppresses the issues of the rules in the suppression comments", func() {
+		errIssues, warnIssues := getIssues([]byte(`ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+
+modules:
+ - name: m1
+   type: html5
+   build-parameters:
+     # mta-lint-disable-next-line deprecatedOpts
+     npm-opts:
+       no-optional: null
+   properties:
+     a: ~{unknown/a} # mta-lint-disable-line required
+     b: ~{unknown/b}
+     # mta-lint-disable required, names
+     c: ~{unknown/c}
+     d: ~{unknown/d}
+     # mta-lint-enable required
+     e: ~{unknown/e}
+     # mta-lint-disable-next-line expressions, unknownRule
+     f: value
+# mta-lint-enable
+# mta-lint-enable paths
+`))
+		Ω(errIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: `the "b" property of the "m1" module is unresolved; the "unknown/b" property is not provided`, Line: 14, Column: 9, Rule: requiredValidation},
+			YamlValidationIssue{Msg: `the "e" property of the "m1" module is unresolved; the "unknown/e" property is not provided`, Line: 19, Column: 9, Rule: requiredValidation},
+		))
+		Ω(warnIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(unknownSuppressedRuleMsg, "unknownRule", disableNextLineDirective), Line: 20, Column: 6},
+			YamlValidationIssue{Msg: fmt.Sprintf(unmatchedEnableMsg, enableDirective, disableDirective, pathsValidation), Line: 23, Column: 1},
+			YamlValidationIssue{Msg: fmt.Sprintf(unusedSuppressionMsg, disableDirective, namesValidation), Line: 15, Column: 6},
+			YamlValidationIssue{Msg: fmt.Sprintf(unusedSuppressionMsg, disableNextLineDirective, expressionsValidation), Line: 20, Column: 6},
+		))
+	})
+
+	It("suppresses the issues of all the rules when the suppression comment doesn't have rules", func() {
+		errIssues, warnIssues := getIssues([]byte(`# mta-lint-disable
+
+ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+
+modules:
+ - name: m1
+   type: html5
+   properties:
+     a: ~{unknown/a}
+     b: ~{{unknown/b}}
+# mta-lint-enable
+resources:
+ - name: m1
+   # mta-lint-disable-next-line
+   type: org.cloudfoundry.user-provided-service
+`))
+		Ω(errIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: `the "m1" resource name is already in use; a module was found with the same name on line 8`, Line: 15, Column: 10, Rule: namesValidation},
+		))
+		Ω(warnIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(unusedSuppressionAllMsg, disableNextLineDirective), Line: 16, Column: 4},
+		))
+	})
+
+	It("reports an enable comment which doesn't follow a disable comment", func() {
+		_, warnIssues := getIssues([]byte(`ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+# mta-lint-enable
+modules:
+ - name: m1
+   type: html5
+`))
+		Ω(warnIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(unmatchedEnableAllMsg, enableDirective, disableDirective), Line: 4, Column: 1},
+		))
+	})
+
+	It("doesn't report unused suppressions of rules which didn't run", func() {
+		_, warnIssues := getIssues([]byte(`ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+modules:
+ - name: m1
+   type: html5
+   # mta-lint-disable-next-line paths
+   path: doesNotExist
+`))
+		Ω(warnIssues).Should(BeEmpty())
+	})
+
+	It("ignores the comments which are not suppression comments", func() {
+		_, warnIssues := getIssues([]byte(`ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+modules:
+ # mta-lint-disabled names
+ - name: m1 # mta-lint-disable-line-names
+   # mta-lint-disable-line names
+   type: html5
+`))
+		Ω(warnIssues).Should(BeEmpty())
+	})
+
+	It("suppresses the issues on the next node when the directive is separated from it or is in a longer comment", func() {
+		errIssues, warnIssues := getIssues([]byte(`ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+
+modules:
+ - name: m1
+   type: html5
+   properties:
+     # mta-lint-disable-next-line required
+
+     # The value of a is provided later.
+     a: ~{unknown/a}
+     b: ~{unknown/b}
+     # The value of c is provided later.
+     # mta-lint-disable-next-line required
+     # The value of d is not.
+     c: ~{unknown/c}
+     d: ~{unknown/d}
+`))
+		Ω(errIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: `the "b" property of the "m1" module is unresolved; the "unknown/b" property is not provided`, Line: 13, Column: 9, Rule: requiredValidation},
+			YamlValidationIssue{Msg: `the "d" property of the "m1" module is unresolved; the "unknown/d" property is not provided`, Line: 18, Column: 9, Rule: requiredValidation},
+		))
+		Ω(warnIssues).Should(BeEmpty())
+	})
+
+	It("doesn't read the suppression comments in quoted and block scalars", func() {
+		errIssues, _ := getIssues([]byte(`ID: mta
+_schema-version: '3.1'
+version: 0.0.1
+
+modules:
+ - name: m1
+   type: html5
+   properties:
+     text: |
+       # mta-lint-disable-next-line required
+     a: ~{unknown/a}
+     quoted: "x
+       # mta-lint-disable-next-line required"
+     b: ~{unknown/b}
+`))
+		Ω(errIssues).Should(HaveLen(2))
+	})
+
+	It("applies the suppression comments in the validation of the mta.yaml and mtaext files", func() {
+		mtaYamlPath := getTestPath("suppressionsProject", "mta.yaml")
+		mtaExtPath := getTestPath("suppressionsProject", "dev.mtaext")
+		result := Validate(mtaYamlPath, []string{mtaExtPath})
+		Ω(result[mtaYamlPath]).Should(BeEmpty())
+		Ω(result[mtaExtPath]).Should(ConsistOf(
+			FileValidationIssue{SeverityWarning, fmt.Sprintf(unusedSuppressionMsg, disableNextLineDirective, namesValidation), 10, 6, "", nil,
+				&IssueRange{IssuePosition{10, 6, 159}, IssuePosition{10, 40, 193}}},
+		))
+	})
+})
@@ -0,0 +1,195 @@
+package validate
+
+import (
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+const (
+	// TypesCatalogFileName is the name of the project types catalog file. It is discovered in the project folder, next to the mta.yaml file,
+	// and extends the types catalog which is embedded in the tool.
+	TypesCatalogFileName = ".mtatypes.yaml"
+	// TypesCatalogVersion is the version of the types catalog format
+	TypesCatalogVersion = 1
+
+	readTypesCatalogErrorMsg     = `could not read the %q types catalog file`
+	wrongTypesCatalogVersionMsg  = `the %d version of the %q types catalog file is not supported; expected version %d`
+	parseEmbeddedCatalogErrorMsg = `could not parse the embedded types catalog`
+)
+
+// TypesCatalog describes the parameters of the module types and the resource types
+type TypesCatalog struct {
+	// Version - the version of the catalog format. A project types catalog file without a version has the current version.
+	Version int `yaml:"version"`
+	// ModuleParameters - the parameters of all the module types in the catalog
+	ModuleParameters CatalogParameters `yaml:"module-parameters"`
+	// ModuleTypes - the module types
+	ModuleTypes []*CatalogType `yaml:"module-types"`
+	// ResourceParameters - the parameters of all the resource types in the catalog
+	ResourceParameters CatalogParameters `yaml:"resource-parameters"`
+	// ResourceTypes - the resource types
+	ResourceTypes []*CatalogType `yaml:"resource-types"`
+}
+
+// CatalogType describes the parameters of a module type or a resource type
+type CatalogType struct {
+	Name       string            `yaml:"name"`
+	Parameters CatalogParameters `yaml:"parameters"`
+	// Config describes the keys of the "config" parameter, which contains the service creation parameters of a resource
+	Config *CatalogParameters `yaml:"config"`
+}
+
+// CatalogParameters describes the keys of a map of parameters
+type CatalogParameters struct {
+	// Required - the keys which must be defined
+	Required []string `yaml:"required"`
+	// Allowed - the other supported keys. If it is not set, any key is supported; an empty list supports only the keys
+	// which are described in the other fields.
+	Allowed []string `yaml:"allowed"`
+	// Defaults - the values which the deployer uses for the keys which are not defined
+	Defaults map[string]interface{} `yaml:"defaults"`
+	// Deprecated - the deprecated keys, mapped to the keys which replace them; the replacement is empty if there is none
+	Deprecated map[string]string `yaml:"deprecated"`
+}
+
+// GetTypesCatalog returns the embedded types catalog, extended with the types catalog file in the project folder if it exists
+func GetTypesCatalog(projectPath string) (*TypesCatalog, error) {
+	catalog := &TypesCatalog{}
+	if err := yaml.Unmarshal(typesCatalogDef, catalog); err != nil {
+		return nil, errors.Wrap(err, parseEmbeddedCatalogErrorMsg)
+	}
+	catalogPath := filepath.Join(projectPath, TypesCatalogFileName)
+	if _, err := os.Stat(catalogPath); os.IsNotExist(err) {
+		return catalog, nil
+	}
+	projectCatalog, err := ReadTypesCatalog(catalogPath)
+	if err != nil {
+		return nil, err
+	}
+	catalog.extend(projectCatalog)
+	return catalog, nil
+}
+
+// ReadTypesCatalog reads a types catalog file
+func ReadTypesCatalog(catalogPath string) (*TypesCatalog, error) {
+	content, err := fs.ReadFile(catalogPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, readTypesCatalogErrorMsg, catalogPath)
+	}
+	catalog := &TypesCatalog{}
+	if err = yaml.Unmarshal(content, catalog); err != nil {
+		return nil, errors.Wrapf(err, readTypesCatalogErrorMsg, catalogPath)
+	}
+	if catalog.Version != 0 && catalog.Version != TypesCatalogVersion {
+		return nil, errors.Errorf(wrongTypesCatalogVersionMsg, catalog.Version, catalogPath, TypesCatalogVersion)
+	}
+	return catalog, nil
+}
+
+// GetModuleType returns the module type with the name, or nil if the catalog doesn't describe it
+func (c *TypesCatalog) GetModuleType(name string) *CatalogType {
+	return getCatalogType(c.ModuleTypes, name)
+}
+
+// GetResourceType returns the resource type with the name, or nil if the catalog doesn't describe it
+func (c *TypesCatalog) GetResourceType(name string) *CatalogType {
+	return getCatalogType(c.ResourceTypes, name)
+}
+
+func getCatalogType(types []*CatalogType, name string) *CatalogType {
+	for _, catalogType := range types {
+		if catalogType.Name == name {
+			return catalogType
+		}
+	}
+	return nil
+}
+
+// extend adds the parameters and the types of the other catalog to the catalog.
+// A type in the other catalog replaces the type with the same name.
+func (c *TypesCatalog) extend(other *TypesCatalog) {
+	c.ModuleParameters.extend(other.ModuleParameters)
+	c.ResourceParameters.extend(other.ResourceParameters)
+	c.ModuleTypes = extendCatalogTypes(c.ModuleTypes, other.ModuleTypes)
+	c.ResourceTypes = extendCatalogTypes(c.ResourceTypes, other.ResourceTypes)
+}
+
+func extendCatalogTypes(types []*CatalogType, otherTypes []*CatalogType) []*CatalogType {
+	for _, otherType := range otherTypes {
+		replaced := false
+		for i, catalogType := range types {
+			if catalogType.Name == otherType.Name {
+				types[i] = otherType
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			types = append(types, otherType)
+		}
+	}
+	return types
+}
+
+// extend adds the keys of the other parameters description to the parameters description
+func (p *CatalogParameters) extend(other CatalogParameters) {
+	p.Required = append(p.Required, other.Required...)
+	if other.Allowed != nil {
+		p.Allowed = append(p.Allowed, other.Allowed...)
+	}
+	for key, value := range other.Defaults {
+		if p.Defaults == nil {
+			p.Defaults = make(map[string]interface{})
+		}
+		p.Defaults[key] = value
+	}
+	for key, replacement := range other.Deprecated {
+		if p.Deprecated == nil {
+			p.Deprecated = make(map[string]string)
+		}
+		p.Deprecated[key] = replacement
+	}
+}
+
+// isSupported returns true if the key is described by the parameters or by the common parameters,
+// or if the parameters don't restrict the supported keys
+func (p *CatalogParameters) isSupported(key string, common CatalogParameters) bool {
+	if p.Allowed == nil {
+		return true
+	}
+	for _, params := range []*CatalogParameters{p, &common} {
+		if containsString(params.Required, key) || containsString(params.Allowed, key) {
+			return true
+		}
+		if _, ok := params.Defaults[key]; ok {
+			return true
+		}
+		if _, ok := params.Deprecated[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getDeprecation returns the key which replaces the deprecated key, and true if the key is deprecated
+func (p *CatalogParameters) getDeprecation(key string, common CatalogParameters) (string, bool) {
+	if replacement, ok := p.Deprecated[key]; ok {
+		return replacement, true
+	}
+	replacement, ok := common.Deprecated[key]
+	return replacement, ok
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
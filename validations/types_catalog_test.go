@@ -0,0 +1,62 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TypesCatalog", func() {
+	Describe("GetTypesCatalog", func() {
+		It("returns the embedded catalog when the project doesn't have a types catalog file", func() {
+			catalog, err := GetTypesCatalog(getTestPath("testproject"))
+			Ω(err).Should(Succeed())
+			Ω(catalog.Version).Should(Equal(TypesCatalogVersion))
+			Ω(catalog.GetResourceType("org.cloudfoundry.managed-service").Parameters.Required).Should(Equal([]string{"service", "service-plan"}))
+			Ω(catalog.GetResourceType("org.cloudfoundry.existing-service").Parameters.Required).Should(Equal([]string{"service-name"}))
+			Ω(catalog.GetResourceType("com.sap.xs.hdi-container").Parameters.Defaults).Should(Equal(map[string]interface{}{"service": "hana", "service-plan": "hdi-shared"}))
+			Ω(catalog.GetModuleType("nodejs")).ShouldNot(BeNil())
+			Ω(catalog.GetModuleType("unknown")).Should(BeNil())
+		})
+
+		It("extends the embedded catalog with the types catalog file of the project", func() {
+			catalog, err := GetTypesCatalog(getTestPath("typesProject"))
+			Ω(err).Should(Succeed())
+			Ω(catalog.ModuleParameters.Deprecated).Should(Equal(map[string]string{"keep-existing-routes": "keep-existing", "legacy-timeout": ""}))
+			Ω(catalog.ResourceParameters.Allowed).Should(ContainElement("audit-level"))
+			Ω(catalog.ResourceParameters.Allowed).Should(ContainElement("service-name"))
+			// The project type replaces the embedded type
+			Ω(catalog.GetResourceType("org.cloudfoundry.existing-service").Parameters.Allowed).Should(Equal([]string{"service-keys-prefix"}))
+			Ω(catalog.GetResourceType("com.acme.queue").Parameters.Required).Should(Equal([]string{"queue-name"}))
+			Ω(catalog.GetResourceType("org.cloudfoundry.managed-service")).ShouldNot(BeNil())
+		})
+
+		It("fails when the version of the types catalog file is not supported", func() {
+			catalogPath := getTestPath("typesProject", "badVersion", TypesCatalogFileName)
+			_, err := GetTypesCatalog(getTestPath("typesProject", "badVersion"))
+			Ω(err).Should(MatchError(fmt.Sprintf(wrongTypesCatalogVersionMsg, 2, catalogPath, TypesCatalogVersion)))
+		})
+	})
+
+	It("fails to read a types catalog file which doesn't exist", func() {
+		catalogPath := getTestPath("typesProject", "unknown.yaml")
+		_, err := ReadTypesCatalog(catalogPath)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(readTypesCatalogErrorMsg, catalogPath)))
+	})
+
+	var _ = DescribeTable("isSupported", func(params CatalogParameters, key string, expected bool) {
+		common := CatalogParameters{Allowed: []string{"service-name"}, Deprecated: map[string]string{"old": ""}}
+		Ω(params.isSupported(key, common)).Should(Equal(expected))
+	},
+		Entry("any key when the allowed keys are not set", CatalogParameters{}, "a", true),
+		Entry("required key", CatalogParameters{Allowed: []string{}, Required: []string{"a"}}, "a", true),
+		Entry("allowed key", CatalogParameters{Allowed: []string{"a"}}, "a", true),
+		Entry("key with a default", CatalogParameters{Allowed: []string{}, Defaults: map[string]interface{}{"a": 1}}, "a", true),
+		Entry("common allowed key", CatalogParameters{Allowed: []string{}}, "service-name", true),
+		Entry("common deprecated key", CatalogParameters{Allowed: []string{}}, "old", true),
+		Entry("unknown key", CatalogParameters{Allowed: []string{"b"}}, "a", false),
+	)
+})